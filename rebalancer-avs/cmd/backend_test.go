@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// fakeChain is an in-memory L2 backend. It satisfies bind.ContractBackend and
+// receiptBackend; calls are answered by callFn.
+type fakeChain struct {
+	mu       sync.Mutex
+	head     uint64
+	receipts map[common.Hash]*types.Receipt
+	sent     []*types.Transaction
+	callErr  error
+	callFn   func(msg ethereum.CallMsg, block *big.Int) ([]byte, error)
+
+	// baseFee, tipCap and gasEstimate override the defaults of 1, 1 gwei
+	// and 100000 gas when set.
+	baseFee     *big.Int
+	tipCap      *big.Int
+	gasEstimate uint64
+	estimateErr error
+
+	// chainID defaults to 31338; noCode makes CodeAt report no contract.
+	chainID *big.Int
+	noCode  bool
+
+	// blockTime gives the timestamp of block n, zero when unset. logs are
+	// served by FilterLogs up to the head.
+	blockTime func(n uint64) uint64
+	logs      []types.Log
+}
+
+func newFakeChain(head uint64) *fakeChain {
+	return &fakeChain{head: head, receipts: make(map[common.Hash]*types.Receipt)}
+}
+
+func (c *fakeChain) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.receipts[hash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return r, nil
+}
+
+func (c *fakeChain) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	if c.noCode {
+		return nil, nil
+	}
+	return []byte{0x01}, nil
+}
+
+func (c *fakeChain) ChainID(context.Context) (*big.Int, error) {
+	if c.chainID != nil {
+		return c.chainID, nil
+	}
+	return big.NewInt(31338), nil
+}
+
+func (c *fakeChain) CallContract(_ context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	if c.callFn != nil {
+		return c.callFn(msg, block)
+	}
+	return nil, c.callErr
+}
+
+func (c *fakeChain) BlockNumber(context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.head, nil
+}
+
+func (c *fakeChain) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := c.head
+	if number != nil {
+		if number.Uint64() > c.head {
+			return nil, ethereum.NotFound
+		}
+		n = number.Uint64()
+	}
+	baseFee := big.NewInt(1e9)
+	if c.baseFee != nil {
+		baseFee = c.baseFee
+	}
+	header := &types.Header{Number: new(big.Int).SetUint64(n), BaseFee: baseFee}
+	if c.blockTime != nil {
+		header.Time = c.blockTime(n)
+	}
+	return header, nil
+}
+
+func (c *fakeChain) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return c.CodeAt(ctx, account, nil)
+}
+
+func (c *fakeChain) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return uint64(len(c.sent)), nil
+}
+
+func (c *fakeChain) SuggestGasPrice(context.Context) (*big.Int, error) {
+	return big.NewInt(2e9), nil
+}
+
+func (c *fakeChain) SuggestGasTipCap(context.Context) (*big.Int, error) {
+	if c.tipCap != nil {
+		return c.tipCap, nil
+	}
+	return big.NewInt(1e9), nil
+}
+
+func (c *fakeChain) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error) {
+	if c.estimateErr != nil {
+		return 0, c.estimateErr
+	}
+	if c.gasEstimate != 0 {
+		return c.gasEstimate, nil
+	}
+	return 100000, nil
+}
+
+func (c *fakeChain) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, tx)
+	return nil
+}
+
+func (c *fakeChain) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var logs []types.Log
+	for _, l := range c.logs {
+		switch {
+		case l.BlockNumber > c.head,
+			q.FromBlock != nil && l.BlockNumber < q.FromBlock.Uint64(),
+			q.ToBlock != nil && l.BlockNumber > q.ToBlock.Uint64(),
+			len(q.Addresses) > 0 && !slices.Contains(q.Addresses, l.Address):
+			continue
+		}
+		matched := true
+		for i, topics := range q.Topics {
+			if len(topics) > 0 && (i >= len(l.Topics) || !slices.Contains(topics, l.Topics[i])) {
+				matched = false
+			}
+		}
+		if matched {
+			logs = append(logs, l)
+		}
+	}
+	return logs, nil
+}
+
+func (c *fakeChain) SubscribeFilterLogs(context.Context, ethereum.FilterQuery, chan<- types.Log) (ethereum.Subscription, error) {
+	return event.NewSubscription(func(<-chan struct{}) error { return nil }), nil
+}
+
+func (c *fakeChain) mine(r *types.Receipt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.receipts[r.TxHash] = r
+	if r.BlockNumber.Uint64() > c.head {
+		c.head = r.BlockNumber.Uint64()
+	}
+}
@@ -0,0 +1,687 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultPerformerConfigPath is read when PERFORMER_CONFIG is unset and
+	// the file exists.
+	defaultPerformerConfigPath = "config/performer.yaml"
+	// defaultPerformerPort and defaultPerformerTimeout configure the gRPC server.
+	defaultPerformerPort    = 8080
+	defaultPerformerTimeout = 5 * time.Second
+)
+
+// Performer profiles. Production profiles refuse to start without everything
+// needed to execute rebalances.
+const (
+	ProfileDevnet  = "devnet"
+	ProfileTestnet = "testnet"
+	ProfileMainnet = "mainnet"
+)
+
+// PerformerConfig is the performer's configuration file. The chains, avs,
+// eigenlayer and deployed_l1_contracts sections follow the devkit context
+// layout of config/contexts/devnet.yaml, so a context file can be used as is;
+// performer holds the settings specific to this performer.
+type PerformerConfig struct {
+	Version string        `yaml:"version"`
+	Context ConfigContext `yaml:"context"`
+
+	// path is the file the config was read from, empty without one.
+	path string
+}
+
+// ConfigContext is the context section of a devkit context file.
+type ConfigContext struct {
+	Name                string             `yaml:"name"`
+	Chains              ChainsConfig       `yaml:"chains"`
+	AVS                 AVSConfig          `yaml:"avs"`
+	EigenLayer          EigenLayerConfig   `yaml:"eigenlayer"`
+	DeployedL1Contracts []DeployedContract `yaml:"deployed_l1_contracts"`
+	Performer           PerformerSettings  `yaml:"performer"`
+}
+
+// ChainsConfig lists the L1 and L2 chains.
+type ChainsConfig struct {
+	L1 ChainConfig `yaml:"l1"`
+	L2 ChainConfig `yaml:"l2"`
+}
+
+// ChainConfig is a chain and the RPC endpoint the performer reads it through.
+type ChainConfig struct {
+	ChainId uint64 `yaml:"chain_id"`
+	RpcURL  string `yaml:"rpc_url"`
+}
+
+// AVSConfig identifies the AVS.
+type AVSConfig struct {
+	Address string `yaml:"address"`
+}
+
+// EigenLayerConfig holds the EigenLayer core contracts the performer reads.
+type EigenLayerConfig struct {
+	L2 struct {
+		TaskMailbox              string `yaml:"task_mailbox"`
+		ECDSACertificateVerifier string `yaml:"ecdsa_certificate_verifier"`
+	} `yaml:"l2"`
+}
+
+// DeployedContract is an entry of deployed_l1_contracts.
+type DeployedContract struct {
+	Name    string `yaml:"name"`
+	Address string `yaml:"address"`
+}
+
+// PerformerSettings is the performer section.
+type PerformerSettings struct {
+	// Profile is devnet, testnet or mainnet. Defaults to devnet.
+	Profile            string        `yaml:"profile"`
+	Port               int           `yaml:"port"`
+	Timeout            time.Duration `yaml:"timeout"`
+	HookAddress        string        `yaml:"hook_address"`
+	PoolManagerAddress string        `yaml:"pool_manager_address"`
+	PoolsConfig        string        `yaml:"pools_config"`
+	TaskStorePath      string        `yaml:"task_store_path"`
+	// OperatorKeystore is a Web3 secret-storage file with the operator's ECDSA
+	// key. Its password is read from OperatorKeystorePasswordFile, or prompted
+	// for when that is empty.
+	OperatorKeystore             string `yaml:"operator_keystore"`
+	OperatorKeystorePasswordFile string `yaml:"operator_keystore_password_file"`
+	// OperatorPrivateKey is a raw hex key, only accepted with
+	// AllowInsecurePrivateKey.
+	OperatorPrivateKey      string `yaml:"operator_private_key"`
+	AllowInsecurePrivateKey bool   `yaml:"allow_insecure_private_key"`
+	// RemoteSignerURL is a Web3Signer endpoint holding the key of
+	// RemoteSignerAddress, used instead of a local key; the TLS settings are
+	// PEM files.
+	RemoteSignerURL        string `yaml:"remote_signer_url"`
+	RemoteSignerAddress    string `yaml:"remote_signer_address"`
+	RemoteSignerCACert     string `yaml:"remote_signer_ca_cert"`
+	RemoteSignerClientCert string `yaml:"remote_signer_client_cert"`
+	RemoteSignerClientKey  string `yaml:"remote_signer_client_key"`
+	// PKCS11Module is a PKCS#11 library whose token holds the operator's
+	// secp256k1 key pair, labelled PKCS11KeyLabel. The user PIN is read from
+	// PKCS11PinFile, or prompted for when that is empty.
+	PKCS11Module     string `yaml:"pkcs11_module"`
+	PKCS11TokenLabel string `yaml:"pkcs11_token_label"`
+	PKCS11KeyLabel   string `yaml:"pkcs11_key_label"`
+	PKCS11PinFile    string `yaml:"pkcs11_pin_file"`
+	// CertificationTimeout bounds the wait for the TaskMailbox to verify a
+	// result, polled every CertificationPollInterval.
+	CertificationTimeout      time.Duration `yaml:"certification_timeout"`
+	CertificationPollInterval time.Duration `yaml:"certification_poll_interval"`
+
+	// TaskMaxAge is how old a task's timestamp may be.
+	TaskMaxAge time.Duration `yaml:"task_max_age"`
+	// TickRounding aligns tick shifts to the tick spacing: nearest,
+	// toward_zero or away_from_zero.
+	TickRounding string `yaml:"tick_rounding"`
+	// YieldToleranceBps is how far the reported yield may be from the yield
+	// source's.
+	YieldToleranceBps *uint64 `yaml:"yield_tolerance_bps"`
+	// ReceiptConfirmations is how many blocks a rebalance needs before it is
+	// confirmed, waited for at most ReceiptTimeout.
+	ReceiptConfirmations uint64        `yaml:"receipt_confirmations"`
+	ReceiptTimeout       time.Duration `yaml:"receipt_timeout"`
+
+	// The gas policy, see GasPolicy. Fees are decimal gwei and MaxTxCostWei
+	// is wei; an empty amount is not capped.
+	GasLimitMultiplier  float64       `yaml:"gas_limit_multiplier"`
+	MaxFeePerGasGwei    string        `yaml:"max_fee_per_gas_gwei"`
+	PriorityFeeStrategy string        `yaml:"priority_fee_strategy"`
+	PriorityFeeGwei     string        `yaml:"priority_fee_gwei"`
+	MaxPriorityFeeGwei  string        `yaml:"max_priority_fee_gwei"`
+	MaxTxCostWei        string        `yaml:"max_tx_cost_wei"`
+	StuckTxTimeout      time.Duration `yaml:"stuck_tx_timeout"`
+	FeeBumpPercent      uint64        `yaml:"fee_bump_percent"`
+	MaxFeeBumps         *uint64       `yaml:"max_fee_bumps"`
+}
+
+// ConfigError lists every problem found in a configuration.
+type ConfigError struct {
+	Path     string
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	source := e.Path
+	if source == "" {
+		source = "environment"
+	}
+	return fmt.Sprintf("invalid performer config (%s):\n  - %s", source, strings.Join(e.Problems, "\n  - "))
+}
+
+func (e *ConfigError) add(format string, args ...interface{}) {
+	e.Problems = append(e.Problems, fmt.Sprintf(format, args...))
+}
+
+// LoadPerformerConfig reads path, or defaultPerformerConfigPath if path is
+// empty and that file exists, applies environment overrides and defaults. The
+// result still has to be validated.
+func LoadPerformerConfig(path string) (*PerformerConfig, error) {
+	cfg := &PerformerConfig{}
+	if path == "" {
+		if _, err := os.Stat(defaultPerformerConfigPath); err == nil {
+			path = defaultPerformerConfigPath
+		}
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read performer config: %w", err)
+		}
+		if err := cfg.unmarshal(data); err != nil {
+			return nil, &ConfigError{Path: path, Problems: []string{err.Error()}}
+		}
+		cfg.path = path
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		err.Path = cfg.path
+		return nil, err
+	}
+	cfg.applyDefaults()
+	return cfg, nil
+}
+
+func (c *PerformerConfig) unmarshal(data []byte) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	if err := root.Decode(c); err != nil {
+		return err
+	}
+	// The other sections are shared with devkit and may carry keys the
+	// performer does not read; its own section must not.
+	if performer := lookupNode(&root, "context", "performer"); performer != nil {
+		if unknown := unknownKeys(performer, reflect.TypeOf(PerformerSettings{})); len(unknown) > 0 {
+			return fmt.Errorf("unknown keys in context.performer: %s", strings.Join(unknown, ", "))
+		}
+	}
+	return nil
+}
+
+// lookupNode follows keys through nested mappings.
+func lookupNode(node *yaml.Node, keys ...string) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// unknownKeys returns the keys of mapping that no yaml tag of t names.
+func unknownKeys(mapping *yaml.Node, t reflect.Type) []string {
+	if mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	known := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		known[name] = true
+	}
+	var unknown []string
+	for i := 0; i < len(mapping.Content); i += 2 {
+		if key := mapping.Content[i].Value; !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	return unknown
+}
+
+// configEnv maps environment variables onto string settings. They override
+// the file.
+var configEnv = []struct {
+	key   string
+	field func(*PerformerConfig) *string
+}{
+	{"PERFORMER_PROFILE", func(c *PerformerConfig) *string { return &c.Context.Performer.Profile }},
+	{"L1_RPC_URL", func(c *PerformerConfig) *string { return &c.Context.Chains.L1.RpcURL }},
+	{"L2_RPC_URL", func(c *PerformerConfig) *string { return &c.Context.Chains.L2.RpcURL }},
+	{"TASK_MAILBOX_ADDRESS", func(c *PerformerConfig) *string { return &c.Context.EigenLayer.L2.TaskMailbox }},
+	{"HOOK_ADDRESS", func(c *PerformerConfig) *string { return &c.Context.Performer.HookAddress }},
+	{"POOL_MANAGER_ADDRESS", func(c *PerformerConfig) *string { return &c.Context.Performer.PoolManagerAddress }},
+	{"POOLS_CONFIG", func(c *PerformerConfig) *string { return &c.Context.Performer.PoolsConfig }},
+	{"TASK_STORE_PATH", func(c *PerformerConfig) *string { return &c.Context.Performer.TaskStorePath }},
+	{"OPERATOR_KEYSTORE", func(c *PerformerConfig) *string { return &c.Context.Performer.OperatorKeystore }},
+	{"OPERATOR_KEYSTORE_PASSWORD_FILE", func(c *PerformerConfig) *string { return &c.Context.Performer.OperatorKeystorePasswordFile }},
+	{"OPERATOR_PRIVATE_KEY", func(c *PerformerConfig) *string { return &c.Context.Performer.OperatorPrivateKey }},
+	{"REMOTE_SIGNER_URL", func(c *PerformerConfig) *string { return &c.Context.Performer.RemoteSignerURL }},
+	{"REMOTE_SIGNER_ADDRESS", func(c *PerformerConfig) *string { return &c.Context.Performer.RemoteSignerAddress }},
+	{"REMOTE_SIGNER_CA_CERT", func(c *PerformerConfig) *string { return &c.Context.Performer.RemoteSignerCACert }},
+	{"REMOTE_SIGNER_CLIENT_CERT", func(c *PerformerConfig) *string { return &c.Context.Performer.RemoteSignerClientCert }},
+	{"REMOTE_SIGNER_CLIENT_KEY", func(c *PerformerConfig) *string { return &c.Context.Performer.RemoteSignerClientKey }},
+	{"PKCS11_MODULE", func(c *PerformerConfig) *string { return &c.Context.Performer.PKCS11Module }},
+	{"PKCS11_TOKEN_LABEL", func(c *PerformerConfig) *string { return &c.Context.Performer.PKCS11TokenLabel }},
+	{"PKCS11_KEY_LABEL", func(c *PerformerConfig) *string { return &c.Context.Performer.PKCS11KeyLabel }},
+	{"PKCS11_PIN_FILE", func(c *PerformerConfig) *string { return &c.Context.Performer.PKCS11PinFile }},
+	{"TICK_ROUNDING", func(c *PerformerConfig) *string { return &c.Context.Performer.TickRounding }},
+	{"MAX_FEE_PER_GAS_GWEI", func(c *PerformerConfig) *string { return &c.Context.Performer.MaxFeePerGasGwei }},
+	{"PRIORITY_FEE_STRATEGY", func(c *PerformerConfig) *string { return &c.Context.Performer.PriorityFeeStrategy }},
+	{"PRIORITY_FEE_GWEI", func(c *PerformerConfig) *string { return &c.Context.Performer.PriorityFeeGwei }},
+	{"MAX_PRIORITY_FEE_GWEI", func(c *PerformerConfig) *string { return &c.Context.Performer.MaxPriorityFeeGwei }},
+	{"MAX_TX_COST_WEI", func(c *PerformerConfig) *string { return &c.Context.Performer.MaxTxCostWei }},
+}
+
+func (c *PerformerConfig) applyEnv() *ConfigError {
+	for _, e := range configEnv {
+		if v := os.Getenv(e.key); v != "" {
+			*e.field(c) = v
+		}
+	}
+
+	problems := &ConfigError{}
+	parseUint := func(key string, bits int, set func(uint64)) {
+		v := os.Getenv(key)
+		if v == "" {
+			return
+		}
+		n, err := strconv.ParseUint(v, 10, bits)
+		if err != nil {
+			problems.add("%s: invalid value %q", key, v)
+			return
+		}
+		set(n)
+	}
+	p := &c.Context.Performer
+	parseUint("L1_CHAIN_ID", 64, func(n uint64) { c.Context.Chains.L1.ChainId = n })
+	parseUint("L2_CHAIN_ID", 64, func(n uint64) { c.Context.Chains.L2.ChainId = n })
+	parseUint("PERFORMER_PORT", 16, func(n uint64) { p.Port = int(n) })
+	parseUint("YIELD_TOLERANCE_BPS", 64, func(n uint64) { p.YieldToleranceBps = &n })
+	parseUint("RECEIPT_CONFIRMATIONS", 64, func(n uint64) { p.ReceiptConfirmations = n })
+	parseUint("FEE_BUMP_PERCENT", 64, func(n uint64) { p.FeeBumpPercent = n })
+	parseUint("MAX_FEE_BUMPS", 64, func(n uint64) { p.MaxFeeBumps = &n })
+	if v := os.Getenv("GAS_LIMIT_MULTIPLIER"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			problems.add("GAS_LIMIT_MULTIPLIER: invalid value %q", v)
+		} else {
+			p.GasLimitMultiplier = f
+		}
+	}
+	if v := os.Getenv("ALLOW_INSECURE_PRIVATE_KEY"); v != "" {
+		allow, err := strconv.ParseBool(v)
+		if err != nil {
+			problems.add("ALLOW_INSECURE_PRIVATE_KEY: invalid value %q", v)
+		} else {
+			c.Context.Performer.AllowInsecurePrivateKey = allow
+		}
+	}
+	for _, d := range []struct {
+		key   string
+		field *time.Duration
+	}{
+		{"PERFORMER_TIMEOUT", &p.Timeout},
+		{"CERTIFICATION_TIMEOUT", &p.CertificationTimeout},
+		{"CERTIFICATION_POLL_INTERVAL", &p.CertificationPollInterval},
+		{"TASK_MAX_AGE", &p.TaskMaxAge},
+		{"RECEIPT_TIMEOUT", &p.ReceiptTimeout},
+		{"STUCK_TX_TIMEOUT", &p.StuckTxTimeout},
+	} {
+		v := os.Getenv(d.key)
+		if v == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			problems.add("%s: invalid duration %q", d.key, v)
+			continue
+		}
+		*d.field = parsed
+	}
+
+	if len(problems.Problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+func (c *PerformerConfig) applyDefaults() {
+	p := &c.Context.Performer
+	if p.Profile == "" {
+		p.Profile = ProfileDevnet
+	}
+	if p.Port == 0 {
+		p.Port = defaultPerformerPort
+	}
+	if p.Timeout == 0 {
+		p.Timeout = defaultPerformerTimeout
+	}
+	if p.PoolsConfig == "" {
+		p.PoolsConfig = defaultPoolsConfigPath
+	}
+	if p.TaskStorePath == "" {
+		p.TaskStorePath = defaultTaskStorePath
+	}
+	for _, d := range []struct {
+		field *time.Duration
+		def   time.Duration
+	}{
+		{&p.CertificationTimeout, defaultCertificationTimeout},
+		{&p.CertificationPollInterval, defaultCertificationPollInterval},
+		{&p.TaskMaxAge, defaultMaxTaskAge},
+		{&p.ReceiptTimeout, defaultReceiptTimeout},
+		{&p.StuckTxTimeout, defaultStuckTxTimeout},
+	} {
+		if *d.field == 0 {
+			*d.field = d.def
+		}
+	}
+	if p.TickRounding == "" {
+		p.TickRounding = string(defaultTickRounding)
+	}
+	if p.YieldToleranceBps == nil {
+		tolerance := defaultYieldToleranceBps
+		p.YieldToleranceBps = &tolerance
+	}
+	if p.ReceiptConfirmations == 0 {
+		p.ReceiptConfirmations = defaultConfirmations
+	}
+	if p.GasLimitMultiplier == 0 {
+		p.GasLimitMultiplier = defaultGasLimitMultiplier
+	}
+	if p.PriorityFeeStrategy == "" {
+		p.PriorityFeeStrategy = PriorityFeeNode
+	}
+	if p.FeeBumpPercent == 0 {
+		p.FeeBumpPercent = defaultFeeBumpPercent
+	}
+	if p.MaxFeeBumps == nil {
+		bumps := defaultMaxFeeBumps
+		p.MaxFeeBumps = &bumps
+	}
+}
+
+// Production reports whether the profile must not run with missing pieces.
+func (c *PerformerConfig) Production() bool {
+	return c.Context.Performer.Profile != ProfileDevnet
+}
+
+// Missing lists the settings the performer needs to execute rebalances and
+// certify them that are not set.
+func (c *PerformerConfig) Missing() []string {
+	p := &c.Context.Performer
+	var missing []string
+	check := func(name, value string) {
+		if value == "" {
+			missing = append(missing, name)
+		}
+	}
+	check("chains.l2.rpc_url (L2_RPC_URL)", c.Context.Chains.L2.RpcURL)
+	if c.Context.Chains.L2.ChainId == 0 {
+		missing = append(missing, "chains.l2.chain_id (L2_CHAIN_ID)")
+	}
+	check("performer.hook_address (HOOK_ADDRESS)", p.HookAddress)
+	check("performer.operator_keystore, remote_signer_url, pkcs11_module or operator_private_key with allow_insecure_private_key "+
+		"(OPERATOR_KEYSTORE, REMOTE_SIGNER_URL, PKCS11_MODULE, OPERATOR_PRIVATE_KEY with ALLOW_INSECURE_PRIVATE_KEY=true)",
+		p.OperatorKeystore+p.OperatorPrivateKey+p.RemoteSignerURL+p.PKCS11Module)
+	check("eigenlayer.l2.task_mailbox (TASK_MAILBOX_ADDRESS)", c.Context.EigenLayer.L2.TaskMailbox)
+	return missing
+}
+
+// Validate checks every setting. In a production profile, missing settings
+// are errors too. It returns a *ConfigError.
+func (c *PerformerConfig) Validate() error {
+	problems := &ConfigError{Path: c.path}
+	p := &c.Context.Performer
+
+	switch p.Profile {
+	case ProfileDevnet, ProfileTestnet, ProfileMainnet:
+	default:
+		problems.add("performer.profile: must be %s, %s or %s, got %q", ProfileDevnet, ProfileTestnet, ProfileMainnet, p.Profile)
+	}
+	if p.Port <= 0 || p.Port > 65535 {
+		problems.add("performer.port: must be between 1 and 65535, got %d", p.Port)
+	}
+	for _, d := range []struct {
+		field string
+		value time.Duration
+	}{
+		{"performer.timeout", p.Timeout},
+		{"performer.certification_timeout", p.CertificationTimeout},
+		{"performer.certification_poll_interval", p.CertificationPollInterval},
+		{"performer.task_max_age", p.TaskMaxAge},
+		{"performer.receipt_timeout", p.ReceiptTimeout},
+		{"performer.stuck_tx_timeout", p.StuckTxTimeout},
+	} {
+		if d.value <= 0 {
+			problems.add("%s: must be positive, got %s", d.field, d.value)
+		}
+	}
+	if p.CertificationPollInterval > 0 && p.CertificationPollInterval >= p.CertificationTimeout {
+		problems.add("performer.certification_poll_interval: must be shorter than certification_timeout (%s), got %s",
+			p.CertificationTimeout, p.CertificationPollInterval)
+	}
+	if _, err := ParseTickRounding(p.TickRounding); err != nil {
+		problems.add("performer.tick_rounding: %v", err)
+	}
+	c.validateGas(problems)
+
+	for _, chain := range []struct {
+		name string
+		cfg  ChainConfig
+	}{{"l1", c.Context.Chains.L1}, {"l2", c.Context.Chains.L2}} {
+		if chain.cfg.RpcURL == "" {
+			continue
+		}
+		u, err := url.Parse(chain.cfg.RpcURL)
+		if err != nil || u.Host == "" {
+			problems.add("chains.%s.rpc_url: invalid URL %q", chain.name, chain.cfg.RpcURL)
+			continue
+		}
+		switch u.Scheme {
+		case "http", "https", "ws", "wss":
+		default:
+			problems.add("chains.%s.rpc_url: unsupported scheme %q", chain.name, u.Scheme)
+		}
+	}
+
+	for _, a := range []struct{ field, value string }{
+		{"avs.address", c.Context.AVS.Address},
+		{"eigenlayer.l2.task_mailbox", c.Context.EigenLayer.L2.TaskMailbox},
+		{"eigenlayer.l2.ecdsa_certificate_verifier", c.Context.EigenLayer.L2.ECDSACertificateVerifier},
+		{"performer.hook_address", p.HookAddress},
+		{"performer.pool_manager_address", p.PoolManagerAddress},
+	} {
+		if a.value == "" {
+			continue
+		}
+		if _, err := parseAddress(a.field, a.value, false); err != nil {
+			problems.add("%v", err)
+		}
+	}
+	for _, d := range c.Context.DeployedL1Contracts {
+		if _, err := parseAddress("deployed_l1_contracts."+d.Name, d.Address, false); err != nil {
+			problems.add("%v", err)
+		}
+	}
+
+	keySources := 0
+	for _, v := range []string{p.OperatorKeystore, p.OperatorPrivateKey, p.RemoteSignerURL, p.PKCS11Module} {
+		if v != "" {
+			keySources++
+		}
+	}
+	switch {
+	case keySources > 1:
+		problems.add("performer: set one of operator_keystore, operator_private_key, remote_signer_url or pkcs11_module, not several")
+	case p.RemoteSignerURL != "":
+		c.validateRemoteSigner(problems)
+	case p.PKCS11Module != "":
+		if _, err := os.Stat(p.PKCS11Module); err != nil {
+			problems.add("performer.pkcs11_module: %v", err)
+		}
+		if p.PKCS11TokenLabel == "" {
+			problems.add("performer.pkcs11_token_label: required with pkcs11_module")
+		}
+		if p.PKCS11KeyLabel == "" {
+			problems.add("performer.pkcs11_key_label: required with pkcs11_module")
+		}
+		if p.PKCS11PinFile != "" {
+			if _, err := os.Stat(p.PKCS11PinFile); err != nil {
+				problems.add("performer.pkcs11_pin_file: %v", err)
+			}
+		}
+	case p.OperatorKeystore != "":
+		if _, err := os.Stat(p.OperatorKeystore); err != nil {
+			problems.add("performer.operator_keystore: %v", err)
+		}
+		if p.OperatorKeystorePasswordFile != "" {
+			if _, err := os.Stat(p.OperatorKeystorePasswordFile); err != nil {
+				problems.add("performer.operator_keystore_password_file: %v", err)
+			}
+		}
+	case p.OperatorPrivateKey != "":
+		// The key itself never goes into the report.
+		if !p.AllowInsecurePrivateKey {
+			problems.add("performer.operator_private_key: %v", ErrInsecureKeyNotAllowed)
+		} else if _, err := crypto.HexToECDSA(strings.TrimPrefix(p.OperatorPrivateKey, "0x")); err != nil {
+			problems.add("performer.operator_private_key: not a valid secp256k1 key")
+		}
+	}
+
+	if p.HookAddress != "" {
+		if hook, err := parseAddress("performer.hook_address", p.HookAddress, false); err == nil {
+			if _, err := LoadPoolRegistry(p.PoolsConfig, hook); err != nil {
+				problems.add("performer.pools_config: %v", err)
+			}
+		}
+	}
+
+	if c.Production() {
+		for _, m := range c.Missing() {
+			problems.add("%s: required in the %s profile", m, p.Profile)
+		}
+	}
+
+	if len(problems.Problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+func (c *PerformerConfig) validateGas(problems *ConfigError) {
+	p := &c.Context.Performer
+	if p.GasLimitMultiplier < 1 {
+		problems.add("performer.gas_limit_multiplier: must be at least 1, got %g", p.GasLimitMultiplier)
+	}
+	for _, a := range []struct {
+		field, value string
+		parse        func(string) (*big.Int, error)
+	}{
+		{"max_fee_per_gas_gwei", p.MaxFeePerGasGwei, parseGwei},
+		{"priority_fee_gwei", p.PriorityFeeGwei, parseGwei},
+		{"max_priority_fee_gwei", p.MaxPriorityFeeGwei, parseGwei},
+		{"max_tx_cost_wei", p.MaxTxCostWei, parseWei},
+	} {
+		if a.value == "" {
+			continue
+		}
+		if _, err := a.parse(a.value); err != nil {
+			problems.add("performer.%s: %v", a.field, err)
+		}
+	}
+	switch p.PriorityFeeStrategy {
+	case PriorityFeeNode:
+	case PriorityFeeFixed:
+		if p.PriorityFeeGwei == "" {
+			problems.add("performer.priority_fee_gwei: required with priority_fee_strategy %s", PriorityFeeFixed)
+		}
+	default:
+		problems.add("performer.priority_fee_strategy: must be %s or %s, got %q", PriorityFeeNode, PriorityFeeFixed, p.PriorityFeeStrategy)
+	}
+	if p.FeeBumpPercent < minFeeBumpPercent {
+		problems.add("performer.fee_bump_percent: must be at least %d, the node's replacement minimum, got %d", minFeeBumpPercent, p.FeeBumpPercent)
+	}
+}
+
+func (c *PerformerConfig) validateRemoteSigner(problems *ConfigError) {
+	p := &c.Context.Performer
+	if u, err := url.Parse(p.RemoteSignerURL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		problems.add("performer.remote_signer_url: invalid http(s) URL %q", p.RemoteSignerURL)
+	} else if u.Scheme == "http" && c.Production() {
+		problems.add("performer.remote_signer_url: must use https in the %s profile", p.Profile)
+	}
+	if p.RemoteSignerAddress == "" {
+		problems.add("performer.remote_signer_address: required with remote_signer_url")
+	} else if _, err := parseAddress("performer.remote_signer_address", p.RemoteSignerAddress, false); err != nil {
+		problems.add("%v", err)
+	}
+	if (p.RemoteSignerClientCert == "") != (p.RemoteSignerClientKey == "") {
+		problems.add("performer: remote_signer_client_cert and remote_signer_client_key must be set together")
+	}
+	for _, f := range []struct{ field, path string }{
+		{"remote_signer_ca_cert", p.RemoteSignerCACert},
+		{"remote_signer_client_cert", p.RemoteSignerClientCert},
+		{"remote_signer_client_key", p.RemoteSignerClientKey},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			problems.add("performer.%s: %v", f.field, err)
+		}
+	}
+}
+
+// validateConfigCommand implements `performer validate-config [path]`. It
+// prints a report to out and returns the process exit code.
+func validateConfigCommand(args []string, out io.Writer) int {
+	path := os.Getenv("PERFORMER_CONFIG")
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	cfg, err := LoadPerformerConfig(path)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		fmt.Fprintln(out, err)
+		var cfgErr *ConfigError
+		if !errors.As(err, &cfgErr) {
+			return 2
+		}
+		return 1
+	}
+
+	source := cfg.path
+	if source == "" {
+		source = "environment only"
+	}
+	fmt.Fprintf(out, "performer config OK (%s, profile %s)\n", source, cfg.Context.Performer.Profile)
+	for _, m := range cfg.Missing() {
+		fmt.Fprintf(out, "  warning: %s not set\n", m)
+	}
+	return 0
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testOperatorKey = "0x47e179ec197488593b187f80a00eb0da91f1b9d0b13f8733639f19c30a34926a"
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "performer.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func Test_LoadPerformerConfigDevnetContext(t *testing.T) {
+	cfg, err := LoadPerformerConfig("../config/contexts/devnet.yaml")
+	if err != nil {
+		t.Fatalf("LoadPerformerConfig failed: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	if cfg.Context.Chains.L2.ChainId != 31338 || cfg.Context.Chains.L2.RpcURL != "http://localhost:9545" {
+		t.Errorf("l2 chain = %+v", cfg.Context.Chains.L2)
+	}
+	if got := cfg.Context.EigenLayer.L2.TaskMailbox; got != "0xB99CC53e8db7018f557606C2a5B066527bF96b26" {
+		t.Errorf("task mailbox = %s", got)
+	}
+	p := cfg.Context.Performer
+	if p.Profile != ProfileDevnet || p.Port != defaultPerformerPort || p.Timeout != defaultPerformerTimeout {
+		t.Errorf("performer defaults = %+v", p)
+	}
+}
+
+func Test_PerformerConfigSample(t *testing.T) {
+	// Paths in the sample are relative to the performer's directory.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd failed: %v", err)
+	}
+	if err := os.Chdir(".."); err != nil {
+		t.Fatalf("Chdir failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	var out bytes.Buffer
+	if code := validateConfigCommand([]string{"config/performer.yaml"}, &out); code != 0 {
+		t.Fatalf("validate-config exited %d:\n%s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "profile devnet") || !strings.Contains(out.String(), "HOOK_ADDRESS") {
+		t.Errorf("unexpected report:\n%s", out.String())
+	}
+}
+
+func Test_PerformerConfigEnvOverrides(t *testing.T) {
+	path := writeConfig(t, `
+context:
+  chains:
+    l2:
+      chain_id: 1
+      rpc_url: "http://l2.example:8545"
+  performer:
+    port: 8080
+`)
+	t.Setenv("L2_RPC_URL", "wss://override.example")
+	t.Setenv("L2_CHAIN_ID", "31338")
+	t.Setenv("PERFORMER_PORT", "9090")
+	t.Setenv("PERFORMER_TIMEOUT", "30s")
+	t.Setenv("CERTIFICATION_TIMEOUT", "90s")
+	t.Setenv("YIELD_TOLERANCE_BPS", "0")
+	t.Setenv("TICK_ROUNDING", "toward_zero")
+	t.Setenv("MAX_FEE_PER_GAS_GWEI", "1.5")
+	t.Setenv("MAX_FEE_BUMPS", "0")
+
+	cfg, err := LoadPerformerConfig(path)
+	if err != nil {
+		t.Fatalf("LoadPerformerConfig failed: %v", err)
+	}
+	if cfg.Context.Chains.L2.RpcURL != "wss://override.example" || cfg.Context.Chains.L2.ChainId != 31338 {
+		t.Errorf("l2 chain = %+v", cfg.Context.Chains.L2)
+	}
+	if cfg.Context.Performer.Port != 9090 || cfg.Context.Performer.Timeout != 30*time.Second {
+		t.Errorf("server = %d, %s", cfg.Context.Performer.Port, cfg.Context.Performer.Timeout)
+	}
+	p := &cfg.Context.Performer
+	if p.CertificationTimeout != 90*time.Second || *p.YieldToleranceBps != 0 || p.TickRounding != string(RoundTowardZero) {
+		t.Errorf("tuning = %s, %d, %s", p.CertificationTimeout, *p.YieldToleranceBps, p.TickRounding)
+	}
+	gas := loadGasPolicy(p)
+	if gas.MaxFeePerGas == nil || gas.MaxFeePerGas.Int64() != 1_500_000_000 || gas.MaxFeeBumps != 0 {
+		t.Errorf("gas policy = %+v", gas)
+	}
+	// Unset knobs get their defaults.
+	if p.TaskMaxAge != defaultMaxTaskAge || p.ReceiptConfirmations != defaultConfirmations || gas.GasLimitMultiplier != defaultGasLimitMultiplier {
+		t.Errorf("defaults = %s, %d, %g", p.TaskMaxAge, p.ReceiptConfirmations, gas.GasLimitMultiplier)
+	}
+
+	t.Setenv("PERFORMER_PORT", "eighty")
+	t.Setenv("RECEIPT_TIMEOUT", "soon")
+	_, err = LoadPerformerConfig(path)
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) || !strings.Contains(err.Error(), "PERFORMER_PORT") || !strings.Contains(err.Error(), "RECEIPT_TIMEOUT") {
+		t.Errorf("expected a PERFORMER_PORT and RECEIPT_TIMEOUT ConfigError, got %v", err)
+	}
+}
+
+func Test_PerformerConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr []string
+	}{
+		{
+			name: "devnet with missing pieces",
+			config: `
+context:
+  performer:
+    profile: devnet
+`,
+		},
+		{
+			name: "production with missing pieces",
+			config: `
+context:
+  performer:
+    profile: mainnet
+`,
+			wantErr: []string{"L2_RPC_URL", "L2_CHAIN_ID", "HOOK_ADDRESS", "OPERATOR_KEYSTORE", "OPERATOR_PRIVATE_KEY with ALLOW_INSECURE_PRIVATE_KEY", "TASK_MAILBOX_ADDRESS"},
+		},
+		{
+			name: "production complete",
+			config: `
+context:
+  chains:
+    l2:
+      chain_id: 31338
+      rpc_url: "http://localhost:9545"
+  eigenlayer:
+    l2:
+      task_mailbox: "0xB99CC53e8db7018f557606C2a5B066527bF96b26"
+  performer:
+    profile: testnet
+    hook_address: "0x4444444444444444444444444444444444440AC0"
+    pools_config: "../config/pools.yaml"
+    operator_keystore: "../keystores/operator2.ecdsa.keystore.json"
+`,
+		},
+		{
+			name: "raw key without the insecure flag",
+			config: `
+context:
+  performer:
+    operator_private_key: "` + testOperatorKey + `"
+`,
+			wantErr: []string{"allow_insecure_private_key"},
+		},
+		{
+			name: "raw key with the insecure flag",
+			config: `
+context:
+  performer:
+    operator_private_key: "` + testOperatorKey + `"
+    allow_insecure_private_key: true
+`,
+		},
+		{
+			name: "keystore and raw key",
+			config: `
+context:
+  performer:
+    operator_keystore: "../keystores/operator2.ecdsa.keystore.json"
+    operator_private_key: "` + testOperatorKey + `"
+`,
+			wantErr: []string{"not several"},
+		},
+		{
+			name: "production with a remote signer",
+			config: `
+context:
+  chains:
+    l2:
+      chain_id: 31338
+      rpc_url: "http://localhost:9545"
+  eigenlayer:
+    l2:
+      task_mailbox: "0xB99CC53e8db7018f557606C2a5B066527bF96b26"
+  performer:
+    profile: testnet
+    hook_address: "0x4444444444444444444444444444444444440AC0"
+    pools_config: "../config/pools.yaml"
+    remote_signer_url: "https://web3signer.internal:9000"
+    remote_signer_address: "0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65"
+`,
+		},
+		{
+			name: "invalid remote signer",
+			config: `
+context:
+  performer:
+    profile: testnet
+    remote_signer_url: "http://web3signer.internal:9000"
+    remote_signer_client_cert: "../keystores/missing.pem"
+`,
+			wantErr: []string{
+				"remote_signer_url: must use https",
+				"remote_signer_address: required",
+				"must be set together",
+				"performer.remote_signer_client_cert:",
+			},
+		},
+		{
+			name: "invalid pkcs11",
+			config: `
+context:
+  performer:
+    pkcs11_module: "../keystores/missing.so"
+    pkcs11_pin_file: "../keystores/missing.pin"
+`,
+			wantErr: []string{
+				"performer.pkcs11_module:",
+				"pkcs11_token_label: required",
+				"pkcs11_key_label: required",
+				"performer.pkcs11_pin_file:",
+			},
+		},
+		{
+			name: "pkcs11 and keystore",
+			config: `
+context:
+  performer:
+    operator_keystore: "../keystores/operator2.ecdsa.keystore.json"
+    pkcs11_module: "/usr/lib/softhsm/libsofthsm2.so"
+    pkcs11_token_label: "performer"
+    pkcs11_key_label: "operator"
+`,
+			wantErr: []string{"not several"},
+		},
+		{
+			name: "remote signer and keystore",
+			config: `
+context:
+  performer:
+    operator_keystore: "../keystores/operator2.ecdsa.keystore.json"
+    remote_signer_url: "https://web3signer.internal:9000"
+    remote_signer_address: "0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65"
+`,
+			wantErr: []string{"not several"},
+		},
+		{
+			name: "missing keystore",
+			config: `
+context:
+  performer:
+    operator_keystore: "../keystores/missing.json"
+    operator_keystore_password_file: "../keystores/missing.password"
+`,
+			wantErr: []string{"performer.operator_keystore:", "performer.operator_keystore_password_file:"},
+		},
+		{
+			name: "invalid values",
+			config: `
+context:
+  chains:
+    l1:
+      rpc_url: "localhost:8545"
+  avs:
+    address: "0x1234"
+  performer:
+    profile: staging
+    port: 70000
+    operator_private_key: "0xnot-a-key"
+    allow_insecure_private_key: true
+`,
+			wantErr: []string{"chains.l1.rpc_url", "avs.address", "performer.profile", "performer.port", "performer.operator_private_key"},
+		},
+		{
+			name: "invalid tuning",
+			config: `
+context:
+  performer:
+    task_max_age: -1m
+    certification_timeout: 10s
+    certification_poll_interval: 30s
+    tick_rounding: up
+    gas_limit_multiplier: 0.5
+    max_fee_per_gas_gwei: "1.0000000001"
+    max_tx_cost_wei: "-1"
+    priority_fee_strategy: fixed
+    fee_bump_percent: 5
+`,
+			wantErr: []string{
+				"performer.task_max_age: must be positive",
+				"performer.certification_poll_interval: must be shorter",
+				"performer.tick_rounding",
+				"performer.gas_limit_multiplier",
+				"performer.max_fee_per_gas_gwei",
+				"performer.max_tx_cost_wei",
+				"performer.priority_fee_gwei: required",
+				"performer.fee_bump_percent",
+			},
+		},
+		{
+			name: "unknown performer key",
+			config: `
+context:
+  performer:
+    hook_adress: "0x4444444444444444444444444444444444440AC0"
+`,
+			wantErr: []string{"unknown keys in context.performer: hook_adress"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadPerformerConfig(writeConfig(t, tt.config))
+			if err == nil {
+				err = cfg.Validate()
+			}
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var cfgErr *ConfigError
+			if !errors.As(err, &cfgErr) {
+				t.Fatalf("expected *ConfigError, got %v", err)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error does not mention %q:\n%v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func Test_PerformerConfigErrorHidesKey(t *testing.T) {
+	key := strings.Repeat("ab", 31) + "zz"
+	cfg, err := LoadPerformerConfig(writeConfig(t, "context:\n  performer:\n    allow_insecure_private_key: true\n    operator_private_key: \""+key+"\"\n"))
+	if err != nil {
+		t.Fatalf("LoadPerformerConfig failed: %v", err)
+	}
+	err = cfg.Validate()
+	if err == nil || strings.Contains(err.Error(), key) {
+		t.Errorf("validation error leaks the key or is missing: %v", err)
+	}
+}
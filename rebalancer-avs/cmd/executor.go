@@ -0,0 +1,372 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/taskresult"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultCertificationTimeout bounds how long the executor waits for the
+	// TaskMailbox to verify a result.
+	defaultCertificationTimeout = 10 * time.Minute
+	// defaultCertificationPollInterval is the TaskMailbox polling period.
+	defaultCertificationPollInterval = 5 * time.Second
+)
+
+// TaskStatus mirrors ITaskMailboxTypes.TaskStatus.
+type TaskStatus uint8
+
+const (
+	TaskStatusNone TaskStatus = iota
+	TaskStatusCreated
+	TaskStatusVerified
+	TaskStatusExpired
+)
+
+func (s TaskStatus) String() string {
+	switch s {
+	case TaskStatusNone:
+		return "none"
+	case TaskStatusCreated:
+		return "created"
+	case TaskStatusVerified:
+		return "verified"
+	case TaskStatusExpired:
+		return "expired"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(s))
+	}
+}
+
+var (
+	// ErrTaskExpired is returned when the TaskMailbox expires a task before
+	// its result is verified.
+	ErrTaskExpired = errors.New("task expired before its result was certified")
+	// ErrCertificationTimeout is returned when a result is not verified in time.
+	ErrCertificationTimeout = errors.New("timed out waiting for result certification")
+	// ErrResultTaskMismatch is returned when a result answers a different task.
+	ErrResultTaskMismatch = errors.New("result is for a different task")
+)
+
+var taskMailboxABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[
+		{"type": "function", "name": "getTaskStatus", "stateMutability": "view",
+		 "inputs": [{"name": "taskHash", "type": "bytes32"}], "outputs": [{"name": "", "type": "uint8"}]},
+		{"type": "function", "name": "getTaskResult", "stateMutability": "view",
+		 "inputs": [{"name": "taskHash", "type": "bytes32"}], "outputs": [{"name": "", "type": "bytes"}]}
+	]`))
+	if err != nil {
+		panic(fmt.Errorf("failed to parse TaskMailbox ABI: %w", err))
+	}
+	return parsed
+}()
+
+// TaskMailbox reads task status and certified results from the TaskMailbox.
+type TaskMailbox struct {
+	caller  bind.ContractCaller
+	address common.Address
+}
+
+// NewTaskMailbox returns a reader for the TaskMailbox at address.
+func NewTaskMailbox(caller bind.ContractCaller, address common.Address) *TaskMailbox {
+	return &TaskMailbox{caller: caller, address: address}
+}
+
+func (m *TaskMailbox) call(ctx context.Context, method string, taskHash common.Hash) ([]interface{}, error) {
+	input, err := taskMailboxABI.Pack(method, taskHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+	output, err := m.caller.CallContract(ctx, ethereum.CallMsg{To: &m.address, Data: input}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s on TaskMailbox %s failed: %w", method, m.address.Hex(), err)
+	}
+	values, err := taskMailboxABI.Unpack(method, output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s: %w", method, err)
+	}
+	return values, nil
+}
+
+// Status returns the task's status.
+func (m *TaskMailbox) Status(ctx context.Context, taskHash common.Hash) (TaskStatus, error) {
+	values, err := m.call(ctx, "getTaskStatus", taskHash)
+	if err != nil {
+		return TaskStatusNone, err
+	}
+	return TaskStatus(values[0].(uint8)), nil
+}
+
+// Result returns the task's certified result.
+func (m *TaskMailbox) Result(ctx context.Context, taskHash common.Hash) ([]byte, error) {
+	values, err := m.call(ctx, "getTaskResult", taskHash)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].([]byte), nil
+}
+
+// ExecutionTask is what HandleTask hands to the executor: the task and the
+// result this operator computed for it.
+type ExecutionTask struct {
+	TaskId []byte
+	// Result is the encoded taskresult.Result HandleTask returned.
+	Result []byte
+}
+
+// Executor carries task results to the chain. HandleTask only computes a
+// deterministic result and submits it; execution happens after HandleTask has
+// returned, once the result is certified.
+type Executor interface {
+	// Submit schedules the task for execution and returns immediately.
+	Submit(task *ExecutionTask) error
+}
+
+// HookExecutor executes results by calling executeRebalance on the hook.
+//
+// The hook accepts executeRebalance only from its avsServiceManager, so only
+// the operator whose key is that address executes; every other operator
+// computes results only. There is no failover to another operator.
+//
+// With a TaskMailbox it waits until the task is verified and executes the
+// certified result, not its own. Without one it executes its own result as
+// soon as the task is accepted.
+type HookExecutor struct {
+	logger       *zap.Logger
+	tw           *TaskWorker
+	mailbox      *TaskMailbox
+	timeout      time.Duration
+	pollInterval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewHookExecutor returns an executor that uses tw's chain clients, key and
+// gas policy. mailbox may be nil.
+func NewHookExecutor(logger *zap.Logger, tw *TaskWorker, mailbox *TaskMailbox, timeout, pollInterval time.Duration) *HookExecutor {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &HookExecutor{
+		logger:       logger,
+		tw:           tw,
+		mailbox:      mailbox,
+		timeout:      timeout,
+		pollInterval: pollInterval,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// Submit starts executing task in the background.
+func (e *HookExecutor) Submit(task *ExecutionTask) error {
+	if err := e.ctx.Err(); err != nil {
+		return fmt.Errorf("executor is closed: %w", err)
+	}
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		if _, err := e.execute(e.ctx, task); err != nil {
+			e.logger.Error("❌ Failed to execute rebalance on hook",
+				zap.String("taskId", string(task.TaskId)),
+				zap.Bool("retryable", IsRetryable(err)),
+				zap.Error(err),
+			)
+			e.tw.failTask(string(task.TaskId), err)
+		}
+	}()
+	return nil
+}
+
+// Resume picks up the tasks the performer left unfinished when it last
+// stopped. Tasks with a result but no transaction are executed, since a
+// redelivery only returns the stored result; tasks whose transaction was sent
+// but not confirmed resume receipt tracking.
+func (e *HookExecutor) Resume() {
+	planned, err := e.tw.store.Unexecuted()
+	if err != nil {
+		e.logger.Error("Failed to read unexecuted tasks", zap.Error(err))
+	}
+	for _, task := range planned {
+		e.logger.Sugar().Infow("🔁 Resuming execution",
+			"taskId", task.TaskId,
+			"state", task.State,
+		)
+		if err := e.Submit(&ExecutionTask{TaskId: []byte(task.TaskId), Result: task.Result}); err != nil {
+			e.logger.Error("Failed to resume execution", zap.String("taskId", task.TaskId), zap.Error(err))
+		}
+	}
+
+	tasks, err := e.tw.store.Unconfirmed()
+	if err != nil {
+		e.logger.Error("Failed to read unconfirmed tasks", zap.Error(err))
+		return
+	}
+	for _, task := range tasks {
+		e.wg.Add(1)
+		go func(task *StoredTask) {
+			defer e.wg.Done()
+			outcome, err := e.tw.resumeTask(e.ctx, e.tw.l2Client, task)
+			if err != nil {
+				e.logger.Error("❌ Resumed rebalance did not settle",
+					zap.String("taskId", task.TaskId),
+					zap.Error(err),
+				)
+				e.tw.failTask(task.TaskId, err)
+				return
+			}
+			e.logger.Sugar().Infow("✅ Resumed rebalance confirmed",
+				"taskId", task.TaskId,
+				"txHash", outcome.TxHash.Hex(),
+				"blockNumber", outcome.BlockNumber,
+			)
+		}(task)
+	}
+}
+
+// Close cancels pending executions and waits for them to return.
+func (e *HookExecutor) Close() {
+	e.cancel()
+	e.wg.Wait()
+}
+
+// execute waits for certification when a mailbox is configured and executes
+// the result. It returns a nil outcome when there is nothing to execute, and
+// records the task as skipped when this operator will never execute it.
+func (e *HookExecutor) execute(ctx context.Context, task *ExecutionTask) (*RebalanceOutcome, error) {
+	taskHash := TaskHash(task.TaskId)
+
+	if stored, err := e.tw.store.Get(task.TaskId); err != nil {
+		return nil, fmt.Errorf("failed to read task store: %w", err)
+	} else if stored != nil && len(stored.RawTxs) > 0 {
+		e.logger.Sugar().Infow("Task already has a transaction, not executing again",
+			"taskId", string(task.TaskId),
+			"state", stored.State,
+		)
+		return nil, nil
+	}
+
+	encoded := task.Result
+	if e.mailbox != nil {
+		certified, err := e.awaitCertified(ctx, taskHash)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(certified, task.Result) {
+			e.logger.Sugar().Warnw("⚠️  Certified result differs from local result, executing the certified one",
+				"taskId", string(task.TaskId),
+				"taskHash", taskHash.Hex(),
+			)
+		}
+		encoded = certified
+	}
+
+	result, err := taskresult.Decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if result.TaskId != taskHash {
+		return nil, fmt.Errorf("%w: task %s, result %s", ErrResultTaskMismatch, taskHash.Hex(), result.TaskId.Hex())
+	}
+
+	pool, ok := e.tw.pools.Lookup(result.PoolId)
+	if !ok {
+		return nil, &UnknownPoolError{PoolId: result.PoolId}
+	}
+
+	manager, err := e.tw.hook.AvsServiceManager(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avsServiceManager: %w", err)
+	}
+	if self := e.tw.signer.Address(); self != manager {
+		e.logger.Sugar().Infow("Hook does not accept this operator as executor, computing results only",
+			"taskId", string(task.TaskId),
+			"self", self.Hex(),
+			"avsServiceManager", manager.Hex(),
+		)
+		e.tw.skipTask(string(task.TaskId), skipNotExecutor)
+		return nil, nil
+	}
+
+	// Re-plan against the current positions; they may have changed while the
+	// result was being certified.
+	plan, err := e.tw.planRebalance(ctx, pool, result.TickShift, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.Worthwhile() {
+		e.logger.Sugar().Warnw("⚠️  Skipping hook execution (plan rebalances no positions)",
+			"taskId", string(task.TaskId),
+			"tickShift", result.TickShift,
+			"positions", len(plan.Positions),
+		)
+		e.tw.skipTask(string(task.TaskId), skipNotWorthwhile)
+		return nil, nil
+	}
+
+	outcome, err := e.tw.executeRebalanceOnHook(ctx, string(task.TaskId), pool, plan, result.PositionsRebalanced)
+	if err != nil {
+		if errors.Is(err, ErrOnlyAvsOperator) {
+			e.tw.reportMisconfiguredServiceManager(e.tw.signer.Address(), err)
+		}
+		return nil, fmt.Errorf("rebalance failed: %w", err)
+	}
+	e.logger.Sugar().Infow("✅ Rebalance executed successfully on hook!",
+		"taskId", string(task.TaskId),
+		"txHash", outcome.TxHash.Hex(),
+		"blockNumber", outcome.BlockNumber,
+		"positionsRebalanced", outcome.PositionsRebalanced,
+		"positionsPlanned", result.PositionsRebalanced,
+	)
+	return outcome, nil
+}
+
+// awaitCertified polls the TaskMailbox until the task is verified and returns
+// the certified result.
+func (e *HookExecutor) awaitCertified(ctx context.Context, taskHash common.Hash) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(e.pollInterval)
+	defer ticker.Stop()
+
+	e.logger.Sugar().Infow("⏳ Waiting for result certification",
+		"taskHash", taskHash.Hex(),
+		"mailbox", e.mailbox.address.Hex(),
+		"timeout", e.timeout,
+	)
+	for {
+		status, err := e.mailbox.Status(ctx, taskHash)
+		if err != nil {
+			e.logger.Warn("Failed to read task status, retrying", zap.Error(err))
+		}
+		switch status {
+		case TaskStatusVerified:
+			e.logger.Sugar().Infow("📜 Task result certified", "taskHash", taskHash.Hex())
+			return e.mailbox.Result(ctx, taskHash)
+		case TaskStatusExpired:
+			return nil, fmt.Errorf("%w: %s", ErrTaskExpired, taskHash.Hex())
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("%w: %s after %s", ErrCertificationTimeout, taskHash.Hex(), e.timeout)
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
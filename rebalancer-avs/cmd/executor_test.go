@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/signer"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/taskresult"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+var testMailbox = common.HexToAddress("0xB99CC53e8db7018f557606C2a5B066527bF96b26")
+
+// mailboxChain serves getTaskStatus from statuses, one per call, repeating
+// the last one, and getTaskResult from result.
+func mailboxChain(t *testing.T, statuses []TaskStatus, result []byte) *fakeChain {
+	t.Helper()
+
+	var mu sync.Mutex
+	chain := newFakeChain(10)
+	chain.callFn = func(msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+		if *msg.To != testMailbox {
+			t.Errorf("called %s, want TaskMailbox", msg.To.Hex())
+		}
+		method, err := taskMailboxABI.MethodById(msg.Data[:4])
+		if err != nil {
+			t.Fatalf("unknown selector %x", msg.Data[:4])
+		}
+		switch method.Name {
+		case "getTaskStatus":
+			mu.Lock()
+			status := statuses[0]
+			if len(statuses) > 1 {
+				statuses = statuses[1:]
+			}
+			mu.Unlock()
+			return method.Outputs.Pack(uint8(status))
+		default:
+			return method.Outputs.Pack(result)
+		}
+	}
+	return chain
+}
+
+func testExecutor(chain *fakeChain, timeout time.Duration) *HookExecutor {
+	var mailbox *TaskMailbox
+	if chain != nil {
+		mailbox = NewTaskMailbox(chain, testMailbox)
+	}
+	return NewHookExecutor(zap.NewNop(), &TaskWorker{logger: zap.NewNop()}, mailbox, timeout, time.Millisecond)
+}
+
+func Test_AwaitCertified(t *testing.T) {
+	taskHash := common.Hash{0x01}
+	certified := []byte("certified result")
+
+	t.Run("verified", func(t *testing.T) {
+		e := testExecutor(mailboxChain(t, []TaskStatus{TaskStatusCreated, TaskStatusCreated, TaskStatusVerified}, certified), time.Second)
+		got, err := e.awaitCertified(context.Background(), taskHash)
+		if err != nil {
+			t.Fatalf("awaitCertified failed: %v", err)
+		}
+		if string(got) != string(certified) {
+			t.Errorf("result = %q, want %q", got, certified)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		e := testExecutor(mailboxChain(t, []TaskStatus{TaskStatusCreated, TaskStatusExpired}, nil), time.Second)
+		if _, err := e.awaitCertified(context.Background(), taskHash); !errors.Is(err, ErrTaskExpired) {
+			t.Fatalf("expected ErrTaskExpired, got %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		e := testExecutor(mailboxChain(t, []TaskStatus{TaskStatusCreated}, nil), 20*time.Millisecond)
+		if _, err := e.awaitCertified(context.Background(), taskHash); !errors.Is(err, ErrCertificationTimeout) {
+			t.Fatalf("expected ErrCertificationTimeout, got %v", err)
+		}
+	})
+}
+
+func Test_ExecuteRejectsResultForOtherTask(t *testing.T) {
+	taskId := []byte("task-1")
+	other, err := taskresult.Encode(&taskresult.Result{TaskId: TaskHash([]byte("task-2")), TickShift: 60})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	t.Run("local result", func(t *testing.T) {
+		_, err := testExecutor(nil, time.Second).execute(context.Background(), &ExecutionTask{TaskId: taskId, Result: other})
+		if !errors.Is(err, ErrResultTaskMismatch) {
+			t.Fatalf("expected ErrResultTaskMismatch, got %v", err)
+		}
+	})
+
+	t.Run("certified result", func(t *testing.T) {
+		local, err := taskresult.Encode(&taskresult.Result{TaskId: TaskHash(taskId), TickShift: 60})
+		if err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+		e := testExecutor(mailboxChain(t, []TaskStatus{TaskStatusVerified}, other), time.Second)
+		_, err = e.execute(context.Background(), &ExecutionTask{TaskId: taskId, Result: local})
+		if !errors.Is(err, ErrResultTaskMismatch) {
+			t.Fatalf("expected ErrResultTaskMismatch, got %v", err)
+		}
+	})
+}
+
+func Test_HookExecutorSubmitAfterClose(t *testing.T) {
+	e := testExecutor(nil, time.Second)
+	e.Close()
+	if err := e.Submit(&ExecutionTask{TaskId: []byte("task-1")}); err == nil {
+		t.Fatal("Submit succeeded on a closed executor")
+	}
+}
+
+func Test_ResumeExecutesPlannedTasks(t *testing.T) {
+	e := testExecutor(nil, time.Second)
+	e.tw.store = openTestTaskStore(t, filepath.Join(t.TempDir(), "tasks.db"))
+
+	other, err := taskresult.Encode(&taskresult.Result{TaskId: TaskHash([]byte("task-2")), TickShift: 60})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	e.tw.transition("task-1", StateSimulated, func(st *StoredTask) {
+		st.Result = other
+		st.ExpectedPositions = 1
+	})
+	e.tw.transition("task-idle", StatePlanned, func(st *StoredTask) { st.Result = other })
+
+	e.Resume()
+	e.Close()
+
+	// The resumed task reached execute, which rejects the result.
+	stored, err := e.tw.store.Get([]byte("task-1"))
+	if err != nil || stored.State != StateFailed || !strings.Contains(stored.Error, ErrResultTaskMismatch.Error()) {
+		t.Fatalf("stored task = (%+v, %v), want failed on the result mismatch", stored, err)
+	}
+	if stored.Answered() {
+		t.Error("a task that failed before sending a transaction must be handled again on redelivery")
+	}
+	if idle, _ := e.tw.store.Get([]byte("task-idle")); idle.State != StatePlanned {
+		t.Errorf("task without positions to move = %s, want planned", idle.State)
+	}
+}
+
+func Test_ExecuteSkipsUnauthorizedOperator(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	chain := newFakeChain(10)
+	chain.callFn = func(msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+		return testHookABI.Methods["avsServiceManager"].Outputs.Pack(common.Address{0x01})
+	}
+	hook, err := lstrebalancehook.NewLSTrebalanceHook(testHookAddress, chain)
+	if err != nil {
+		t.Fatalf("failed to bind hook: %v", err)
+	}
+
+	e := testExecutor(nil, time.Second)
+	e.tw.hook, e.tw.hookAddress = hook, testHookAddress
+	e.tw.signer = signer.NewLocal(key)
+	e.tw.pools = testPoolRegistry(t)
+	e.tw.store = openTestTaskStore(t, filepath.Join(t.TempDir(), "tasks.db"))
+
+	taskId := []byte("task-1")
+	result, err := taskresult.Encode(&taskresult.Result{TaskId: TaskHash(taskId), PoolId: testPoolId(t, e.tw.pools), TickShift: 60})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	e.tw.transition(string(taskId), StatePlanned, func(st *StoredTask) {
+		st.Result = result
+		st.ExpectedPositions = 1
+	})
+	outcome, err := e.execute(context.Background(), &ExecutionTask{TaskId: taskId, Result: result})
+	if outcome != nil || err != nil {
+		t.Fatalf("execute = (%+v, %v), want nothing executed", outcome, err)
+	}
+	if len(chain.sent) != 0 {
+		t.Errorf("sent %d transactions from an operator the hook does not accept", len(chain.sent))
+	}
+
+	// The task is skipped for good, so a restart does not execute it again.
+	stored, err := e.tw.store.Get(taskId)
+	if err != nil || stored.State != StateSkipped || stored.SkipReason != skipNotExecutor {
+		t.Fatalf("stored task = (%+v, %v), want skipped as not the executor", stored, err)
+	}
+	if unexecuted, err := e.tw.store.Unexecuted(); err != nil || len(unexecuted) != 0 {
+		t.Errorf("Unexecuted = (%v, %v), want none", unexecuted, err)
+	}
+	if !stored.Answered() {
+		t.Error("a redelivered skipped task must get its stored result back")
+	}
+}
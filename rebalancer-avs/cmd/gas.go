@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// defaultGasLimitMultiplier is applied to eth_estimateGas
+	// (performer.gas_limit_multiplier).
+	defaultGasLimitMultiplier = 1.2
+
+	// baseFeeHeadroom is how many base fees the fee cap covers, so the
+	// transaction stays includable while the base fee rises for a few blocks.
+	baseFeeHeadroom = 2
+
+	// PriorityFeeNode uses the node's eth_maxPriorityFeePerGas suggestion.
+	PriorityFeeNode = "node"
+	// PriorityFeeFixed always tips PRIORITY_FEE_GWEI.
+	PriorityFeeFixed = "fixed"
+)
+
+// ErrNoBaseFee is returned when the L2 head has no base fee, i.e. the chain
+// does not support EIP-1559 transactions.
+var ErrNoBaseFee = errors.New("latest L2 header has no base fee")
+
+// GasPolicy bounds what the operator is willing to pay for a rebalance.
+// Nil caps are not enforced.
+type GasPolicy struct {
+	// GasLimitMultiplier is the safety margin applied to the gas estimate.
+	GasLimitMultiplier float64
+	// MaxFeePerGas caps the EIP-1559 fee cap.
+	MaxFeePerGas *big.Int
+	// PriorityFeeStrategy is PriorityFeeNode or PriorityFeeFixed.
+	PriorityFeeStrategy string
+	// PriorityFee is the tip used by PriorityFeeFixed.
+	PriorityFee *big.Int
+	// MaxPriorityFee caps the tip suggested by the node.
+	MaxPriorityFee *big.Int
+	// MaxTxCost is the absolute ceiling on gasLimit * feeCap for any transaction.
+	MaxTxCost *big.Int
+
+	// StuckTxTimeout is how long a transaction may stay pending before it is
+	// replaced with bumped fees.
+	StuckTxTimeout time.Duration
+	// FeeBumpPercent is how much each replacement raises both fees.
+	FeeBumpPercent uint64
+	// MaxFeeBumps is how many replacements are sent per rebalance; 0 disables
+	// replacement.
+	MaxFeeBumps uint64
+}
+
+// DefaultGasPolicy returns the policy used when no gas settings are configured.
+func DefaultGasPolicy() GasPolicy {
+	return GasPolicy{
+		GasLimitMultiplier:  defaultGasLimitMultiplier,
+		PriorityFeeStrategy: PriorityFeeNode,
+		StuckTxTimeout:      defaultStuckTxTimeout,
+		FeeBumpPercent:      defaultFeeBumpPercent,
+		MaxFeeBumps:         defaultMaxFeeBumps,
+	}
+}
+
+// loadGasPolicy builds the gas policy from validated settings.
+func loadGasPolicy(settings *PerformerSettings) GasPolicy {
+	p := DefaultGasPolicy()
+	p.GasLimitMultiplier = settings.GasLimitMultiplier
+	p.PriorityFeeStrategy = settings.PriorityFeeStrategy
+	p.StuckTxTimeout = settings.StuckTxTimeout
+	p.FeeBumpPercent = settings.FeeBumpPercent
+	if settings.MaxFeeBumps != nil {
+		p.MaxFeeBumps = *settings.MaxFeeBumps
+	}
+	for _, a := range []struct {
+		value string
+		field **big.Int
+		parse func(string) (*big.Int, error)
+	}{
+		{settings.MaxFeePerGasGwei, &p.MaxFeePerGas, parseGwei},
+		{settings.PriorityFeeGwei, &p.PriorityFee, parseGwei},
+		{settings.MaxPriorityFeeGwei, &p.MaxPriorityFee, parseGwei},
+		{settings.MaxTxCostWei, &p.MaxTxCost, parseWei},
+	} {
+		if a.value != "" {
+			*a.field, _ = a.parse(a.value)
+		}
+	}
+	return p
+}
+
+// parseGwei converts a decimal gwei amount, e.g. "1.5", to wei.
+func parseGwei(v string) (*big.Int, error) {
+	whole, frac, _ := strings.Cut(v, ".")
+	if len(frac) > 9 {
+		return nil, fmt.Errorf("invalid gwei amount %q: more than 9 decimals", v)
+	}
+	wei, err := parseWei(whole + frac + strings.Repeat("0", 9-len(frac)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gwei amount %q", v)
+	}
+	return wei, nil
+}
+
+// parseWei parses a decimal wei amount.
+func parseWei(v string) (*big.Int, error) {
+	wei, ok := new(big.Int).SetString(v, 10)
+	if !ok || wei.Sign() < 0 {
+		return nil, fmt.Errorf("invalid wei amount %q", v)
+	}
+	return wei, nil
+}
+
+// GasQuote holds the gas settings a rebalance transaction is signed with.
+type GasQuote struct {
+	Estimate  uint64
+	GasLimit  uint64
+	BaseFee   *big.Int
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+	// MaxCost is GasLimit * GasFeeCap, the most the transaction can cost.
+	MaxCost *big.Int
+}
+
+// FeeCapTooLowError is returned when MaxFeePerGas is below the current base
+// fee plus tip, so a transaction within the cap would not be included.
+type FeeCapTooLowError struct {
+	BaseFee      *big.Int
+	GasTipCap    *big.Int
+	MaxFeePerGas *big.Int
+}
+
+func (e *FeeCapTooLowError) Error() string {
+	return fmt.Sprintf("fee cap %s wei is below base fee %s + tip %s wei, transaction not signed",
+		e.MaxFeePerGas, e.BaseFee, e.GasTipCap)
+}
+
+// GasBudgetExceededError is returned when the quoted cost of a rebalance is
+// above the per-transaction ceiling or the pool's budget. The transaction is
+// not signed.
+type GasBudgetExceededError struct {
+	// Scope is "transaction" for MAX_TX_COST_WEI or "pool" for max_tx_cost_wei.
+	Scope   string
+	PoolId  common.Hash
+	MaxCost *big.Int
+	Budget  *big.Int
+}
+
+func (e *GasBudgetExceededError) Error() string {
+	return fmt.Sprintf("estimated cost %s wei for pool %s exceeds %s budget %s wei, transaction not signed",
+		e.MaxCost, e.PoolId.Hex(), e.Scope, e.Budget)
+}
+
+// gasBackend is the subset of the L2 client needed to price a transaction.
+type gasBackend interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+}
+
+// quoteGas estimates executeRebalance and prices it under the worker's gas
+// policy, rejecting quotes that exceed the transaction or pool budget.
+func (tw *TaskWorker) quoteGas(ctx context.Context, backend gasBackend, from common.Address, pool *Pool, tickShift int32, taskId uint32) (*GasQuote, error) {
+	head, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	if head.BaseFee == nil {
+		return nil, ErrNoBaseFee
+	}
+
+	tip, err := tw.gas.tipCap(ctx, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	feeCap := new(big.Int).Mul(head.BaseFee, big.NewInt(baseFeeHeadroom))
+	feeCap.Add(feeCap, tip)
+	if tw.gas.MaxFeePerGas != nil && feeCap.Cmp(tw.gas.MaxFeePerGas) > 0 {
+		feeCap.Set(tw.gas.MaxFeePerGas)
+	}
+	if minFee := new(big.Int).Add(head.BaseFee, tip); feeCap.Cmp(minFee) < 0 {
+		return nil, &FeeCapTooLowError{BaseFee: head.BaseFee, GasTipCap: tip, MaxFeePerGas: tw.gas.MaxFeePerGas}
+	}
+
+	parsed, err := lstrebalancehook.LSTrebalanceHookMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	input, err := parsed.Pack("executeRebalance", pool.Key, big.NewInt(int64(tickShift)), taskId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack executeRebalance: %w", err)
+	}
+
+	estimate, err := backend.EstimateGas(ctx, ethereum.CallMsg{
+		From:      from,
+		To:        &tw.hookAddress,
+		GasFeeCap: feeCap,
+		GasTipCap: tip,
+		Data:      input,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %w", decodeCallError(err))
+	}
+
+	gasLimit := uint64(math.Ceil(float64(estimate) * tw.gas.GasLimitMultiplier))
+	quote := &GasQuote{
+		Estimate:  estimate,
+		GasLimit:  gasLimit,
+		BaseFee:   head.BaseFee,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		MaxCost:   new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), feeCap),
+	}
+
+	if tw.gas.MaxTxCost != nil && quote.MaxCost.Cmp(tw.gas.MaxTxCost) > 0 {
+		return nil, &GasBudgetExceededError{Scope: "transaction", PoolId: pool.Id, MaxCost: quote.MaxCost, Budget: tw.gas.MaxTxCost}
+	}
+	if pool.MaxTxCost != nil && quote.MaxCost.Cmp(pool.MaxTxCost) > 0 {
+		return nil, &GasBudgetExceededError{Scope: "pool", PoolId: pool.Id, MaxCost: quote.MaxCost, Budget: pool.MaxTxCost}
+	}
+
+	return quote, nil
+}
+
+func (p GasPolicy) tipCap(ctx context.Context, backend gasBackend) (*big.Int, error) {
+	if p.PriorityFeeStrategy == PriorityFeeFixed {
+		return new(big.Int).Set(p.PriorityFee), nil
+	}
+
+	tip, err := backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas tip cap: %w", err)
+	}
+	if p.MaxPriorityFee != nil && tip.Cmp(p.MaxPriorityFee) > 0 {
+		tip = new(big.Int).Set(p.MaxPriorityFee)
+	}
+	return tip, nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.uber.org/zap"
+)
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9))
+}
+
+func Test_QuoteGas(t *testing.T) {
+	operator := common.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+	pools := testPoolRegistry(t)
+	registered, _ := pools.Lookup(testPoolId(t, pools))
+
+	tests := []struct {
+		name         string
+		policy       GasPolicy
+		poolBudget   *big.Int
+		chain        func(c *fakeChain)
+		wantGasLimit uint64
+		wantTip      *big.Int
+		wantFeeCap   *big.Int
+		wantErr      func(error) bool
+	}{
+		{
+			name:         "node tip with headroom",
+			policy:       DefaultGasPolicy(),
+			wantGasLimit: 120000,
+			wantTip:      gwei(1),
+			wantFeeCap:   gwei(3),
+		},
+		{
+			name:         "fixed tip",
+			policy:       GasPolicy{GasLimitMultiplier: 1.5, PriorityFeeStrategy: PriorityFeeFixed, PriorityFee: gwei(2)},
+			wantGasLimit: 150000,
+			wantTip:      gwei(2),
+			wantFeeCap:   gwei(4),
+		},
+		{
+			name:         "node tip capped",
+			policy:       GasPolicy{GasLimitMultiplier: 1, PriorityFeeStrategy: PriorityFeeNode, MaxPriorityFee: big.NewInt(5e8)},
+			chain:        func(c *fakeChain) { c.tipCap = gwei(10) },
+			wantGasLimit: 100000,
+			wantTip:      big.NewInt(5e8),
+			wantFeeCap:   big.NewInt(25e8),
+		},
+		{
+			name:         "fee cap clamped",
+			policy:       GasPolicy{GasLimitMultiplier: 1, PriorityFeeStrategy: PriorityFeeNode, MaxFeePerGas: big.NewInt(25e8)},
+			wantGasLimit: 100000,
+			wantTip:      gwei(1),
+			wantFeeCap:   big.NewInt(25e8),
+		},
+		{
+			name:   "fee cap below base fee",
+			policy: GasPolicy{GasLimitMultiplier: 1, PriorityFeeStrategy: PriorityFeeNode, MaxFeePerGas: gwei(1)},
+			wantErr: func(err error) bool {
+				var e *FeeCapTooLowError
+				return errors.As(err, &e)
+			},
+		},
+		{
+			name:   "transaction ceiling",
+			policy: GasPolicy{GasLimitMultiplier: 1, PriorityFeeStrategy: PriorityFeeNode, MaxTxCost: big.NewInt(1e14)},
+			wantErr: func(err error) bool {
+				var e *GasBudgetExceededError
+				return errors.As(err, &e) && e.Scope == "transaction" && e.MaxCost.Cmp(big.NewInt(3e14)) == 0
+			},
+		},
+		{
+			name:       "pool budget",
+			policy:     DefaultGasPolicy(),
+			poolBudget: big.NewInt(3e14),
+			wantErr: func(err error) bool {
+				var e *GasBudgetExceededError
+				return errors.As(err, &e) && e.Scope == "pool" && e.PoolId == registered.Id
+			},
+		},
+		{
+			name:         "within pool budget",
+			policy:       DefaultGasPolicy(),
+			poolBudget:   big.NewInt(36e13),
+			wantGasLimit: 120000,
+			wantTip:      gwei(1),
+			wantFeeCap:   gwei(3),
+		},
+		{
+			name:   "estimate reverts",
+			policy: DefaultGasPolicy(),
+			chain: func(c *fakeChain) {
+				c.estimateErr = &fakeDataError{data: hexutil.Encode(selector("invalidTickShift()"))}
+			},
+			wantErr: func(err error) bool { return errors.Is(err, ErrInvalidTickShift) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := newFakeChain(10)
+			if tt.chain != nil {
+				tt.chain(chain)
+			}
+			pool := *registered
+			pool.MaxTxCost = tt.poolBudget
+
+			tw := &TaskWorker{logger: zap.NewNop(), hookAddress: testHookAddress, gas: tt.policy}
+
+			quote, err := tw.quoteGas(context.Background(), chain, operator, &pool, 60, 7)
+			if tt.wantErr != nil {
+				if err == nil || !tt.wantErr(err) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("quoteGas failed: %v", err)
+			}
+
+			if quote.GasLimit != tt.wantGasLimit {
+				t.Errorf("gas limit = %d, want %d", quote.GasLimit, tt.wantGasLimit)
+			}
+			if quote.GasTipCap.Cmp(tt.wantTip) != 0 {
+				t.Errorf("tip = %s, want %s", quote.GasTipCap, tt.wantTip)
+			}
+			if quote.GasFeeCap.Cmp(tt.wantFeeCap) != 0 {
+				t.Errorf("fee cap = %s, want %s", quote.GasFeeCap, tt.wantFeeCap)
+			}
+			wantCost := new(big.Int).Mul(new(big.Int).SetUint64(tt.wantGasLimit), tt.wantFeeCap)
+			if quote.MaxCost.Cmp(wantCost) != 0 {
+				t.Errorf("max cost = %s, want %s", quote.MaxCost, wantCost)
+			}
+		})
+	}
+}
+
+func Test_ParseGwei(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    *big.Int
+		wantErr bool
+	}{
+		{"1", gwei(1), false},
+		{"1.5", big.NewInt(15e8), false},
+		{"0.000000001", big.NewInt(1), false},
+		{"-1", nil, true},
+		{"abc", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseGwei(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseGwei(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got.Cmp(tt.want) != 0 {
+			t.Errorf("parseGwei(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/signer"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+	"golang.org/x/term"
+)
+
+var (
+	// ErrNoOperatorKey is returned when no operator key or signer is configured.
+	ErrNoOperatorKey = errors.New("no operator key configured")
+	// ErrInsecureKeyNotAllowed is returned for a raw hex key without allow_insecure_private_key.
+	ErrInsecureKeyNotAllowed = errors.New("raw operator private keys need allow_insecure_private_key (ALLOW_INSECURE_PRIVATE_KEY=true); use operator_keystore instead")
+	// ErrNoPasswordSource is returned when a keystore or PKCS#11 token has no
+	// password file and there is no terminal to prompt on.
+	ErrNoPasswordSource = errors.New("no password file configured and stdin is not a terminal")
+)
+
+// promptPassword asks for the password of what, a keystore path or a PKCS#11
+// token, on the terminal without echo. Tests replace it.
+var promptPassword = func(what string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", ErrNoPasswordSource
+	}
+	fmt.Fprintf(os.Stderr, "Password for %s: ", what)
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return string(password), nil
+}
+
+// OperatorKey is the decrypted operator key and where it came from. Only
+// Source and the address are safe to log.
+type OperatorKey struct {
+	PrivateKey *ecdsa.PrivateKey
+	// Source is the keystore path, or "raw key".
+	Source string
+}
+
+// LoadOperatorKey decrypts the configured keystore, with the password read
+// from operator_keystore_password_file or prompted for, or parses the raw hex
+// key when allow_insecure_private_key is set. Errors never include key
+// material or passwords.
+func LoadOperatorKey(settings *PerformerSettings) (*OperatorKey, error) {
+	switch {
+	case settings.OperatorKeystore != "":
+		return loadKeystore(settings.OperatorKeystore, settings.OperatorKeystorePasswordFile)
+	case settings.OperatorPrivateKey != "":
+		if !settings.AllowInsecurePrivateKey {
+			return nil, ErrInsecureKeyNotAllowed
+		}
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(settings.OperatorPrivateKey, "0x"))
+		if err != nil {
+			return nil, errors.New("operator_private_key is not a valid secp256k1 key")
+		}
+		return &OperatorKey{PrivateKey: key, Source: "raw key"}, nil
+	default:
+		return nil, ErrNoOperatorKey
+	}
+}
+
+// LoadOperatorSigner returns the signer executeRebalance transactions are
+// signed with and a loggable description of it: a Web3Signer when
+// remote_signer_url is set, a PKCS#11 token when pkcs11_module is set,
+// otherwise the key from LoadOperatorKey.
+func LoadOperatorSigner(settings *PerformerSettings) (signer.Signer, string, error) {
+	if settings.PKCS11Module != "" {
+		return loadPKCS11Signer(settings)
+	}
+	if settings.RemoteSignerURL == "" {
+		key, err := LoadOperatorKey(settings)
+		if err != nil {
+			return nil, "", err
+		}
+		return signer.NewLocal(key.PrivateKey), key.Source, nil
+	}
+
+	cfg := signer.Web3SignerConfig{
+		URL:     settings.RemoteSignerURL,
+		Address: common.HexToAddress(settings.RemoteSignerAddress),
+	}
+	for _, f := range []struct {
+		path string
+		dst  *[]byte
+	}{
+		{settings.RemoteSignerCACert, &cfg.CACert},
+		{settings.RemoteSignerClientCert, &cfg.ClientCert},
+		{settings.RemoteSignerClientKey, &cfg.ClientKey},
+	} {
+		if f.path == "" {
+			continue
+		}
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read remote signer TLS file: %w", err)
+		}
+		*f.dst = data
+	}
+	s, err := signer.NewWeb3Signer(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	return s, "web3signer " + settings.RemoteSignerURL, nil
+}
+
+func loadPKCS11Signer(settings *PerformerSettings) (signer.Signer, string, error) {
+	var pin string
+	if settings.PKCS11PinFile != "" {
+		data, err := os.ReadFile(settings.PKCS11PinFile)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read PKCS#11 PIN file: %w", err)
+		}
+		pin = strings.TrimRight(string(data), "\r\n")
+	} else {
+		var err error
+		if pin, err = promptPassword("PKCS#11 token " + settings.PKCS11TokenLabel); err != nil {
+			return nil, "", fmt.Errorf("PKCS#11 token %s: %w", settings.PKCS11TokenLabel, err)
+		}
+	}
+
+	s, err := signer.NewPKCS11(signer.PKCS11Config{
+		Module:     settings.PKCS11Module,
+		TokenLabel: settings.PKCS11TokenLabel,
+		KeyLabel:   settings.PKCS11KeyLabel,
+		PIN:        pin,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return s, fmt.Sprintf("pkcs11 %s/%s", settings.PKCS11TokenLabel, settings.PKCS11KeyLabel), nil
+}
+
+// checkRemoteSigner logs an error when the remote signer is unreachable or
+// does not hold the operator's key. Startup goes on: the signer may come up
+// later, and every signature it returns is checked anyway.
+func checkRemoteSigner(logger *zap.Logger, s *signer.Web3Signer) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	accounts, err := s.Accounts(ctx)
+	if err != nil {
+		logger.Error("Failed to reach the remote signer", zap.Error(err))
+		return
+	}
+	for _, a := range accounts {
+		if a == s.Address() {
+			return
+		}
+	}
+	logger.Sugar().Errorw("Remote signer does not hold the operator key, rebalances will fail",
+		"address", s.Address().Hex(),
+		"accounts", len(accounts),
+	)
+}
+
+func loadKeystore(path, passwordFile string) (*OperatorKey, error) {
+	encrypted, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+
+	var password string
+	if passwordFile != "" {
+		data, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keystore password file: %w", err)
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+	} else if password, err = promptPassword(path); err != nil {
+		return nil, fmt.Errorf("keystore %s: %w", path, err)
+	}
+
+	key, err := keystore.DecryptKey(encrypted, password)
+	if err != nil {
+		// keystore errors carry no secrets, e.g. "could not decrypt key with given password".
+		return nil, fmt.Errorf("failed to decrypt keystore %s: %w", path, err)
+	}
+	return &OperatorKey{PrivateKey: key.PrivateKey, Source: path}, nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/signer"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+const testKeystorePassword = "correct horse"
+
+// writeKeystore encrypts testOperatorKey with light scrypt parameters and
+// returns its path and address.
+func writeKeystore(t *testing.T) (string, common.Address) {
+	t.Helper()
+
+	priv, err := crypto.HexToECDSA(strings.TrimPrefix(testOperatorKey, "0x"))
+	if err != nil {
+		t.Fatalf("bad test key: %v", err)
+	}
+	key := &keystore.Key{Id: uuid.New(), Address: crypto.PubkeyToAddress(priv.PublicKey), PrivateKey: priv}
+	encrypted, err := keystore.EncryptKey(key, testKeystorePassword, keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("EncryptKey failed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "operator.ecdsa.keystore.json")
+	if err := os.WriteFile(path, encrypted, 0o600); err != nil {
+		t.Fatalf("failed to write keystore: %v", err)
+	}
+	return path, key.Address
+}
+
+func writePasswordFile(t *testing.T, password string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(path, []byte(password), 0o600); err != nil {
+		t.Fatalf("failed to write password file: %v", err)
+	}
+	return path
+}
+
+// stubPrompt replaces promptPassword for the duration of the test.
+func stubPrompt(t *testing.T, password string, err error) *int {
+	t.Helper()
+
+	calls := 0
+	orig := promptPassword
+	promptPassword = func(string) (string, error) {
+		calls++
+		return password, err
+	}
+	t.Cleanup(func() { promptPassword = orig })
+	return &calls
+}
+
+func Test_LoadOperatorKeyKeystore(t *testing.T) {
+	path, addr := writeKeystore(t)
+
+	t.Run("password file", func(t *testing.T) {
+		calls := stubPrompt(t, "", errors.New("unexpected prompt"))
+		key, err := LoadOperatorKey(&PerformerSettings{
+			OperatorKeystore:             path,
+			OperatorKeystorePasswordFile: writePasswordFile(t, testKeystorePassword+"\n"),
+		})
+		if err != nil {
+			t.Fatalf("LoadOperatorKey failed: %v", err)
+		}
+		if got := crypto.PubkeyToAddress(key.PrivateKey.PublicKey); got != addr || key.Source != path {
+			t.Errorf("key = %s from %s, want %s from %s", got.Hex(), key.Source, addr.Hex(), path)
+		}
+		if *calls != 0 {
+			t.Errorf("prompted %d times with a password file", *calls)
+		}
+	})
+
+	t.Run("prompt", func(t *testing.T) {
+		calls := stubPrompt(t, testKeystorePassword, nil)
+		key, err := LoadOperatorKey(&PerformerSettings{OperatorKeystore: path})
+		if err != nil {
+			t.Fatalf("LoadOperatorKey failed: %v", err)
+		}
+		if got := crypto.PubkeyToAddress(key.PrivateKey.PublicKey); got != addr {
+			t.Errorf("address = %s, want %s", got.Hex(), addr.Hex())
+		}
+		if *calls != 1 {
+			t.Errorf("prompted %d times, want 1", *calls)
+		}
+	})
+
+	t.Run("no terminal", func(t *testing.T) {
+		stubPrompt(t, "", ErrNoPasswordSource)
+		_, err := LoadOperatorKey(&PerformerSettings{OperatorKeystore: path})
+		if !errors.Is(err, ErrNoPasswordSource) {
+			t.Errorf("expected ErrNoPasswordSource, got %v", err)
+		}
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		wrong := "wrong horse"
+		_, err := LoadOperatorKey(&PerformerSettings{
+			OperatorKeystore:             path,
+			OperatorKeystorePasswordFile: writePasswordFile(t, wrong),
+		})
+		if !errors.Is(err, keystore.ErrDecrypt) {
+			t.Fatalf("expected keystore.ErrDecrypt, got %v", err)
+		}
+		for _, secret := range []string{wrong, testKeystorePassword, strings.TrimPrefix(testOperatorKey, "0x")} {
+			if strings.Contains(err.Error(), secret) {
+				t.Errorf("error leaks a secret: %v", err)
+			}
+		}
+	})
+}
+
+func Test_LoadOperatorKeyRaw(t *testing.T) {
+	settings := &PerformerSettings{OperatorPrivateKey: testOperatorKey}
+	if _, err := LoadOperatorKey(settings); !errors.Is(err, ErrInsecureKeyNotAllowed) {
+		t.Fatalf("expected ErrInsecureKeyNotAllowed, got %v", err)
+	}
+
+	settings.AllowInsecurePrivateKey = true
+	key, err := LoadOperatorKey(settings)
+	if err != nil {
+		t.Fatalf("LoadOperatorKey failed: %v", err)
+	}
+	if key.Source != "raw key" {
+		t.Errorf("source = %q", key.Source)
+	}
+
+	bad := strings.Repeat("ab", 31) + "zz"
+	settings.OperatorPrivateKey = bad
+	if _, err := LoadOperatorKey(settings); err == nil || strings.Contains(err.Error(), bad) {
+		t.Errorf("invalid key error leaks the key or is missing: %v", err)
+	}
+
+	if _, err := LoadOperatorKey(&PerformerSettings{}); !errors.Is(err, ErrNoOperatorKey) {
+		t.Errorf("expected ErrNoOperatorKey, got %v", err)
+	}
+}
+
+func Test_LoadOperatorKeyDevnetKeystore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("full-strength scrypt is slow")
+	}
+	key, err := LoadOperatorKey(&PerformerSettings{
+		OperatorKeystore:             "../keystores/operator2.ecdsa.keystore.json",
+		OperatorKeystorePasswordFile: writePasswordFile(t, "testpass"),
+	})
+	if err != nil {
+		t.Fatalf("LoadOperatorKey failed: %v", err)
+	}
+	want := common.HexToAddress("0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65")
+	if got := crypto.PubkeyToAddress(key.PrivateKey.PublicKey); got != want {
+		t.Errorf("address = %s, want %s", got.Hex(), want.Hex())
+	}
+}
+
+func Test_LoadOperatorSigner(t *testing.T) {
+	path, addr := writeKeystore(t)
+	s, source, err := LoadOperatorSigner(&PerformerSettings{
+		OperatorKeystore:             path,
+		OperatorKeystorePasswordFile: writePasswordFile(t, testKeystorePassword),
+	})
+	if err != nil {
+		t.Fatalf("LoadOperatorSigner failed: %v", err)
+	}
+	if _, ok := s.(*signer.Local); !ok || s.Address() != addr || source != path {
+		t.Errorf("signer = %T for %s from %s", s, s.Address().Hex(), source)
+	}
+
+	remoteAddr := common.HexToAddress("0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65")
+	s, source, err = LoadOperatorSigner(&PerformerSettings{
+		RemoteSignerURL:     "https://web3signer.internal:9000",
+		RemoteSignerAddress: remoteAddr.Hex(),
+	})
+	if err != nil {
+		t.Fatalf("LoadOperatorSigner failed: %v", err)
+	}
+	if _, ok := s.(*signer.Web3Signer); !ok || s.Address() != remoteAddr || !strings.Contains(source, "web3signer") {
+		t.Errorf("signer = %T for %s from %s", s, s.Address().Hex(), source)
+	}
+
+	_, _, err = LoadOperatorSigner(&PerformerSettings{
+		RemoteSignerURL:     "https://web3signer.internal:9000",
+		RemoteSignerAddress: remoteAddr.Hex(),
+		RemoteSignerCACert:  writePasswordFile(t, "not a certificate"),
+	})
+	if err == nil {
+		t.Error("expected an error for an invalid CA certificate")
+	}
+
+	pkcs11 := &PerformerSettings{
+		PKCS11Module:     filepath.Join(t.TempDir(), "missing.so"),
+		PKCS11TokenLabel: "performer",
+		PKCS11KeyLabel:   "operator",
+	}
+	stubPrompt(t, "", ErrNoPasswordSource)
+	if _, _, err := LoadOperatorSigner(pkcs11); !errors.Is(err, ErrNoPasswordSource) {
+		t.Errorf("expected ErrNoPasswordSource without a PIN file, got %v", err)
+	}
+	pkcs11.PKCS11PinFile = writePasswordFile(t, "1234\n")
+	if _, _, err := LoadOperatorSigner(pkcs11); err == nil || strings.Contains(err.Error(), "1234") {
+		t.Errorf("missing PKCS#11 module error leaks the PIN or is missing: %v", err)
+	}
+}
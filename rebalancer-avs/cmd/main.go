@@ -2,42 +2,53 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
 	"time"
-	"crypto/ecdsa"
-"strings"
-"github.com/ethereum/go-ethereum/accounts/abi"
 
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/signer"
 	"github.com/Layr-Labs/hourglass-monorepo/ponos/pkg/performer/contracts"
 	"github.com/Layr-Labs/hourglass-monorepo/ponos/pkg/performer/server"
 	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"go.uber.org/zap"
 )
 
-type RebalanceTaskData struct {
-	PoolId          [32]byte
-	YieldBps        uint64
-	CumulativeYield uint64
-	PositionCount   uint64
-	Timestamp       uint64
-}
-
 type TaskWorker struct {
-	logger        *zap.Logger
-	contractStore *contracts.ContractStore
-	l1Client      *ethclient.Client
-	l2Client      *ethclient.Client
-	hookAddress   common.Address
-	privateKey  *ecdsa.PrivateKey
+	logger         *zap.Logger
+	contractStore  *contracts.ContractStore
+	l1Client       *ethclient.Client
+	l2Client       *ethclient.Client
+	hookAddress    common.Address
+	hook           *lstrebalancehook.LSTrebalanceHook
+	signer         signer.Signer
+	pools          *PoolRegistry
+	maxTaskAge     time.Duration
+	confirmations  uint64
+	receiptTimeout time.Duration
+	gas            GasPolicy
+	nonces         *NonceManager
+	tickRounding   TickRounding
+	poolState      *PoolStateReader
+	executor       Executor
+	store          *TaskStore
+
+	yieldSources      map[common.Hash]YieldSource
+	yieldToleranceBps uint64
+
+	now func() time.Time
 }
 
-func NewTaskWorker(logger *zap.Logger) *TaskWorker {
+// NewTaskWorker builds the worker from a loaded, validated cfg.
+func NewTaskWorker(logger *zap.Logger, cfg *PerformerConfig) *TaskWorker {
+	settings := &cfg.Context.Performer
+
 	contractStore, err := contracts.NewContractStore()
 	if err != nil {
 		logger.Warn("Failed to load contract store", zap.Error(err))
@@ -45,39 +56,163 @@ func NewTaskWorker(logger *zap.Logger) *TaskWorker {
 
 	var l1Client, l2Client *ethclient.Client
 
-	if l1RpcUrl := os.Getenv("L1_RPC_URL"); l1RpcUrl != "" {
+	if l1RpcUrl := cfg.Context.Chains.L1.RpcURL; l1RpcUrl != "" {
 		l1Client, err = ethclient.Dial(l1RpcUrl)
 		if err != nil {
 			logger.Error("Failed to connect to L1 RPC", zap.Error(err))
 		}
 	}
 
-	if l2RpcUrl := os.Getenv("L2_RPC_URL"); l2RpcUrl != "" {
+	if l2RpcUrl := cfg.Context.Chains.L2.RpcURL; l2RpcUrl != "" {
 		l2Client, err = ethclient.Dial(l2RpcUrl)
 		if err != nil {
 			logger.Error("Failed to connect to L2 RPC", zap.Error(err))
 		}
 	}
 
-	hookAddress := common.HexToAddress(os.Getenv("HOOK_ADDRESS"))
-	
-	pkHex := os.Getenv("OPERATOR_PRIVATE_KEY")
-	 var privateKey *ecdsa.PrivateKey
-	pk, err := crypto.HexToECDSA(pkHex)
-if err != nil {
-    logger.Error("Failed to load private key", zap.Error(err))
-} else {
-    privateKey = pk
-}
+	hookAddress := common.HexToAddress(settings.HookAddress)
+
+	var hook *lstrebalancehook.LSTrebalanceHook
+	if l2Client != nil && hookAddress != (common.Address{}) {
+		hook, err = lstrebalancehook.NewLSTrebalanceHook(hookAddress, l2Client)
+		if err != nil {
+			logger.Error("Failed to bind hook contract", zap.Error(err))
+		}
+	}
+
+	var poolState *PoolStateReader
+	if hook != nil {
+		poolManager := common.HexToAddress(settings.PoolManagerAddress)
+		if poolManager == (common.Address{}) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			poolManager, err = hook.PoolManager(&bind.CallOpts{Context: ctx})
+			cancel()
+			if err != nil {
+				logger.Error("Failed to read poolManager from hook, tick shifts ignore the pool price", zap.Error(err))
+			}
+		}
+		if poolManager != (common.Address{}) {
+			poolState = NewPoolStateReader(l2Client, poolManager)
+			logger.Sugar().Infow("Reading pool state from PoolManager", "poolManager", poolManager.Hex())
+		}
+	}
+
+	operator, source, err := LoadOperatorSigner(settings)
+	if errors.Is(err, ErrNoOperatorKey) {
+		logger.Warn("No operator key configured, rebalances will not be executed")
+	} else if err != nil {
+		logger.Error("Failed to load operator key, rebalances will not be executed", zap.Error(err))
+	} else {
+		if settings.OperatorPrivateKey != "" {
+			logger.Warn("⚠️  Using a raw operator private key (allow_insecure_private_key); use an encrypted keystore outside local testing")
+		}
+		logger.Sugar().Infow("🔑 Loaded operator key",
+			"address", operator.Address().Hex(),
+			"source", source,
+		)
+		if remote, ok := operator.(*signer.Web3Signer); ok {
+			checkRemoteSigner(logger, remote)
+		}
+	}
+
+	pools, err := LoadPoolRegistry(settings.PoolsConfig, hookAddress)
+	if err != nil {
+		logger.Error("Failed to load pool registry, every task will be rejected", zap.Error(err))
+	} else {
+		for _, p := range pools.pools {
+			logger.Sugar().Infow("Registered pool",
+				"name", p.Name,
+				"poolId", p.Id.Hex(),
+				"currency0", p.Key.Currency0.Hex(),
+				"currency1", p.Key.Currency1.Hex(),
+			)
+		}
+	}
+
+	var l1Caller, l2Caller bind.ContractCaller
+	if l1Client != nil {
+		l1Caller = l1Client
+	}
+	if l2Client != nil {
+		l2Caller = l2Client
+	}
+	yieldSources := make(map[common.Hash]YieldSource)
+	if pools != nil {
+		for _, p := range pools.pools {
+			if p.YieldSource == nil {
+				continue
+			}
+			source, err := NewYieldSource(p.YieldSource, l1Caller, l2Caller)
+			if err != nil {
+				logger.Error("Failed to configure yield source, reported yield will not be cross-checked",
+					zap.String("pool", p.Name),
+					zap.Error(err),
+				)
+				continue
+			}
+			yieldSources[p.Id] = source
+			logger.Sugar().Infow("Registered yield source", "pool", p.Name, "source", source.Name())
+		}
+	}
+
+	tickRounding, err := ParseTickRounding(settings.TickRounding)
+	if err != nil {
+		logger.Error("Invalid performer.tick_rounding, using default",
+			zap.Error(err),
+			zap.String("default", string(defaultTickRounding)),
+		)
+		tickRounding = defaultTickRounding
+	}
+
+	var nonces *NonceManager
+	if l2Client != nil && operator != nil {
+		nonces = NewNonceManager(logger, l2Client, operator.Address())
+	}
+
+	storePath := settings.TaskStorePath
+	store, err := OpenTaskStore(storePath)
+	if err != nil {
+		logger.Error("Failed to open task store, task state will not survive a restart", zap.Error(err))
+	} else {
+		logger.Sugar().Infow("Task store opened", "path", storePath)
+	}
 
-	return &TaskWorker{
-		logger:        logger,
-		contractStore: contractStore,
-		l1Client:      l1Client,
-		l2Client:      l2Client,
-		hookAddress:   hookAddress,
-		privateKey:    privateKey,
+	tw := &TaskWorker{
+		logger:         logger,
+		contractStore:  contractStore,
+		l1Client:       l1Client,
+		l2Client:       l2Client,
+		hookAddress:    hookAddress,
+		hook:           hook,
+		signer:         operator,
+		pools:          pools,
+		maxTaskAge:     settings.TaskMaxAge,
+		confirmations:  settings.ReceiptConfirmations,
+		receiptTimeout: settings.ReceiptTimeout,
+		gas:            loadGasPolicy(settings),
+		nonces:         nonces,
+		tickRounding:   tickRounding,
+		poolState:      poolState,
+		store:          store,
+
+		yieldSources:      yieldSources,
+		yieldToleranceBps: *settings.YieldToleranceBps,
+		now:               time.Now,
 	}
+
+	if l2Client != nil && hook != nil && operator != nil {
+		var mailbox *TaskMailbox
+		if addr := cfg.Context.EigenLayer.L2.TaskMailbox; addr != "" {
+			mailbox = NewTaskMailbox(l2Client, common.HexToAddress(addr))
+			logger.Sugar().Infow("Executing certified results from TaskMailbox", "mailbox", addr)
+		} else {
+			logger.Warn("eigenlayer.l2.task_mailbox (TASK_MAILBOX_ADDRESS) not set, executing results without waiting for certification")
+		}
+		executor := NewHookExecutor(logger, tw, mailbox, settings.CertificationTimeout, settings.CertificationPollInterval)
+		executor.Resume()
+		tw.executor = executor
+	}
+	return tw
 }
 
 func (tw *TaskWorker) ValidateTask(t *performerV1.TaskRequest) error {
@@ -89,75 +224,267 @@ func (tw *TaskWorker) ValidateTask(t *performerV1.TaskRequest) error {
 		return fmt.Errorf("no task ID provided")
 	}
 
+	if stored, err := tw.store.Get(t.TaskId); err != nil {
+		return fmt.Errorf("failed to read task store: %w", err)
+	} else if stored.Answered() {
+		tw.logger.Sugar().Infow("♻️  Task already handled, skipping validation",
+			"taskId", string(t.TaskId),
+			"state", stored.State,
+		)
+		return nil
+	}
+
+	tw.transition(string(t.TaskId), StateReceived, nil)
+	if err := tw.validateTask(t); err != nil {
+		tw.failTask(string(t.TaskId), err)
+		return err
+	}
+	tw.transition(string(t.TaskId), StateValidated, nil)
+
 	tw.logger.Sugar().Infow("✅ Task validation passed",
 		zap.String("taskId", string(t.TaskId)),
 	)
 	return nil
 }
 
+func (tw *TaskWorker) validateTask(t *performerV1.TaskRequest) error {
+	data, err := DecodeTaskPayload(t.Payload)
+	if err != nil {
+		return err
+	}
+
+	return tw.validateTaskData(data)
+}
+
 func (tw *TaskWorker) HandleTask(t *performerV1.TaskRequest) (*performerV1.TaskResponse, error) {
 	tw.logger.Sugar().Infow("🔄 Processing LST rebalance task",
 		zap.String("taskId", string(t.TaskId)),
 	)
 
-	 
-	mockYieldBps := uint64(50) 
+	stored, err := tw.store.Get(t.TaskId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task store: %w", err)
+	}
+	if stored.Answered() {
+		tw.logger.Sugar().Infow("♻️  Returning stored result for redelivered task",
+			"taskId", string(t.TaskId),
+			"state", stored.State,
+		)
+		return &performerV1.TaskResponse{TaskId: t.TaskId, Result: stored.Result}, nil
+	}
+
+	resp, err := tw.handleTask(t)
+	if err != nil {
+		tw.failTask(string(t.TaskId), err)
+	}
+	return resp, err
+}
+
+func (tw *TaskWorker) handleTask(t *performerV1.TaskRequest) (*performerV1.TaskResponse, error) {
+	data, err := DecodeTaskPayload(t.Payload)
+	if err != nil {
+		return nil, err
+	}
 
 	tw.logger.Sugar().Infow("📊 Task parameters",
-		"yieldBps", mockYieldBps,
+		"poolId", common.Hash(data.PoolId).Hex(),
+		"yieldBps", data.YieldBps,
+		"cumulativeYieldBps", data.CumulativeYield,
+		"positionCount", data.PositionCount,
+		"timestamp", data.Timestamp,
 	)
 
+	var l1Headers, l2Headers headerBackend
+	if tw.l1Client != nil {
+		l1Headers = tw.l1Client
+	}
+	if tw.l2Client != nil {
+		l2Headers = tw.l2Client
+	}
+	computed, err := tw.computeResult(context.Background(), l1Headers, l2Headers, t.TaskId, data)
+	if err != nil {
+		if computed != nil && computed.Simulation != nil {
+			tw.transition(string(t.TaskId), StateFailed, func(st *StoredTask) { st.Simulation = computed.Simulation })
+		}
+		return nil, err
+	}
+	plan, resultBytes := computed.Plan, computed.Result
+
+	// The result is final: persist it so a redelivery returns the same bytes,
+	// then hand it to the executor, which acts once it is certified.
+	tw.transition(string(t.TaskId), StatePlanned, func(st *StoredTask) {
+		st.Result = resultBytes
+		st.PoolId = computed.Pool.Id
+		st.ExpectedPositions = uint64(plan.Rebalanced)
+		st.Simulation = computed.Simulation
+	})
+	if computed.Simulation != nil {
+		tw.transition(string(t.TaskId), StateSimulated, nil)
+	}
+	switch {
+	case tw.executor == nil:
+		tw.logger.Warn("⚠️  Skipping hook execution (missing L2 client, hook address, or operator signer)")
+	case !plan.Worthwhile():
+		tw.logger.Sugar().Warnw("⚠️  Skipping hook execution (plan rebalances no positions)",
+			"tickShift", plan.TickShift,
+			"positions", len(plan.Positions),
+		)
+		tw.skipTask(string(t.TaskId), skipNotWorthwhile)
+	default:
+		if err := tw.executor.Submit(&ExecutionTask{TaskId: t.TaskId, Result: resultBytes}); err != nil {
+			return nil, fmt.Errorf("failed to submit rebalance for execution: %w", err)
+		}
+	}
+
+	return &performerV1.TaskResponse{
+		TaskId: t.TaskId,
+		Result: resultBytes,
+	}, nil
+}
+
+// ComputedResult is what computeResult derives for a task.
+type ComputedResult struct {
+	Pool *Pool
+	Plan *RebalancePlan
+	// Result is the encoded taskresult.Result.
+	Result []byte
+	// Simulation is nil when the plan was not simulated: without an L2 client
+	// or hook, or when it rebalances no positions.
+	Simulation *SimulationVerdict
+}
+
+// computeResult derives the task's result from the payload and from chain
+// state at the block of the task's RebalanceRequested event, never at the
+// local head, so operators whose nodes agree on that block return the same
+// bytes. Without an L2 client or hook the result depends on the payload alone.
+//
+// The plan is simulated at the reference block before it is encoded. When the
+// simulation rejects it, the returned ComputedResult carries only the verdict,
+// alongside a *SimulationError.
+func (tw *TaskWorker) computeResult(ctx context.Context, l1, l2 headerBackend, taskId []byte, data *RebalanceTaskData) (*ComputedResult, error) {
+	pool, ok := tw.pools.Lookup(common.Hash(data.PoolId))
+	if !ok {
+		return nil, &UnknownPoolError{PoolId: common.Hash(data.PoolId)}
+	}
+
+	var (
+		ref *TaskReference
+		err error
+	)
+	if l2 != nil && tw.hook != nil {
+		ref, err = tw.locateTask(ctx, l2, pool, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.checkYield(ctx, l1, l2, pool, data, ref); err != nil {
+		return nil, err
+	}
+
+	var snapshot *PoolSnapshot
+	if ref != nil {
+		snapshot, err = tw.readPoolSnapshot(ctx, pool, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pool state: %w", err)
+		}
+		if state := snapshot.State; state != nil {
+			tw.logger.Sugar().Infow("🌊 Pool state",
+				"poolId", pool.Id.Hex(),
+				"blockNumber", state.BlockNumber,
+				"sqrtPriceX96", state.SqrtPriceX96,
+				"tick", state.Tick,
+				"protocolFee", state.ProtocolFee,
+				"lpFee", state.LPFee,
+				"liquidity", state.Liquidity,
+			)
+		}
+	}
+
 	// Calculate optimal tick shift
-	tickShift := tw.calculateTickShift(mockYieldBps)
+	tickShift := tw.calculateTickShift(pool, data.YieldBps, snapshot)
 
 	tw.logger.Sugar().Infow("✅ Calculated tick shift",
 		"tickShift", tickShift,
-		"yieldBps", mockYieldBps,
+		"yieldBps", data.YieldBps,
 	)
 
-	// Execute rebalance on hook if L2 client is available
-	if tw.l2Client != nil && tw.hookAddress != (common.Address{}) && tw.privateKey != nil {
-		err := tw.executeRebalanceOnHook(tickShift)
-		if err != nil {
-			tw.logger.Error("❌ Failed to execute rebalance on hook", zap.Error(err))
+	plan, err := tw.planRebalance(ctx, pool, tickShift, snapshot)
+	if err != nil {
+		return nil, err
+	}
 
-		} else {
-			tw.logger.Info("✅ Rebalance executed successfully on hook!")
+	var verdict *SimulationVerdict
+	if ref != nil && plan.Worthwhile() {
+		verdict, err = tw.simulateResult(ctx, pool, plan, ref, taskId)
+		if err != nil {
+			if verdict != nil {
+				return &ComputedResult{Pool: pool, Simulation: verdict}, err
+			}
+			return nil, err
 		}
-	} else {
-		tw.logger.Warn("⚠️  Skipping hook execution (missing L2 client, hook address, or private key)")
 	}
 
-	// Encode tick shift as bytes for the response
-	resultBytes := []byte(fmt.Sprintf("%d", tickShift))
-
-	return &performerV1.TaskResponse{
-		TaskId: t.TaskId,
-		Result: resultBytes,
-	}, nil
+	resultBytes, err := encodeTaskResult(taskId, plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode task result: %w", err)
+	}
+	return &ComputedResult{Pool: pool, Plan: plan, Result: resultBytes, Simulation: verdict}, nil
 }
 
-func (tw *TaskWorker) calculateTickShift(yieldBps uint64) int32 {
-	tickShift := int32(yieldBps)
+// calculateTickShift converts the yield into a shift and, when a snapshot with
+// pool state is available, adjusts it for where the pool traded relative to
+// the positions at the task's reference block.
+func (tw *TaskWorker) calculateTickShift(pool *Pool, yieldBps uint64, snapshot *PoolSnapshot) int32 {
+	tickShift := TickShiftForYield(pool, yieldBps, maxTickShift)
 
 	tw.logger.Sugar().Infow("📐 Calculating tick shift",
 		"yieldBps", yieldBps,
+		"lstIsCurrency0", pool.LSTIsCurrency0,
 		"calculatedShift", tickShift,
 	)
 
-	if tickShift > 1000 {
+	if snapshot != nil && snapshot.State != nil {
+		if center, ok := snapshot.PositionCenter(); ok {
+			drift := snapshot.State.Tick - center
+			relative := RelativeTickShift(tickShift, drift)
+			tw.logger.Sugar().Infow("🎯 Adjusted tick shift to pool price",
+				"tick", snapshot.State.Tick,
+				"positionCenter", center,
+				"drift", drift,
+				"yieldShift", tickShift,
+				"relativeShift", relative,
+			)
+			tickShift = relative
+		}
+	}
+
+	if tickShift > int32(maxTickShift) {
+		tickShift = int32(maxTickShift)
+	} else if tickShift < -int32(maxTickShift) {
+		tickShift = -int32(maxTickShift)
+	}
+	if tickShift == int32(maxTickShift) || tickShift == -int32(maxTickShift) {
 		tw.logger.Sugar().Warnw("Tick shift capped at maximum",
-			"original", tickShift,
-			"capped", 1000,
+			"yieldBps", yieldBps,
+			"capped", tickShift,
 		)
-		tickShift = 1000
-	} else if tickShift < -1000 {
-		tw.logger.Sugar().Warnw("Tick shift capped at minimum",
-			"original", tickShift,
-			"capped", -1000,
+	}
+
+	spacing := int32(pool.Key.TickSpacing.Int64())
+	aligned := AlignTickShift(tickShift, spacing, tw.tickRounding)
+	if aligned > int32(maxTickShift) || aligned < -int32(maxTickShift) {
+		aligned = AlignTickShift(tickShift, spacing, RoundTowardZero)
+	}
+	if aligned != tickShift {
+		tw.logger.Sugar().Infow("📏 Aligned tick shift to tick spacing",
+			"tickShift", tickShift,
+			"tickSpacing", spacing,
+			"rounding", tw.tickRounding,
+			"aligned", aligned,
 		)
-		tickShift = -1000
 	}
+	tickShift = aligned
 
 	tw.logger.Sugar().Infow("📈 Final tick shift",
 		"finalShift", tickShift,
@@ -167,72 +494,139 @@ func (tw *TaskWorker) calculateTickShift(yieldBps uint64) int32 {
 }
 
 // Execute rebalance on the hook contract
-func (tw *TaskWorker) executeRebalanceOnHook(tickShift int32) error {
-    tw.logger.Sugar().Infow("📤 Calling hook contract to execute rebalance",
-        "hookAddress", tw.hookAddress.Hex(),
-        "tickShift", tickShift,
-    )
-
-    chainID, err := tw.l2Client.ChainID(context.Background())
-    if err != nil {
-        return fmt.Errorf("failed to get chain ID: %w", err)
-    }
-
-
-    auth, err := bind.NewKeyedTransactorWithChainID(tw.privateKey, chainID)
-    if err != nil {
-        return fmt.Errorf("failed to create transactor: %w", err)
-    }
-
-   
-    auth.GasLimit = 500000
-
-    // ABI for executeRebalance function
-    hookABI := `[{"inputs":[{"components":[{"internalType":"address","name":"currency0","type":"address"},{"internalType":"address","name":"currency1","type":"address"},{"internalType":"uint24","name":"fee","type":"uint24"},{"internalType":"int24","name":"tickSpacing","type":"int24"},{"internalType":"address","name":"hooks","type":"address"}],"internalType":"struct PoolKey","name":"","type":"tuple"},{"internalType":"int24","name":"","type":"int24"},{"internalType":"uint32","name":"","type":"uint32"}],"name":"executeRebalance","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"nonpayable","type":"function"}]`
-
-   
-    parsedABI, err := abi.JSON(strings.NewReader(hookABI))
-    if err != nil {
-        return fmt.Errorf("failed to parse ABI: %w", err)
-    }
-
-    
-    contract := bind.NewBoundContract(tw.hookAddress, parsedABI, tw.l2Client, tw.l2Client, tw.l2Client)
-
- 
-    poolKey := struct {
-        Currency0   common.Address
-        Currency1   common.Address
-        Fee         *big.Int
-        TickSpacing *big.Int
-        Hooks       common.Address
-    }{
-        Currency0:   common.HexToAddress("0x8C4c13856e935d33c0d3C3EF5623F2339f17d4f5"),
-        Currency1:   common.HexToAddress("0xfbBB81A58049F92C340F00006D6B1BCbDfD5ec0d"),
-        Fee:         big.NewInt(3000),
-        TickSpacing: big.NewInt(60),
-        Hooks:       tw.hookAddress,
-    }
-
-    // Call the contract
-    tx, err := contract.Transact(auth, "executeRebalance", poolKey, big.NewInt(int64(tickShift)), uint32(0))
-    if err != nil {
-        return fmt.Errorf("failed to send transaction: %w", err)
-    }
-
-    tw.logger.Sugar().Infow("✅ Transaction sent to hook contract", "txHash", tx.Hash().Hex())
-    return nil
+func (tw *TaskWorker) executeRebalanceOnHook(ctx context.Context, taskId string, pool *Pool, plan *RebalancePlan, expectedPositions uint64) (*RebalanceOutcome, error) {
+	tickShift := plan.TickShift
+	taskHash := TaskHash([]byte(taskId))
+	onchainTaskId := OnchainTaskId(taskHash)
+	tw.logger.Sugar().Infow("📤 Calling hook contract to execute rebalance",
+		"hookAddress", tw.hookAddress.Hex(),
+		"pool", pool.Name,
+		"poolId", pool.Id.Hex(),
+		"tickShift", tickShift,
+		"taskId", taskId,
+		"taskHash", taskHash.Hex(),
+		"onchainTaskId", onchainTaskId,
+	)
+
+	chainID, err := tw.l2Client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	auth := signer.NewTransactor(ctx, tw.signer, chainID)
+
+	simulated, err := tw.simulateRebalance(ctx, auth.From, nil, pool, tickShift, onchainTaskId, expectedPositions)
+	if err != nil {
+		return nil, err
+	}
+	tw.logger.Sugar().Infow("🧪 Simulated rebalance",
+		"poolId", pool.Id.Hex(),
+		"positionsRebalanced", simulated,
+		"positionCount", expectedPositions,
+	)
+	tw.transition(taskId, StateSimulated, func(st *StoredTask) { st.ExpectedPositions = expectedPositions })
+	if simulated != uint64(plan.Rebalanced) {
+		tw.logger.Sugar().Warnw("⚠️  Simulation disagrees with rebalance plan",
+			"poolId", pool.Id.Hex(),
+			"simulated", simulated,
+			"planned", plan.Rebalanced,
+		)
+	}
+
+	quote, err := tw.quoteGas(ctx, tw.l2Client, auth.From, pool, tickShift, onchainTaskId)
+	if err != nil {
+		return nil, err
+	}
+	tw.logger.Sugar().Infow("⛽ Gas quote",
+		"estimate", quote.Estimate,
+		"gasLimit", quote.GasLimit,
+		"baseFee", quote.BaseFee,
+		"gasTipCap", quote.GasTipCap,
+		"gasFeeCap", quote.GasFeeCap,
+		"maxCost", quote.MaxCost,
+	)
+	auth.GasLimit = quote.GasLimit
+	auth.GasTipCap = quote.GasTipCap
+	auth.GasFeeCap = quote.GasFeeCap
+
+	// Call the contract
+	tx, err := tw.sendWithNonce(ctx, auth, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return tw.hook.ExecuteRebalance(opts, pool.Key, big.NewInt(int64(tickShift)), onchainTaskId)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", decodeCallError(err))
+	}
+
+	tw.recordSubmitted(taskId, tx)
+	tw.logger.Sugar().Infow("✅ Transaction sent to hook contract",
+		"txHash", tx.Hash().Hex(),
+		"nonce", tx.Nonce(),
+		"taskId", taskId,
+		"onchainTaskId", onchainTaskId,
+	)
+
+	tw.logger.Sugar().Infow("⏳ Waiting for receipt",
+		"txHash", tx.Hash().Hex(),
+		"confirmations", tw.confirmations,
+		"timeout", tw.receiptTimeout,
+	)
+	outcome, err := tw.waitForRebalance(ctx, tw.l2Client, &pendingTx{
+		taskId: taskId,
+		poolId: pool.Id,
+		from:   auth.From,
+		signer: auth.Signer,
+		budget: pool.MaxTxCost,
+		sent:   []*types.Transaction{tx},
+	}, expectedPositions)
+	if outcome != nil {
+		if err == nil {
+			tw.transition(taskId, StateConfirmed, func(st *StoredTask) { st.Outcome = outcome })
+		}
+		tw.logger.Sugar().Infow("🔗 Task settled on-chain",
+			"taskId", taskId,
+			"taskHash", taskHash.Hex(),
+			"onchainTaskId", onchainTaskId,
+			"txHash", outcome.TxHash.Hex(),
+			"blockNumber", outcome.BlockNumber,
+			"logIndex", outcome.LogIndex,
+		)
+	}
+	return outcome, err
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate-config" {
+		os.Exit(validateConfigCommand(os.Args[2:], os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "task" {
+		os.Exit(taskCommand(os.Args[2:], os.Stdout))
+	}
+
 	ctx := context.Background()
 	l, _ := zap.NewProduction()
 
-	w := NewTaskWorker(l)
+	cfg, err := LoadPerformerConfig(os.Getenv("PERFORMER_CONFIG"))
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		l.Fatal("Refusing to start with an invalid performer config", zap.Error(err))
+	}
+	for _, m := range cfg.Missing() {
+		l.Sugar().Warnw("Performer setting not configured", "setting", m, "profile", cfg.Context.Performer.Profile)
+	}
+
+	w := NewTaskWorker(l, cfg)
+
+	report := w.selfCheck(ctx, cfg)
+	fmt.Fprint(os.Stderr, report)
+	if report.Failed() {
+		l.Fatal("Refusing to serve tasks, startup self-checks failed", zap.String("report", report.String()))
+	}
 
 	pp, err := server.NewPonosPerformerWithRpcServer(&server.PonosPerformerConfig{
-		Port:    8080,
-		Timeout: 5 * time.Second,
+		Port:    cfg.Context.Performer.Port,
+		Timeout: cfg.Context.Performer.Timeout,
 	}, w, l)
 	if err != nil {
 		panic(fmt.Errorf("failed to create performer: %w", err))
@@ -241,4 +635,4 @@ func main() {
 	if err := pp.Start(ctx); err != nil {
 		panic(err)
 	}
-}
\ No newline at end of file
+}
@@ -1,9 +1,15 @@
 package main
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/taskresult"
 	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"github.com/ethereum/go-ethereum/common"
 	"go.uber.org/zap"
 )
 
@@ -17,11 +23,44 @@ func Test_TaskRequestPayload(t *testing.T) {
 		t.Errorf("Failed to create logger: %v", err)
 	}
 
-	taskWorker := NewTaskWorker(logger)
+	poolsConfig := filepath.Join(t.TempDir(), "pools.yaml")
+	err = os.WriteFile(poolsConfig, []byte(`pools:
+  - name: test
+    currency0: "0x8C4c13856e935d33c0d3C3EF5623F2339f17d4f5"
+    currency1: "0xfbBB81A58049F92C340F00006D6B1BCbDfD5ec0d"
+    lst: "0x8C4c13856e935d33c0d3C3EF5623F2339f17d4f5"
+    fee: 3000
+    tick_spacing: 60
+`), 0o600)
+	if err != nil {
+		t.Fatalf("Failed to write pools config: %v", err)
+	}
+	t.Setenv("POOLS_CONFIG", poolsConfig)
+	t.Setenv("HOOK_ADDRESS", testHookAddress.Hex())
+	t.Setenv("TASK_STORE_PATH", filepath.Join(t.TempDir(), "tasks.db"))
+
+	cfg, err := LoadPerformerConfig("")
+	if err != nil {
+		t.Fatalf("Failed to load performer config: %v", err)
+	}
+	taskWorker := NewTaskWorker(logger, cfg)
+	defer taskWorker.store.Close()
+	poolId := testPoolId(t, taskWorker.pools)
+
+	payload, err := EncodeTaskPayload(&RebalanceTaskData{
+		PoolId:          poolId,
+		YieldBps:        50,
+		CumulativeYield: 50,
+		PositionCount:   1,
+		Timestamp:       uint64(time.Now().Unix()),
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode payload: %v", err)
+	}
 
 	taskRequest := &performerV1.TaskRequest{
 		TaskId:  []byte("test-task-id"),
-		Payload: []byte("test-data"),
+		Payload: payload,
 	}
 
 	err = taskWorker.ValidateTask(taskRequest)
@@ -31,8 +70,37 @@ func Test_TaskRequestPayload(t *testing.T) {
 
 	resp, err := taskWorker.HandleTask(taskRequest)
 	if err != nil {
-		t.Errorf("HandleTask failed: %v", err)
+		t.Fatalf("HandleTask failed: %v", err)
 	}
 
 	t.Logf("Response: %v", resp)
+
+	result, err := taskresult.Decode(resp.Result)
+	if err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if result.PoolId != common.Hash(poolId) || result.TaskId != TaskHash(taskRequest.TaskId) {
+		t.Errorf("result = %+v, want pool %x and task %s", result, poolId, TaskHash(taskRequest.TaskId).Hex())
+	}
+	if result.TickShift != 60 {
+		t.Errorf("tickShift = %d, want 60", result.TickShift)
+	}
+
+	// A redelivered task returns the stored result without being re-planned,
+	// even though the payload no longer validates.
+	taskRequest.Payload = nil
+	if err := taskWorker.ValidateTask(taskRequest); err != nil {
+		t.Errorf("ValidateTask of redelivered task failed: %v", err)
+	}
+	again, err := taskWorker.HandleTask(taskRequest)
+	if err != nil {
+		t.Fatalf("HandleTask of redelivered task failed: %v", err)
+	}
+	if !bytes.Equal(again.Result, resp.Result) {
+		t.Errorf("redelivered result differs from the stored one")
+	}
+	stored, err := taskWorker.store.Get(taskRequest.TaskId)
+	if err != nil || stored == nil || stored.State != StatePlanned {
+		t.Errorf("stored task = (%+v, %v), want planned", stored, err)
+	}
 }
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// nonceBackend is the subset of the L2 client needed to track the operator nonce.
+type nonceBackend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out operator nonces in order to concurrent tasks. It
+// starts unsynced and reads the chain's pending nonce on first use, so a
+// restarted performer picks up where the previous process left off.
+type NonceManager struct {
+	logger  *zap.Logger
+	backend nonceBackend
+	account common.Address
+
+	mu     sync.Mutex
+	synced bool
+	next   uint64
+	// reserved holds nonces handed out whose transaction has not been
+	// reported sent or failed yet.
+	reserved map[uint64]struct{}
+	// broadcast holds nonces whose transaction reached the node and is not
+	// yet below the chain's pending nonce. A node reports the gap below them
+	// as its pending nonce, so a resync must not hand them out again.
+	broadcast map[uint64]struct{}
+}
+
+// NewNonceManager returns a manager for account's nonces.
+func NewNonceManager(logger *zap.Logger, backend nonceBackend, account common.Address) *NonceManager {
+	return &NonceManager{
+		logger:    logger,
+		backend:   backend,
+		account:   account,
+		reserved:  make(map[uint64]struct{}),
+		broadcast: make(map[uint64]struct{}),
+	}
+}
+
+// Reserve returns the next unused nonce. Every reserved nonce must be
+// released with Done once the transaction was sent or abandoned.
+func (m *NonceManager) Reserve(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.synced {
+		if err := m.syncLocked(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	for {
+		n := m.next
+		m.next++
+		if !m.takenLocked(n) {
+			m.reserved[n] = struct{}{}
+			return n, nil
+		}
+	}
+}
+
+// Done releases nonce. sendErr is the error from broadcasting the
+// transaction, nil if it reached the node. A nonce that was rejected as
+// already used triggers a resync; a nonce held by a pending transaction the
+// node would not replace is skipped; a nonce that never reached the node
+// leaves a gap, which is closed by handing it out again.
+func (m *NonceManager) Done(nonce uint64, sendErr error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.reserved, nonce)
+	if sendErr == nil {
+		m.broadcast[nonce] = struct{}{}
+		return
+	}
+
+	if isNonceTooLow(sendErr) {
+		m.logger.Sugar().Warnw("Nonce already used on chain, resyncing",
+			"account", m.account.Hex(),
+			"nonce", nonce,
+			"error", sendErr,
+		)
+		m.synced = false
+		return
+	}
+
+	if isReplacementUnderpriced(sendErr) {
+		// The nonce is valid but another pending transaction holds it and
+		// pays more. Resyncing would not free it, so it is never reissued.
+		m.logger.Sugar().Warnw("Nonce held by a pending transaction, skipping it",
+			"account", m.account.Hex(),
+			"nonce", nonce,
+			"error", sendErr,
+		)
+		m.broadcast[nonce] = struct{}{}
+		return
+	}
+
+	if nonce+1 == m.next {
+		m.next = nonce
+		return
+	}
+	m.logger.Sugar().Warnw("Nonce gap after failed send, resyncing",
+		"account", m.account.Hex(),
+		"nonce", nonce,
+		"next", m.next,
+	)
+	m.synced = false
+}
+
+// Confirmed records that a transaction with nonce was mined. Every nonce up
+// to it is used on chain, so those broadcast entries are dropped. A nil
+// manager ignores the call.
+func (m *NonceManager) Confirmed(nonce uint64) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for n := range m.broadcast {
+		if n <= nonce {
+			delete(m.broadcast, n)
+		}
+	}
+}
+
+// takenLocked reports whether n is reserved or was already broadcast.
+func (m *NonceManager) takenLocked(n uint64) bool {
+	if _, ok := m.reserved[n]; ok {
+		return true
+	}
+	_, ok := m.broadcast[n]
+	return ok
+}
+
+// Resync discards the local nonce and reads the chain's pending nonce.
+func (m *NonceManager) Resync(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.syncLocked(ctx)
+}
+
+func (m *NonceManager) syncLocked(ctx context.Context) error {
+	pending, err := m.backend.PendingNonceAt(ctx, m.account)
+	if err != nil {
+		return fmt.Errorf("failed to read pending nonce for %s: %w", m.account.Hex(), err)
+	}
+
+	m.logger.Sugar().Infow("Synced operator nonce from chain",
+		"account", m.account.Hex(),
+		"local", m.next,
+		"chain", pending,
+	)
+	m.next = pending
+	m.synced = true
+	for n := range m.broadcast {
+		if n < pending {
+			delete(m.broadcast, n)
+		}
+	}
+	return nil
+}
+
+// sendWithNonce signs and sends a transaction with a nonce from the worker's
+// NonceManager. If the node reports the nonce as already used or held by a
+// pending transaction, the send is retried once with a fresh nonce.
+func (tw *TaskWorker) sendWithNonce(ctx context.Context, auth *bind.TransactOpts, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	for attempt := 1; ; attempt++ {
+		nonce, err := tw.nonces.Reserve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		auth.Nonce = new(big.Int).SetUint64(nonce)
+
+		tx, err := send(auth)
+		tw.nonces.Done(nonce, err)
+		if err != nil && (isNonceTooLow(err) || isReplacementUnderpriced(err)) && attempt == 1 {
+			tw.logger.Sugar().Warnw("Nonce rejected by node, retrying with a resynced nonce",
+				"nonce", nonce,
+				"error", err,
+			)
+			continue
+		}
+		return tx, err
+	}
+}
+
+// isNonceTooLow reports whether a send error means the nonce was already
+// mined.
+func isNonceTooLow(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "nonce too low")
+}
+
+// isReplacementUnderpriced reports whether a send error means a pending
+// transaction holds the nonce and the new one does not pay enough to replace
+// it. That is a fee problem, not a stale nonce.
+func isReplacementUnderpriced(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "replacement transaction underpriced")
+}
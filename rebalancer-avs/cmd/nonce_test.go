@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+var testOperator = common.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+
+// chainWithPending returns a fake chain whose pending nonce is n.
+func chainWithPending(n int) *fakeChain {
+	chain := newFakeChain(10)
+	for i := 0; i < n; i++ {
+		chain.sent = append(chain.sent, testTx(uint64(i)))
+	}
+	return chain
+}
+
+func reserve(t *testing.T, m *NonceManager) uint64 {
+	t.Helper()
+	n, err := m.Reserve(context.Background())
+	if err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	return n
+}
+
+func Test_NonceManagerConcurrentReserve(t *testing.T) {
+	m := NewNonceManager(zap.NewNop(), chainWithPending(3), testOperator)
+
+	const workers = 50
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		got = make(map[uint64]bool)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := m.Reserve(context.Background())
+			if err != nil {
+				t.Errorf("Reserve failed: %v", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if got[n] {
+				t.Errorf("nonce %d handed out twice", n)
+			}
+			got[n] = true
+		}()
+	}
+	wg.Wait()
+
+	for n := uint64(3); n < 3+workers; n++ {
+		if !got[n] {
+			t.Errorf("nonce %d never handed out", n)
+		}
+	}
+}
+
+func Test_NonceManagerRestartReconciles(t *testing.T) {
+	chain := chainWithPending(7)
+
+	m := NewNonceManager(zap.NewNop(), chain, testOperator)
+	if n := reserve(t, m); n != 7 {
+		t.Fatalf("first nonce = %d, want chain pending nonce 7", n)
+	}
+}
+
+func Test_NonceManagerDone(t *testing.T) {
+	errSend := errors.New("connection refused")
+
+	t.Run("failed last nonce is reused", func(t *testing.T) {
+		m := NewNonceManager(zap.NewNop(), chainWithPending(0), testOperator)
+		reserve(t, m)
+		n := reserve(t, m)
+		m.Done(n, errSend)
+
+		if got := reserve(t, m); got != n {
+			t.Fatalf("nonce = %d, want reused %d", got, n)
+		}
+	})
+
+	t.Run("gap is closed around reserved nonces", func(t *testing.T) {
+		m := NewNonceManager(zap.NewNop(), chainWithPending(0), testOperator)
+		gap := reserve(t, m)
+		reserve(t, m)
+		reserve(t, m)
+		m.Done(gap, errSend)
+
+		if got := reserve(t, m); got != gap {
+			t.Fatalf("nonce = %d, want gap %d", got, gap)
+		}
+		if got := reserve(t, m); got != 3 {
+			t.Fatalf("nonce = %d, want 3 after skipping reserved nonces", got)
+		}
+	})
+
+	t.Run("nonce too low resyncs", func(t *testing.T) {
+		chain := chainWithPending(0)
+		m := NewNonceManager(zap.NewNop(), chain, testOperator)
+		n := reserve(t, m)
+
+		// Another process used the key meanwhile.
+		chain.sent = append(chain.sent, testTx(0), testTx(1), testTx(2))
+		m.Done(n, errors.New("nonce too low: next nonce 3, tx nonce 0"))
+
+		if got := reserve(t, m); got != 3 {
+			t.Fatalf("nonce = %d, want resynced 3", got)
+		}
+	})
+
+	t.Run("gap resync skips broadcast nonces", func(t *testing.T) {
+		chain := chainWithPending(0)
+		m := NewNonceManager(zap.NewNop(), chain, testOperator)
+		gap := reserve(t, m)
+		for i := 0; i < 2; i++ {
+			n := reserve(t, m)
+			chain.sent = append(chain.sent, testTx(n))
+			m.Done(n, nil)
+		}
+		// The node's pending nonce stays at the gap.
+		chain.sent = nil
+		m.Done(gap, errSend)
+
+		if got := reserve(t, m); got != gap {
+			t.Fatalf("nonce = %d, want gap %d", got, gap)
+		}
+		if got := reserve(t, m); got != 3 {
+			t.Fatalf("nonce = %d, want 3 after skipping broadcast nonces", got)
+		}
+	})
+
+	t.Run("replacement underpriced skips nonce", func(t *testing.T) {
+		chain := chainWithPending(0)
+		m := NewNonceManager(zap.NewNop(), chain, testOperator)
+		n := reserve(t, m)
+		m.Done(n, errors.New("replacement transaction underpriced"))
+
+		if got := reserve(t, m); got != n+1 {
+			t.Fatalf("nonce = %d, want %d", got, n+1)
+		}
+		if !m.synced {
+			t.Error("underpriced send triggered a resync")
+		}
+	})
+
+	t.Run("confirmed nonces are pruned", func(t *testing.T) {
+		m := NewNonceManager(zap.NewNop(), chainWithPending(0), testOperator)
+		for i := 0; i < 3; i++ {
+			m.Done(reserve(t, m), nil)
+		}
+		m.Confirmed(1)
+
+		if len(m.broadcast) != 1 {
+			t.Fatalf("broadcast = %v, want only nonce 2", m.broadcast)
+		}
+		if _, ok := m.broadcast[2]; !ok {
+			t.Errorf("unconfirmed nonce 2 was pruned")
+		}
+	})
+
+	t.Run("sent nonce is not reused", func(t *testing.T) {
+		m := NewNonceManager(zap.NewNop(), chainWithPending(0), testOperator)
+		n := reserve(t, m)
+		m.Done(n, nil)
+
+		if got := reserve(t, m); got != n+1 {
+			t.Fatalf("nonce = %d, want %d", got, n+1)
+		}
+	})
+}
+
+func Test_SendWithNonceRetriesNonceTooLow(t *testing.T) {
+	chain := chainWithPending(2)
+	tw := &TaskWorker{logger: zap.NewNop(), nonces: NewNonceManager(zap.NewNop(), chain, testOperator)}
+
+	var nonces []uint64
+	tx, err := tw.sendWithNonce(context.Background(), &bind.TransactOpts{}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		nonces = append(nonces, opts.Nonce.Uint64())
+		if len(nonces) == 1 {
+			chain.sent = append(chain.sent, testTx(2))
+			return nil, errors.New("nonce too low")
+		}
+		return testTx(opts.Nonce.Uint64()), nil
+	})
+	if err != nil {
+		t.Fatalf("sendWithNonce failed: %v", err)
+	}
+	if len(nonces) != 2 || nonces[0] != 2 || nonces[1] != 3 {
+		t.Fatalf("attempted nonces %v, want [2 3]", nonces)
+	}
+	if tx.Nonce() != 3 {
+		t.Fatalf("tx nonce = %d, want 3", tx.Nonce())
+	}
+}
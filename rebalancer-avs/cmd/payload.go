@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// PayloadVersionV1 is the first task payload layout. It mirrors the fields of
+// the hook's RebalanceRequested event, prefixed with a version word:
+//
+//	abi.encode(
+//	    uint8   version,
+//	    bytes32 poolId,
+//	    uint256 yieldAmount,
+//	    uint256 yieldBps,
+//	    uint256 cumulativeYieldBps,
+//	    uint256 positionsToRebalance,
+//	    uint256 currentStETHBalance,
+//	    uint256 timestamp
+//	)
+const PayloadVersionV1 uint8 = 1
+
+var (
+	// ErrUnsupportedPayloadVersion is returned when the payload version word is unknown.
+	ErrUnsupportedPayloadVersion = errors.New("unsupported payload version")
+	// ErrMalformedPayload is returned when the payload does not match the declared layout.
+	ErrMalformedPayload = errors.New("malformed payload")
+)
+
+// PayloadError reports a task payload that could not be decoded.
+type PayloadError struct {
+	Version uint8
+	Err     error
+}
+
+func (e *PayloadError) Error() string {
+	return fmt.Sprintf("invalid task payload (version %d): %v", e.Version, e.Err)
+}
+
+func (e *PayloadError) Unwrap() error {
+	return e.Err
+}
+
+func (e *PayloadError) Is(target error) bool { return target == ErrMalformedTask }
+
+var (
+	uint8Type, _   = abi.NewType("uint8", "", nil)
+	uint256Type, _ = abi.NewType("uint256", "", nil)
+	bytes32Type, _ = abi.NewType("bytes32", "", nil)
+
+	payloadV1Args = abi.Arguments{
+		{Name: "version", Type: uint8Type},
+		{Name: "poolId", Type: bytes32Type},
+		{Name: "yieldAmount", Type: uint256Type},
+		{Name: "yieldBps", Type: uint256Type},
+		{Name: "cumulativeYieldBps", Type: uint256Type},
+		{Name: "positionsToRebalance", Type: uint256Type},
+		{Name: "currentStETHBalance", Type: uint256Type},
+		{Name: "timestamp", Type: uint256Type},
+	}
+)
+
+type RebalanceTaskData struct {
+	Version         uint8
+	PoolId          [32]byte
+	YieldAmount     *big.Int
+	YieldBps        uint64
+	CumulativeYield uint64
+	PositionCount   uint64
+	LSTBalance      *big.Int
+	Timestamp       uint64
+}
+
+// EncodeTaskPayload ABI-encodes the task data using the V1 payload layout.
+func EncodeTaskPayload(d *RebalanceTaskData) ([]byte, error) {
+	yieldAmount := d.YieldAmount
+	if yieldAmount == nil {
+		yieldAmount = new(big.Int)
+	}
+	lstBalance := d.LSTBalance
+	if lstBalance == nil {
+		lstBalance = new(big.Int)
+	}
+
+	return payloadV1Args.Pack(
+		PayloadVersionV1,
+		d.PoolId,
+		yieldAmount,
+		new(big.Int).SetUint64(d.YieldBps),
+		new(big.Int).SetUint64(d.CumulativeYield),
+		new(big.Int).SetUint64(d.PositionCount),
+		lstBalance,
+		new(big.Int).SetUint64(d.Timestamp),
+	)
+}
+
+// DecodeTaskPayload decodes a task payload into RebalanceTaskData. Any failure
+// is returned as a *PayloadError.
+func DecodeTaskPayload(payload []byte) (*RebalanceTaskData, error) {
+	if len(payload) < 32 {
+		return nil, &PayloadError{Err: fmt.Errorf("%w: %d bytes is too short for a version word", ErrMalformedPayload, len(payload))}
+	}
+
+	version := new(big.Int).SetBytes(payload[:32])
+	if !version.IsUint64() || version.Uint64() > 255 {
+		return nil, &PayloadError{Err: fmt.Errorf("%w: version word %s", ErrMalformedPayload, version)}
+	}
+
+	switch v := uint8(version.Uint64()); v {
+	case PayloadVersionV1:
+		return decodeTaskPayloadV1(payload)
+	default:
+		return nil, &PayloadError{Version: v, Err: ErrUnsupportedPayloadVersion}
+	}
+}
+
+func decodeTaskPayloadV1(payload []byte) (*RebalanceTaskData, error) {
+	if want := len(payloadV1Args) * 32; len(payload) != want {
+		return nil, &PayloadError{
+			Version: PayloadVersionV1,
+			Err:     fmt.Errorf("%w: expected %d bytes, got %d", ErrMalformedPayload, want, len(payload)),
+		}
+	}
+
+	values, err := payloadV1Args.Unpack(payload)
+	if err != nil {
+		return nil, &PayloadError{Version: PayloadVersionV1, Err: fmt.Errorf("%w: %v", ErrMalformedPayload, err)}
+	}
+
+	data := &RebalanceTaskData{
+		Version:     PayloadVersionV1,
+		PoolId:      values[1].([32]byte),
+		YieldAmount: values[2].(*big.Int),
+		LSTBalance:  values[6].(*big.Int),
+	}
+
+	uint64Fields := []struct {
+		name  string
+		value *big.Int
+		dst   *uint64
+	}{
+		{"yieldBps", values[3].(*big.Int), &data.YieldBps},
+		{"cumulativeYieldBps", values[4].(*big.Int), &data.CumulativeYield},
+		{"positionsToRebalance", values[5].(*big.Int), &data.PositionCount},
+		{"timestamp", values[7].(*big.Int), &data.Timestamp},
+	}
+	for _, f := range uint64Fields {
+		if !f.value.IsUint64() {
+			return nil, &PayloadError{
+				Version: PayloadVersionV1,
+				Err:     fmt.Errorf("%w: %s %s overflows uint64", ErrMalformedPayload, f.name, f.value),
+			}
+		}
+		*f.dst = f.value.Uint64()
+	}
+
+	return data, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func Test_TaskPayloadRoundTrip(t *testing.T) {
+	want := &RebalanceTaskData{
+		PoolId:          [32]byte{0xaa, 0xbb},
+		YieldAmount:     big.NewInt(5e15),
+		YieldBps:        50,
+		CumulativeYield: 120,
+		PositionCount:   3,
+		LSTBalance:      new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18)),
+		Timestamp:       1_700_000_000,
+	}
+
+	payload, err := EncodeTaskPayload(want)
+	if err != nil {
+		t.Fatalf("EncodeTaskPayload failed: %v", err)
+	}
+
+	got, err := DecodeTaskPayload(payload)
+	if err != nil {
+		t.Fatalf("DecodeTaskPayload failed: %v", err)
+	}
+
+	if got.Version != PayloadVersionV1 {
+		t.Errorf("version = %d, want %d", got.Version, PayloadVersionV1)
+	}
+	if got.PoolId != want.PoolId {
+		t.Errorf("poolId = %x, want %x", got.PoolId, want.PoolId)
+	}
+	if got.YieldAmount.Cmp(want.YieldAmount) != 0 || got.LSTBalance.Cmp(want.LSTBalance) != 0 {
+		t.Errorf("amounts = (%s, %s), want (%s, %s)", got.YieldAmount, got.LSTBalance, want.YieldAmount, want.LSTBalance)
+	}
+	if got.YieldBps != want.YieldBps || got.CumulativeYield != want.CumulativeYield ||
+		got.PositionCount != want.PositionCount || got.Timestamp != want.Timestamp {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+}
+
+func Test_DecodeTaskPayloadErrors(t *testing.T) {
+	valid, err := EncodeTaskPayload(&RebalanceTaskData{YieldBps: 10, PositionCount: 1})
+	if err != nil {
+		t.Fatalf("EncodeTaskPayload failed: %v", err)
+	}
+
+	unknownVersion := append([]byte(nil), valid...)
+	unknownVersion[31] = 2
+
+	overflow := append([]byte(nil), valid...)
+	overflow[3*32] = 0x01 // yieldBps high byte
+
+	tests := []struct {
+		name    string
+		payload []byte
+		wantErr error
+	}{
+		{"empty", nil, ErrMalformedPayload},
+		{"not abi", []byte("test-data"), ErrMalformedPayload},
+		{"truncated", valid[:len(valid)-1], ErrMalformedPayload},
+		{"trailing bytes", append(append([]byte(nil), valid...), 0x00), ErrMalformedPayload},
+		{"unknown version", unknownVersion, ErrUnsupportedPayloadVersion},
+		{"uint64 overflow", overflow, ErrMalformedPayload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeTaskPayload(tt.payload)
+			var payloadErr *PayloadError
+			if !errors.As(err, &payloadErr) {
+				t.Fatalf("expected *PayloadError, got %v", err)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
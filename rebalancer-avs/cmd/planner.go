@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SkipReason explains why a plan leaves a position where it is.
+type SkipReason string
+
+const (
+	// SkipNoShift: the tick shift is zero, so nothing moves.
+	SkipNoShift SkipReason = "no_shift"
+	// SkipEmpty: the position has no liquidity.
+	SkipEmpty SkipReason = "empty"
+	// SkipCollapsed: the shifted range is empty after _boundTick.
+	SkipCollapsed SkipReason = "collapsed"
+	// SkipClamped: _boundTick moves a tick off the spacing grid, v4 rejects
+	// the new range and the hook restores the position.
+	SkipClamped SkipReason = "clamped"
+	// SkipMisaligned: the shifted ticks are not multiples of the spacing.
+	SkipMisaligned SkipReason = "misaligned"
+)
+
+var skipReasons = map[PositionOutcome]SkipReason{
+	PositionEmpty:      SkipEmpty,
+	PositionCollapsed:  SkipCollapsed,
+	PositionClamped:    SkipClamped,
+	PositionMisaligned: SkipMisaligned,
+}
+
+// PositionPlan is the planned target range of one hook position. Skipped
+// positions keep their current range as the target.
+type PositionPlan struct {
+	Index           int            `json:"index"`
+	Owner           common.Address `json:"owner"`
+	Liquidity       *big.Int       `json:"liquidity"`
+	TickLower       int32          `json:"tickLower"`
+	TickUpper       int32          `json:"tickUpper"`
+	TargetTickLower int32          `json:"targetTickLower"`
+	TargetTickUpper int32          `json:"targetTickUpper"`
+	Skip            SkipReason     `json:"skip,omitempty"`
+}
+
+// RebalancePlan is the per-position plan for one task. It is what the
+// performer expects executeRebalance to do with tickShift.
+type RebalancePlan struct {
+	PoolId      common.Hash `json:"poolId"`
+	TickShift   int32       `json:"tickShift"`
+	TickSpacing int32       `json:"tickSpacing"`
+	// BlockNumber and BlockHash identify the block the positions were read
+	// at. BlockNumber is nil when no block was pinned.
+	BlockNumber *big.Int       `json:"blockNumber,omitempty"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	Positions   []PositionPlan `json:"positions"`
+	Rebalanced  int            `json:"rebalanced"`
+}
+
+// PlanRebalance plans every position for a uniform tickShift, following
+// executeRebalance's per-position logic.
+func PlanRebalance(poolId common.Hash, positions []lstrebalancehook.LSTrebalanceHookLpPosition, tickShift, spacing int32) *RebalancePlan {
+	plan := &RebalancePlan{
+		PoolId:      poolId,
+		TickShift:   tickShift,
+		TickSpacing: spacing,
+		Positions:   make([]PositionPlan, len(positions)),
+	}
+
+	for i, f := range ForecastPositions(positions, tickShift, spacing) {
+		p := PositionPlan{
+			Index:           f.Index,
+			Owner:           f.Owner,
+			Liquidity:       new(big.Int).Set(positions[i].Liquidity),
+			TickLower:       f.TickLower,
+			TickUpper:       f.TickUpper,
+			TargetTickLower: f.TickLower,
+			TargetTickUpper: f.TickUpper,
+		}
+		switch {
+		case f.Outcome != PositionShifted:
+			p.Skip = skipReasons[f.Outcome]
+		case tickShift == 0:
+			p.Skip = SkipNoShift
+		default:
+			p.TargetTickLower, p.TargetTickUpper = f.NewTickLower, f.NewTickUpper
+			plan.Rebalanced++
+		}
+		plan.Positions[i] = p
+	}
+	return plan
+}
+
+// Skipped returns the positions the plan leaves in place.
+func (p *RebalancePlan) Skipped() []PositionPlan {
+	var skipped []PositionPlan
+	for _, pos := range p.Positions {
+		if pos.Skip != "" {
+			skipped = append(skipped, pos)
+		}
+	}
+	return skipped
+}
+
+// Worthwhile reports whether executing the plan moves any liquidity.
+func (p *RebalancePlan) Worthwhile() bool {
+	return p.TickShift != 0 && p.Rebalanced > 0
+}
+
+// planRebalance plans the task against the snapshot's positions, or reads them
+// from the hook at the latest L2 header when there is no snapshot, as the
+// executor does to check a certified result before sending it. Without a hook
+// the plan has no positions.
+func (tw *TaskWorker) planRebalance(ctx context.Context, pool *Pool, tickShift int32, snapshot *PoolSnapshot) (*RebalancePlan, error) {
+	var (
+		positions []lstrebalancehook.LSTrebalanceHookLpPosition
+		block     *big.Int
+		blockHash common.Hash
+	)
+	switch {
+	case snapshot != nil:
+		positions, block, blockHash = snapshot.Positions, snapshot.BlockNumber, snapshot.BlockHash
+	case tw.hook != nil && tw.l2Client != nil:
+		head, err := tw.l2Client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get L2 head: %w", err)
+		}
+		block, blockHash = head.Number, head.Hash()
+		positions, err = tw.hook.GetPositions(&bind.CallOpts{Context: ctx, BlockNumber: block}, pool.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read positions for pool %s: %w", pool.Id.Hex(), err)
+		}
+	}
+
+	plan := PlanRebalance(pool.Id, positions, tickShift, int32(pool.Key.TickSpacing.Int64()))
+	plan.BlockNumber, plan.BlockHash = block, blockHash
+
+	for _, p := range plan.Skipped() {
+		if p.Skip == SkipNoShift {
+			continue
+		}
+		tw.logger.Sugar().Warnw("⚠️  Position will not be rebalanced",
+			"poolId", pool.Id.Hex(),
+			"index", p.Index,
+			"owner", p.Owner.Hex(),
+			"tickLower", p.TickLower,
+			"tickUpper", p.TickUpper,
+			"reason", p.Skip,
+		)
+	}
+	tw.logger.Sugar().Infow("🗺️  Rebalance plan",
+		"poolId", pool.Id.Hex(),
+		"tickShift", tickShift,
+		"positions", len(plan.Positions),
+		"rebalanced", plan.Rebalanced,
+		"plan", plan,
+	)
+	return plan, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func testPosition(lower, upper, liquidity int64) lstrebalancehook.LSTrebalanceHookLpPosition {
+	return lstrebalancehook.LSTrebalanceHookLpPosition{
+		Owner:     common.HexToAddress("0x1000000000000000000000000000000000000001"),
+		TickLower: big.NewInt(lower),
+		TickUpper: big.NewInt(upper),
+		Liquidity: big.NewInt(liquidity),
+	}
+}
+
+func Test_PlanRebalance(t *testing.T) {
+	positions := []lstrebalancehook.LSTrebalanceHookLpPosition{
+		testPosition(-600, 600, 1e6),
+		testPosition(-120, 120, 0),
+		testPosition(887100, 887220, 1e6),
+		testPosition(0, 240, 5e5),
+	}
+
+	plan := PlanRebalance(common.Hash{0x01}, positions, 120, 60)
+
+	if plan.Rebalanced != 2 || !plan.Worthwhile() {
+		t.Fatalf("rebalanced = %d, worthwhile = %v, want 2 and true", plan.Rebalanced, plan.Worthwhile())
+	}
+
+	want := []struct {
+		lower, upper int32
+		skip         SkipReason
+	}{
+		{-480, 720, ""},
+		{-120, 120, SkipEmpty},
+		{887100, 887220, SkipClamped},
+		{120, 360, ""},
+	}
+	for i, w := range want {
+		p := plan.Positions[i]
+		if p.TargetTickLower != w.lower || p.TargetTickUpper != w.upper || p.Skip != w.skip {
+			t.Errorf("position %d = [%d, %d] %q, want [%d, %d] %q",
+				i, p.TargetTickLower, p.TargetTickUpper, p.Skip, w.lower, w.upper, w.skip)
+		}
+	}
+
+	skipped := plan.Skipped()
+	if len(skipped) != 2 || skipped[0].Index != 1 || skipped[1].Index != 2 {
+		t.Errorf("unexpected skipped positions %+v", skipped)
+	}
+}
+
+func Test_PlanRebalanceNotWorthwhile(t *testing.T) {
+	tests := []struct {
+		name      string
+		positions []lstrebalancehook.LSTrebalanceHookLpPosition
+		shift     int32
+		skip      SkipReason
+	}{
+		{"zero shift", []lstrebalancehook.LSTrebalanceHookLpPosition{testPosition(-600, 600, 1e6)}, 0, SkipNoShift},
+		{"only empty", []lstrebalancehook.LSTrebalanceHookLpPosition{testPosition(-600, 600, 0)}, 60, SkipEmpty},
+		{"misaligned", []lstrebalancehook.LSTrebalanceHookLpPosition{testPosition(-600, 600, 1e6)}, 49, SkipMisaligned},
+		{"collapsed", []lstrebalancehook.LSTrebalanceHookLpPosition{testPosition(887220, 887280, 1e6)}, 600, SkipCollapsed},
+		{"no positions", nil, 60, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := PlanRebalance(common.Hash{0x01}, tt.positions, tt.shift, 60)
+			if plan.Worthwhile() {
+				t.Fatal("plan should not be worthwhile")
+			}
+			for _, p := range plan.Positions {
+				if p.Skip != tt.skip {
+					t.Errorf("skip = %q, want %q", p.Skip, tt.skip)
+				}
+			}
+		})
+	}
+}
+
+func Test_RebalancePlanJSON(t *testing.T) {
+	plan := PlanRebalance(common.Hash{0x01}, []lstrebalancehook.LSTrebalanceHookLpPosition{
+		testPosition(-600, 600, 1e6),
+		testPosition(-120, 120, 0),
+	}, 60, 60)
+	plan.BlockNumber = big.NewInt(42)
+
+	encoded, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded RebalancePlan
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(plan, &decoded) {
+		t.Errorf("round trip = %+v, want %+v", decoded, *plan)
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultPoolsConfigPath is the default performer.pools_config.
+	defaultPoolsConfigPath = "config/pools.yaml"
+
+	maxFee         = 1<<24 - 1
+	minTickSpacing = 1
+	maxTickSpacing = 1<<15 - 1
+)
+
+var (
+	addressType, _ = abi.NewType("address", "", nil)
+	uint24Type, _  = abi.NewType("uint24", "", nil)
+	int24Type, _   = abi.NewType("int24", "", nil)
+
+	poolKeyArgs = abi.Arguments{
+		{Name: "currency0", Type: addressType},
+		{Name: "currency1", Type: addressType},
+		{Name: "fee", Type: uint24Type},
+		{Name: "tickSpacing", Type: int24Type},
+		{Name: "hooks", Type: addressType},
+	}
+)
+
+// PoolIdOf computes the v4 PoolId, keccak256(abi.encode(key)).
+func PoolIdOf(k lstrebalancehook.PoolKey) (common.Hash, error) {
+	encoded, err := poolKeyArgs.Pack(k.Currency0, k.Currency1, k.Fee, k.TickSpacing, k.Hooks)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to encode pool key: %w", err)
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+// PoolConfig is a single pool entry in the pools config file.
+type PoolConfig struct {
+	Name        string `yaml:"name"`
+	PoolId      string `yaml:"pool_id"`
+	Currency0   string `yaml:"currency0"`
+	Currency1   string `yaml:"currency1"`
+	Fee         uint32 `yaml:"fee"`
+	TickSpacing int32  `yaml:"tick_spacing"`
+	Hooks       string `yaml:"hooks"`
+	// LST is the address of the liquid staking token, either currency0 or
+	// currency1. It sets the direction of yield-driven tick shifts.
+	LST string `yaml:"lst"`
+	// MaxTxCostWei is the most a single rebalance of this pool may cost, in
+	// wei. Empty means no pool budget.
+	MaxTxCostWei string `yaml:"max_tx_cost_wei"`
+	// YieldSource, when set, is used to cross-check the yield reported by tasks.
+	YieldSource *YieldSourceConfig `yaml:"yield_source"`
+}
+
+// PoolsConfig is the layout of the pools config file.
+type PoolsConfig struct {
+	Pools []PoolConfig `yaml:"pools"`
+}
+
+// Pool is a verified registry entry.
+type Pool struct {
+	Name string
+	Id   common.Hash
+	Key  lstrebalancehook.PoolKey
+	// LSTIsCurrency0 is true when the LST is currency0, so yield raises the price.
+	LSTIsCurrency0 bool
+	// MaxTxCost is the pool's gas budget per transaction, nil if unlimited.
+	MaxTxCost *big.Int
+	// YieldSource is the pool's redemption rate source, nil if none.
+	YieldSource *YieldSourceConfig
+}
+
+// PoolRegistry routes task PoolIds to verified PoolKeys.
+type PoolRegistry struct {
+	pools map[common.Hash]*Pool
+}
+
+// LoadPoolRegistry reads and verifies the pools config file at path. Pools
+// without an explicit hooks address are bound to hookAddress; if that is
+// zero too, the pool is rejected.
+func LoadPoolRegistry(path string, hookAddress common.Address) (*PoolRegistry, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pools config: %w", err)
+	}
+
+	var cfg PoolsConfig
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse pools config %s: %w", path, err)
+	}
+
+	return NewPoolRegistry(cfg.Pools, hookAddress)
+}
+
+// NewPoolRegistry verifies every pool entry and builds the registry.
+func NewPoolRegistry(pools []PoolConfig, hookAddress common.Address) (*PoolRegistry, error) {
+	r := &PoolRegistry{pools: make(map[common.Hash]*Pool, len(pools))}
+
+	for i, pc := range pools {
+		pool, err := pc.verify(hookAddress)
+		if err != nil {
+			return nil, fmt.Errorf("pool %d (%s): %w", i, pc.Name, err)
+		}
+		if existing, ok := r.pools[pool.Id]; ok {
+			return nil, fmt.Errorf("pool %d (%s): duplicate pool ID %s, already configured as %s",
+				i, pc.Name, pool.Id.Hex(), existing.Name)
+		}
+		r.pools[pool.Id] = pool
+	}
+
+	return r, nil
+}
+
+func (pc PoolConfig) verify(hookAddress common.Address) (*Pool, error) {
+	currency0, err := parseAddress("currency0", pc.Currency0, true)
+	if err != nil {
+		return nil, err
+	}
+	currency1, err := parseAddress("currency1", pc.Currency1, false)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Compare(currency0.Bytes(), currency1.Bytes()) >= 0 {
+		return nil, fmt.Errorf("currencies out of order: currency0 %s must sort below currency1 %s",
+			currency0.Hex(), currency1.Hex())
+	}
+
+	if pc.Fee > maxFee {
+		return nil, fmt.Errorf("fee %d exceeds uint24", pc.Fee)
+	}
+	if pc.TickSpacing < minTickSpacing || pc.TickSpacing > maxTickSpacing {
+		return nil, fmt.Errorf("tick spacing %d out of range [%d, %d]", pc.TickSpacing, minTickSpacing, maxTickSpacing)
+	}
+
+	lst, err := parseAddress("lst", pc.LST, false)
+	if err != nil {
+		return nil, err
+	}
+	if lst != currency0 && lst != currency1 {
+		return nil, fmt.Errorf("lst %s is neither currency0 nor currency1", lst.Hex())
+	}
+
+	hooks := hookAddress
+	if pc.Hooks != "" {
+		if hooks, err = parseAddress("hooks", pc.Hooks, false); err != nil {
+			return nil, err
+		}
+		if hookAddress != (common.Address{}) && hooks != hookAddress {
+			return nil, fmt.Errorf("hooks %s does not match hook address %s", hooks.Hex(), hookAddress.Hex())
+		}
+	}
+	if hooks == (common.Address{}) {
+		// A zero hooks address derives the PoolId of a hookless pool.
+		return nil, fmt.Errorf("no hooks address: set hook_address (HOOK_ADDRESS) or the pool's hooks")
+	}
+
+	key := lstrebalancehook.PoolKey{
+		Currency0:   currency0,
+		Currency1:   currency1,
+		Fee:         big.NewInt(int64(pc.Fee)),
+		TickSpacing: big.NewInt(int64(pc.TickSpacing)),
+		Hooks:       hooks,
+	}
+	id, err := PoolIdOf(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if pc.PoolId != "" {
+		configured := common.FromHex(pc.PoolId)
+		if len(configured) != common.HashLength {
+			return nil, fmt.Errorf("invalid pool_id %q", pc.PoolId)
+		}
+		if common.BytesToHash(configured) != id {
+			return nil, fmt.Errorf("pool_id %s does not match keccak256(abi.encode(PoolKey)) = %s",
+				pc.PoolId, id.Hex())
+		}
+	}
+
+	var maxTxCost *big.Int
+	if pc.MaxTxCostWei != "" {
+		if maxTxCost, err = parseWei(pc.MaxTxCostWei); err != nil {
+			return nil, fmt.Errorf("max_tx_cost_wei: %w", err)
+		}
+	}
+
+	if pc.YieldSource != nil {
+		if err := pc.YieldSource.verify(); err != nil {
+			return nil, err
+		}
+	}
+
+	name := pc.Name
+	if name == "" {
+		name = id.Hex()
+	}
+
+	return &Pool{
+		Name:           name,
+		Id:             id,
+		Key:            key,
+		LSTIsCurrency0: lst == currency0,
+		MaxTxCost:      maxTxCost,
+		YieldSource:    pc.YieldSource,
+	}, nil
+}
+
+func parseAddress(field, value string, allowZero bool) (common.Address, error) {
+	if !common.IsHexAddress(value) {
+		return common.Address{}, fmt.Errorf("invalid %s address %q", field, value)
+	}
+	addr := common.HexToAddress(value)
+	if !allowZero && addr == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("%s must not be the zero address", field)
+	}
+	return addr, nil
+}
+
+// Lookup returns the pool registered under id.
+func (r *PoolRegistry) Lookup(id common.Hash) (*Pool, bool) {
+	if r == nil {
+		return nil, false
+	}
+	p, ok := r.pools[id]
+	return p, ok
+}
+
+// Len returns the number of registered pools.
+func (r *PoolRegistry) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.pools)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var testHookAddress = common.HexToAddress("0x4444444444444444444444444444444444440AC0")
+
+func testPoolRegistry(t *testing.T) *PoolRegistry {
+	t.Helper()
+
+	r, err := NewPoolRegistry([]PoolConfig{{
+		Name:        "stETH/WETH",
+		Currency0:   "0x8C4c13856e935d33c0d3C3EF5623F2339f17d4f5",
+		Currency1:   "0xfbBB81A58049F92C340F00006D6B1BCbDfD5ec0d",
+		LST:         "0x8C4c13856e935d33c0d3C3EF5623F2339f17d4f5",
+		Fee:         3000,
+		TickSpacing: 60,
+	}}, testHookAddress)
+	if err != nil {
+		t.Fatalf("NewPoolRegistry failed: %v", err)
+	}
+	return r
+}
+
+func testPoolId(t *testing.T, r *PoolRegistry) common.Hash {
+	t.Helper()
+
+	for id := range r.pools {
+		return id
+	}
+	t.Fatal("registry is empty")
+	return common.Hash{}
+}
+
+func Test_PoolKeyToId(t *testing.T) {
+	// Mainnet v4 ETH/USDC 0.05% pool.
+	key := lstrebalancehook.PoolKey{
+		Currency0:   common.Address{},
+		Currency1:   common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+		Fee:         big.NewInt(500),
+		TickSpacing: big.NewInt(10),
+		Hooks:       common.Address{},
+	}
+
+	id, err := PoolIdOf(key)
+	if err != nil {
+		t.Fatalf("PoolIdOf failed: %v", err)
+	}
+
+	want := common.HexToHash("0x21c67e77068de97969ba93d4aab21826d33ca12bb9f565d8496e8fda8a82ca27")
+	if id != want {
+		t.Errorf("PoolIdOf = %s, want %s", id.Hex(), want.Hex())
+	}
+}
+
+func Test_NewPoolRegistry(t *testing.T) {
+	base := PoolConfig{
+		Currency0:   "0x8C4c13856e935d33c0d3C3EF5623F2339f17d4f5",
+		Currency1:   "0xfbBB81A58049F92C340F00006D6B1BCbDfD5ec0d",
+		LST:         "0x8C4c13856e935d33c0d3C3EF5623F2339f17d4f5",
+		Fee:         3000,
+		TickSpacing: 60,
+	}
+	key := lstrebalancehook.PoolKey{
+		Currency0:   common.HexToAddress(base.Currency0),
+		Currency1:   common.HexToAddress(base.Currency1),
+		Fee:         big.NewInt(3000),
+		TickSpacing: big.NewInt(60),
+		Hooks:       testHookAddress,
+	}
+	id, err := PoolIdOf(key)
+	if err != nil {
+		t.Fatalf("PoolIdOf failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(pc *PoolConfig)
+		wantErr string
+	}{
+		{"derived id", func(pc *PoolConfig) {}, ""},
+		{"matching id", func(pc *PoolConfig) { pc.PoolId = id.Hex() }, ""},
+		{"explicit hooks", func(pc *PoolConfig) { pc.Hooks = testHookAddress.Hex() }, ""},
+		{"native currency0", func(pc *PoolConfig) { pc.Currency0, pc.LST = common.Address{}.Hex(), pc.Currency1 }, ""},
+		{"lst currency1", func(pc *PoolConfig) { pc.LST = pc.Currency1 }, ""},
+		{"missing lst", func(pc *PoolConfig) { pc.LST = "" }, "invalid lst"},
+		{"foreign lst", func(pc *PoolConfig) { pc.LST = "0x1111111111111111111111111111111111111111" }, "neither currency0 nor currency1"},
+		{"mismatched id", func(pc *PoolConfig) { pc.PoolId = common.Hash{0x01}.Hex() }, "does not match"},
+		{"short id", func(pc *PoolConfig) { pc.PoolId = "0x1234" }, "invalid pool_id"},
+		{"unordered currencies", func(pc *PoolConfig) { pc.Currency0, pc.Currency1 = pc.Currency1, pc.Currency0 }, "out of order"},
+		{"equal currencies", func(pc *PoolConfig) { pc.Currency1 = pc.Currency0 }, "out of order"},
+		{"bad address", func(pc *PoolConfig) { pc.Currency1 = "0x1234" }, "invalid currency1"},
+		{"fee overflow", func(pc *PoolConfig) { pc.Fee = 1 << 24 }, "exceeds uint24"},
+		{"zero tick spacing", func(pc *PoolConfig) { pc.TickSpacing = 0 }, "tick spacing"},
+		{"foreign hook", func(pc *PoolConfig) { pc.Hooks = "0x1111111111111111111111111111111111111111" }, "does not match hook address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := base
+			tt.mutate(&pc)
+
+			r, err := NewPoolRegistry([]PoolConfig{pc}, testHookAddress)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r.Len() != 1 {
+				t.Fatalf("registry has %d pools, want 1", r.Len())
+			}
+		})
+	}
+
+	t.Run("lookup", func(t *testing.T) {
+		r, err := NewPoolRegistry([]PoolConfig{base}, testHookAddress)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pool, ok := r.Lookup(id)
+		if !ok {
+			t.Fatalf("pool %s not found", id.Hex())
+		}
+		if pool.Key.Hooks != testHookAddress || pool.Key.TickSpacing.Int64() != 60 {
+			t.Errorf("unexpected pool key %+v", pool.Key)
+		}
+		if _, ok := r.Lookup(common.Hash{0x02}); ok {
+			t.Error("lookup of unknown pool succeeded")
+		}
+	})
+
+	t.Run("no hook address", func(t *testing.T) {
+		_, err := NewPoolRegistry([]PoolConfig{base}, common.Address{})
+		if err == nil || !strings.Contains(err.Error(), "no hooks address") {
+			t.Fatalf("expected missing hooks error, got %v", err)
+		}
+
+		pc := base
+		pc.Hooks = testHookAddress.Hex()
+		r, err := NewPoolRegistry([]PoolConfig{pc}, common.Address{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := r.Lookup(id); !ok {
+			t.Errorf("pool %s not found with per-pool hooks", id.Hex())
+		}
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		_, err := NewPoolRegistry([]PoolConfig{base, base}, testHookAddress)
+		if err == nil || !strings.Contains(err.Error(), "duplicate pool ID") {
+			t.Fatalf("expected duplicate pool error, got %v", err)
+		}
+	})
+}
+
+func Test_LoadPoolRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pools.yaml")
+	err := os.WriteFile(path, []byte(`pools:
+  - name: stETH/WETH
+    currency0: "0x8C4c13856e935d33c0d3C3EF5623F2339f17d4f5"
+    currency1: "0xfbBB81A58049F92C340F00006D6B1BCbDfD5ec0d"
+    lst: "0x8C4c13856e935d33c0d3C3EF5623F2339f17d4f5"
+    fee: 3000
+    tick_spacing: 60
+  - name: rETH/WETH
+    currency0: "0x8C4c13856e935d33c0d3C3EF5623F2339f17d4f5"
+    currency1: "0xfbBB81A58049F92C340F00006D6B1BCbDfD5ec0d"
+    lst: "0x8C4c13856e935d33c0d3C3EF5623F2339f17d4f5"
+    fee: 500
+    tick_spacing: 10
+`), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	r, err := LoadPoolRegistry(path, testHookAddress)
+	if err != nil {
+		t.Fatalf("LoadPoolRegistry failed: %v", err)
+	}
+	if r.Len() != 2 {
+		t.Errorf("registry has %d pools, want 2", r.Len())
+	}
+}
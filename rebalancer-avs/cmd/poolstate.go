@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// v4 PoolManager storage layout, see StateLibrary.
+const (
+	// poolsSlot is the PoolManager storage slot of mapping(PoolId => Pool.State).
+	poolsSlot = 6
+	// liquidityOffset is the offset of Pool.State.liquidity from slot0.
+	liquidityOffset = 3
+)
+
+// ErrPoolNotInitialized is returned for a PoolId whose sqrtPriceX96 is zero.
+var ErrPoolNotInitialized = errors.New("pool is not initialized")
+
+var extsloadABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[{
+		"type": "function",
+		"name": "extsload",
+		"stateMutability": "view",
+		"inputs": [
+			{"name": "startSlot", "type": "bytes32"},
+			{"name": "nSlots", "type": "uint256"}
+		],
+		"outputs": [{"name": "values", "type": "bytes32[]"}]
+	}]`))
+	if err != nil {
+		panic(fmt.Errorf("failed to parse extsload ABI: %w", err))
+	}
+	return parsed
+}()
+
+// PoolState is a pool's slot0 and active liquidity, as returned by
+// StateLibrary.getSlot0 and getLiquidity.
+type PoolState struct {
+	SqrtPriceX96 *big.Int
+	Tick         int32
+	ProtocolFee  uint32
+	LPFee        uint32
+	Liquidity    *big.Int
+	// BlockNumber is the block the state was read at, nil for latest.
+	BlockNumber *big.Int
+}
+
+// PoolStateReader reads pool state from the v4 PoolManager with extsload.
+type PoolStateReader struct {
+	caller      bind.ContractCaller
+	poolManager common.Address
+}
+
+// NewPoolStateReader returns a reader for the PoolManager at poolManager.
+func NewPoolStateReader(caller bind.ContractCaller, poolManager common.Address) *PoolStateReader {
+	return &PoolStateReader{caller: caller, poolManager: poolManager}
+}
+
+// PoolStateSlot returns the storage slot of a pool's Pool.State,
+// keccak256(abi.encodePacked(poolId, POOLS_SLOT)).
+func PoolStateSlot(poolId common.Hash) common.Hash {
+	return crypto.Keccak256Hash(poolId.Bytes(), common.BigToHash(big.NewInt(poolsSlot)).Bytes())
+}
+
+// Read returns the state of poolId at block, or at the latest block when
+// block is nil. slot0 and liquidity come from a single extsload call so they
+// are always consistent.
+func (r *PoolStateReader) Read(ctx context.Context, poolId common.Hash, block *big.Int) (*PoolState, error) {
+	input, err := extsloadABI.Pack("extsload", PoolStateSlot(poolId), big.NewInt(liquidityOffset+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack extsload: %w", err)
+	}
+
+	output, err := r.caller.CallContract(ctx, ethereum.CallMsg{To: &r.poolManager, Data: input}, block)
+	if err != nil {
+		return nil, fmt.Errorf("extsload on PoolManager %s failed: %w", r.poolManager.Hex(), err)
+	}
+
+	var slots [][32]byte
+	if err := extsloadABI.UnpackIntoInterface(&slots, "extsload", output); err != nil {
+		return nil, fmt.Errorf("failed to unpack extsload: %w", err)
+	}
+	if len(slots) != liquidityOffset+1 {
+		return nil, fmt.Errorf("extsload returned %d slots, want %d", len(slots), liquidityOffset+1)
+	}
+
+	state := DecodeSlot0(slots[0])
+	if state.SqrtPriceX96.Sign() == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPoolNotInitialized, poolId.Hex())
+	}
+	state.Liquidity = new(big.Int).SetBytes(slots[liquidityOffset][16:])
+	state.BlockNumber = block
+	return state, nil
+}
+
+// DecodeSlot0 unpacks a packed slot0 word:
+// lpFee (24) | protocolFee (24) | tick (24) | sqrtPriceX96 (160).
+func DecodeSlot0(slot [32]byte) *PoolState {
+	word := new(big.Int).SetBytes(slot[:])
+
+	field := func(shift, bits uint) *big.Int {
+		mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), bits), big.NewInt(1))
+		return new(big.Int).And(new(big.Int).Rsh(word, shift), mask)
+	}
+
+	// Sign-extend the 24-bit tick.
+	tick := int32(field(160, 24).Int64()<<8) >> 8
+
+	return &PoolState{
+		SqrtPriceX96: field(0, 160),
+		Tick:         tick,
+		ProtocolFee:  uint32(field(184, 24).Uint64()),
+		LPFee:        uint32(field(208, 24).Uint64()),
+	}
+}
+
+// PoolSnapshot is a pool's state and the hook's positions, read at the same block.
+type PoolSnapshot struct {
+	// State is nil when the PoolManager is unknown.
+	State     *PoolState
+	Positions []lstrebalancehook.LSTrebalanceHookLpPosition
+	// BlockNumber and BlockHash identify the block both were read at.
+	BlockNumber *big.Int
+	BlockHash   common.Hash
+}
+
+// PositionCenter returns the liquidity-weighted mid tick of the positions
+// that hold liquidity, rounded toward zero. It is false when there are none.
+func (s *PoolSnapshot) PositionCenter() (int32, bool) {
+	weighted, total := new(big.Int), new(big.Int)
+	for _, pos := range s.Positions {
+		if pos.Liquidity.Sign() == 0 {
+			continue
+		}
+		mid := new(big.Int).Add(pos.TickLower, pos.TickUpper)
+		mid.Quo(mid, big.NewInt(2))
+		weighted.Add(weighted, mid.Mul(mid, pos.Liquidity))
+		total.Add(total, pos.Liquidity)
+	}
+	if total.Sign() == 0 {
+		return 0, false
+	}
+	return int32(weighted.Quo(weighted, total).Int64()), true
+}
+
+// readPoolSnapshot reads the pool's state and positions at the task's
+// reference block, never at the local head.
+func (tw *TaskWorker) readPoolSnapshot(ctx context.Context, pool *Pool, ref *TaskReference) (*PoolSnapshot, error) {
+	block := ref.Block()
+	snapshot := &PoolSnapshot{BlockNumber: block, BlockHash: ref.BlockHash}
+
+	if tw.poolState != nil {
+		state, err := tw.poolState.Read(ctx, pool.Id, block)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.State = state
+	}
+	positions, err := tw.hook.GetPositions(&bind.CallOpts{Context: ctx, BlockNumber: block}, pool.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read positions for pool %s: %w", pool.Id.Hex(), err)
+	}
+	snapshot.Positions = positions
+	return snapshot, nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var testPoolManager = common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+
+// packSlot0 builds a slot0 word the way Slot0Library stores it.
+func packSlot0(sqrtPriceX96 *big.Int, tick int32, protocolFee, lpFee uint32) [32]byte {
+	word := new(big.Int).Set(sqrtPriceX96)
+	word.Or(word, new(big.Int).Lsh(big.NewInt(int64(uint32(tick)&0xffffff)), 160))
+	word.Or(word, new(big.Int).Lsh(big.NewInt(int64(protocolFee)), 184))
+	word.Or(word, new(big.Int).Lsh(big.NewInt(int64(lpFee)), 208))
+
+	var slot [32]byte
+	word.FillBytes(slot[:])
+	return slot
+}
+
+func Test_PoolStateSlot(t *testing.T) {
+	poolId := common.HexToHash("0x21c67e77068de97969ba93d4aab21826d33ca12bb9f565d8496e8fda8a82ca27")
+
+	want := crypto.Keccak256Hash(append(poolId.Bytes(), common.LeftPadBytes([]byte{6}, 32)...))
+	if got := PoolStateSlot(poolId); got != want {
+		t.Errorf("PoolStateSlot = %s, want %s", got.Hex(), want.Hex())
+	}
+}
+
+func Test_DecodeSlot0(t *testing.T) {
+	sqrtPrice, _ := new(big.Int).SetString("79228162514264337593543950336", 10) // 2^96, tick 0
+
+	tests := []struct {
+		name string
+		tick int32
+	}{
+		{"zero tick", 0},
+		{"positive tick", 887272},
+		{"negative tick", -887272},
+		{"minus one", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := DecodeSlot0(packSlot0(sqrtPrice, tt.tick, 0x123456, 3000))
+			if state.SqrtPriceX96.Cmp(sqrtPrice) != 0 {
+				t.Errorf("sqrtPriceX96 = %s, want %s", state.SqrtPriceX96, sqrtPrice)
+			}
+			if state.Tick != tt.tick {
+				t.Errorf("tick = %d, want %d", state.Tick, tt.tick)
+			}
+			if state.ProtocolFee != 0x123456 || state.LPFee != 3000 {
+				t.Errorf("fees = (%d, %d), want (%d, 3000)", state.ProtocolFee, state.LPFee, 0x123456)
+			}
+		})
+	}
+}
+
+func Test_PoolStateReaderRead(t *testing.T) {
+	poolId := common.Hash{0x01}
+	sqrtPrice := new(big.Int).Lsh(big.NewInt(1), 96)
+	liquidity := big.NewInt(1_000_000_000)
+
+	slots := func(slot0 [32]byte) func(ethereum.CallMsg, *big.Int) ([]byte, error) {
+		return func(msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+			if *msg.To != testPoolManager {
+				t.Errorf("called %s, want PoolManager", msg.To.Hex())
+			}
+			if block == nil || block.Int64() != 42 {
+				t.Errorf("read at block %v, want pinned block 42", block)
+			}
+			args, err := extsloadABI.Methods["extsload"].Inputs.Unpack(msg.Data[4:])
+			if err != nil {
+				t.Fatalf("failed to unpack extsload input: %v", err)
+			}
+			if common.Hash(args[0].([32]byte)) != PoolStateSlot(poolId) || args[1].(*big.Int).Int64() != 4 {
+				t.Errorf("extsload(%x, %s), want state slot and 4 slots", args[0], args[1])
+			}
+
+			var liq [32]byte
+			liquidity.FillBytes(liq[:])
+			return extsloadABI.Methods["extsload"].Outputs.Pack([][32]byte{slot0, {}, {}, liq})
+		}
+	}
+
+	t.Run("initialized", func(t *testing.T) {
+		chain := newFakeChain(42)
+		chain.callFn = slots(packSlot0(sqrtPrice, -120, 0, 500))
+
+		state, err := NewPoolStateReader(chain, testPoolManager).Read(context.Background(), poolId, big.NewInt(42))
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		if state.Tick != -120 || state.LPFee != 500 || state.Liquidity.Cmp(liquidity) != 0 {
+			t.Errorf("unexpected state %+v", state)
+		}
+	})
+
+	t.Run("uninitialized", func(t *testing.T) {
+		chain := newFakeChain(42)
+		chain.callFn = slots([32]byte{})
+
+		_, err := NewPoolStateReader(chain, testPoolManager).Read(context.Background(), poolId, big.NewInt(42))
+		if !errors.Is(err, ErrPoolNotInitialized) {
+			t.Fatalf("expected ErrPoolNotInitialized, got %v", err)
+		}
+	})
+}
+
+func Test_PositionCenter(t *testing.T) {
+	position := func(lower, upper, liquidity int64) lstrebalancehook.LSTrebalanceHookLpPosition {
+		return lstrebalancehook.LSTrebalanceHookLpPosition{
+			TickLower: big.NewInt(lower),
+			TickUpper: big.NewInt(upper),
+			Liquidity: big.NewInt(liquidity),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		positions []lstrebalancehook.LSTrebalanceHookLpPosition
+		want      int32
+		wantOk    bool
+	}{
+		{"none", nil, 0, false},
+		{"only empty", []lstrebalancehook.LSTrebalanceHookLpPosition{position(-60, 60, 0)}, 0, false},
+		{"single", []lstrebalancehook.LSTrebalanceHookLpPosition{position(0, 120, 5)}, 60, true},
+		{"weighted", []lstrebalancehook.LSTrebalanceHookLpPosition{position(-120, 0, 3), position(0, 120, 1)}, -30, true},
+		{"ignores empty", []lstrebalancehook.LSTrebalanceHookLpPosition{position(-600, -480, 0), position(60, 180, 1)}, 120, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := (&PoolSnapshot{Positions: tt.positions}).PositionCenter()
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("PositionCenter = (%d, %v), want (%d, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_RelativeTickShift(t *testing.T) {
+	tests := []struct {
+		name              string
+		yieldShift, drift int32
+		want              int32
+	}{
+		{"price at center", 49, 0, 49},
+		{"yield partly priced in", 49, 20, 49},
+		{"yield fully priced in", 49, 49, 49},
+		{"price overshot", 49, 80, 80},
+		{"price moved against yield", 49, -30, 19},
+		{"currency1 LST partly priced in", -49, -20, -49},
+		{"currency1 LST overshot", -49, -80, -80},
+		{"currency1 LST moved against yield", -49, 30, -19},
+		{"no yield follows price", 0, 25, 25},
+	}
+
+	for _, tt := range tests {
+		if got := RelativeTickShift(tt.yieldShift, tt.drift); got != tt.want {
+			t.Errorf("%s: RelativeTickShift(%d, %d) = %d, want %d", tt.name, tt.yieldShift, tt.drift, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// defaultConfirmations is the default performer.receipt_confirmations.
+	defaultConfirmations uint64 = 1
+	// defaultReceiptTimeout bounds the wait for a receipt
+	// (performer.receipt_timeout).
+	defaultReceiptTimeout = 2 * time.Minute
+
+	confirmationPollInterval = time.Second
+)
+
+var (
+	// ErrRebalanceEventMissing is returned when a successful receipt carries
+	// no RebalanceExecuted log from the hook.
+	ErrRebalanceEventMissing = errors.New("receipt has no RebalanceExecuted event")
+	// ErrMalformedRebalanceEvent is returned when the hook's RebalanceExecuted
+	// log carries values the performer cannot represent.
+	ErrMalformedRebalanceEvent = errors.New("malformed RebalanceExecuted event")
+)
+
+// TxRevertedError is returned when the rebalance transaction was mined with a failed status.
+// Reason holds the decoded revert, when replaying the transaction produced one.
+type TxRevertedError struct {
+	TxHash      common.Hash
+	BlockNumber uint64
+	GasUsed     uint64
+	Reason      error
+}
+
+func (e *TxRevertedError) Error() string {
+	msg := fmt.Sprintf("transaction %s reverted in block %d (gas used %d)", e.TxHash.Hex(), e.BlockNumber, e.GasUsed)
+	if e.Reason != nil {
+		msg += ": " + e.Reason.Error()
+	}
+	return msg
+}
+
+func (e *TxRevertedError) Unwrap() error {
+	return e.Reason
+}
+
+// ReceiptTimeoutError is returned when the transaction is not mined and
+// confirmed before the deadline.
+type ReceiptTimeoutError struct {
+	TxHash common.Hash
+	// Replacements lists fee-bumped replacements of TxHash, oldest first.
+	Replacements  []common.Hash
+	Timeout       time.Duration
+	Confirmations uint64
+	Err           error
+}
+
+func (e *ReceiptTimeoutError) Error() string {
+	msg := fmt.Sprintf("transaction %s", e.TxHash.Hex())
+	if len(e.Replacements) > 0 {
+		msg += fmt.Sprintf(" (and %d replacements)", len(e.Replacements))
+	}
+	return fmt.Sprintf("%s not confirmed (%d confirmations) within %s: %v",
+		msg, e.Confirmations, e.Timeout, e.Err)
+}
+
+func (e *ReceiptTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// NoPositionsRebalancedError is returned when the hook rebalanced none of the
+// positions the task reported.
+type NoPositionsRebalancedError struct {
+	TxHash   common.Hash
+	Expected uint64
+}
+
+func (e *NoPositionsRebalancedError) Error() string {
+	return fmt.Sprintf("transaction %s rebalanced 0 of %d positions", e.TxHash.Hex(), e.Expected)
+}
+
+// RebalanceOutcome is the decoded result of a mined executeRebalance transaction.
+type RebalanceOutcome struct {
+	TxHash      common.Hash
+	BlockNumber uint64
+	BlockHash   common.Hash
+	// LogIndex is the index of the RebalanceExecuted log in the block.
+	LogIndex            uint
+	PositionsRebalanced uint64
+	TickShift           int32
+}
+
+// receiptBackend is the subset of the L2 client needed to track a transaction.
+type receiptBackend interface {
+	bind.DeployBackend
+	bind.ContractCaller
+	BlockNumber(ctx context.Context) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// waitForRebalance blocks until one of p's transactions is mined with the
+// configured number of confirmations, then decodes the hook's
+// RebalanceExecuted log.
+func (tw *TaskWorker) waitForRebalance(ctx context.Context, backend receiptBackend, p *pendingTx, expectedPositions uint64) (*RebalanceOutcome, error) {
+	ctx, cancel := context.WithTimeout(ctx, tw.receiptTimeout)
+	defer cancel()
+
+	receipt, err := tw.waitForConfirmations(ctx, backend, p)
+	if err != nil {
+		return nil, &ReceiptTimeoutError{
+			TxHash:        p.original().Hash(),
+			Replacements:  p.hashes()[1:],
+			Timeout:       tw.receiptTimeout,
+			Confirmations: tw.confirmations,
+			Err:           err,
+		}
+	}
+
+	tx, _ := p.find(receipt.TxHash)
+	if tx != p.original() {
+		tw.logger.Sugar().Infow("Replacement transaction mined",
+			"taskId", p.taskId,
+			"originalTxHash", p.original().Hash().Hex(),
+			"minedTxHash", receipt.TxHash.Hex(),
+		)
+	}
+
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, &TxRevertedError{
+			TxHash:      receipt.TxHash,
+			BlockNumber: receipt.BlockNumber.Uint64(),
+			GasUsed:     receipt.GasUsed,
+			Reason:      replayRevert(ctx, backend, tx, receipt.BlockNumber),
+		}
+	}
+
+	outcome, err := tw.decodeRebalanceExecuted(receipt, p.poolId)
+	if err != nil {
+		return nil, err
+	}
+
+	if outcome.PositionsRebalanced == 0 && expectedPositions > 0 {
+		return outcome, &NoPositionsRebalancedError{TxHash: outcome.TxHash, Expected: expectedPositions}
+	}
+
+	return outcome, nil
+}
+
+// waitForConfirmations waits for a receipt of any transaction in p and then
+// for the chain head to move confirmations-1 blocks past it. The receipt is
+// re-read afterwards so a reorg that moved the transaction is observed.
+func (tw *TaskWorker) waitForConfirmations(ctx context.Context, backend receiptBackend, p *pendingTx) (*types.Receipt, error) {
+	for {
+		receipt, err := tw.waitMined(ctx, backend, p)
+		if err != nil {
+			return nil, err
+		}
+		tw.transition(p.taskId, StateMined, nil)
+
+		target := receipt.BlockNumber.Uint64()
+		if tw.confirmations > 1 {
+			target += tw.confirmations - 1
+		}
+
+		if err := waitForBlock(ctx, backend, target); err != nil {
+			return nil, err
+		}
+
+		confirmed, err := backend.TransactionReceipt(ctx, receipt.TxHash)
+		if err != nil {
+			tw.logger.Sugar().Warnw("Receipt disappeared while confirming, waiting again",
+				"txHash", receipt.TxHash.Hex(),
+				"error", err,
+			)
+			continue
+		}
+		if confirmed.BlockHash != receipt.BlockHash {
+			tw.logger.Sugar().Warnw("Transaction moved to a different block while confirming",
+				"txHash", receipt.TxHash.Hex(),
+				"previousBlock", receipt.BlockHash.Hex(),
+				"currentBlock", confirmed.BlockHash.Hex(),
+			)
+			continue
+		}
+		return confirmed, nil
+	}
+}
+
+func waitForBlock(ctx context.Context, backend receiptBackend, target uint64) error {
+	ticker := time.NewTicker(confirmationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		head, err := backend.BlockNumber(ctx)
+		if err == nil && head >= target {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// replayRevert re-executes a reverted transaction as an eth_call against the
+// parent block's state, to recover the revert data that receipts omit.
+func replayRevert(ctx context.Context, backend receiptBackend, tx *types.Transaction, block *big.Int) error {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil
+	}
+
+	_, err = backend.CallContract(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}, new(big.Int).Sub(block, big.NewInt(1)))
+
+	var callErr *HookCallError
+	if errors.As(decodeCallError(err), &callErr) {
+		return callErr.Revert
+	}
+	return nil
+}
+
+// decodeRebalanceExecuted returns the hook's RebalanceExecuted event for
+// poolId. Events for other pools in the same receipt are ignored.
+func (tw *TaskWorker) decodeRebalanceExecuted(receipt *types.Receipt, poolId common.Hash) (*RebalanceOutcome, error) {
+	for _, l := range receipt.Logs {
+		if l.Address != tw.hookAddress || len(l.Topics) < 2 || l.Topics[1] != poolId {
+			continue
+		}
+		ev, err := tw.hook.ParseRebalanceExecuted(*l)
+		if err != nil {
+			continue
+		}
+		if !ev.PositionsRebalanced.IsUint64() {
+			return nil, fmt.Errorf("%w: positionsRebalanced %s out of range (tx %s)", ErrMalformedRebalanceEvent, ev.PositionsRebalanced, receipt.TxHash.Hex())
+		}
+		return &RebalanceOutcome{
+			TxHash:              receipt.TxHash,
+			BlockNumber:         receipt.BlockNumber.Uint64(),
+			BlockHash:           receipt.BlockHash,
+			LogIndex:            l.Index,
+			PositionsRebalanced: ev.PositionsRebalanced.Uint64(),
+			TickShift:           int32(ev.TickShift.Int64()),
+		}, nil
+	}
+	return nil, fmt.Errorf("%w (tx %s)", ErrRebalanceEventMissing, receipt.TxHash.Hex())
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+func newTestHookWorker(t *testing.T) *TaskWorker {
+	t.Helper()
+
+	hook, err := lstrebalancehook.NewLSTrebalanceHook(testHookAddress, nil)
+	if err != nil {
+		t.Fatalf("failed to bind hook: %v", err)
+	}
+	return &TaskWorker{
+		logger:         zap.NewNop(),
+		hookAddress:    testHookAddress,
+		hook:           hook,
+		confirmations:  1,
+		receiptTimeout: 5 * time.Second,
+		now:            time.Now,
+	}
+}
+
+func rebalanceExecutedLog(t *testing.T, address common.Address, poolId common.Hash, positions int64, tickShift int64) *types.Log {
+	t.Helper()
+
+	hookABI, err := abi.JSON(strings.NewReader(lstrebalancehook.LSTrebalanceHookMetaData.ABI))
+	if err != nil {
+		t.Fatalf("failed to parse hook ABI: %v", err)
+	}
+	ev := hookABI.Events["RebalanceExecuted"]
+	data, err := ev.Inputs.NonIndexed().Pack(big.NewInt(positions), big.NewInt(tickShift))
+	if err != nil {
+		t.Fatalf("failed to pack event: %v", err)
+	}
+	return &types.Log{
+		Address: address,
+		Topics:  []common.Hash{ev.ID, poolId},
+		Data:    data,
+	}
+}
+
+func testTx(nonce uint64) *types.Transaction {
+	return types.NewTx(&types.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(1), Gas: 21000})
+}
+
+func Test_WaitForRebalance(t *testing.T) {
+	poolId := common.Hash{0x01}
+
+	tests := []struct {
+		name          string
+		status        uint64
+		logs          func(t *testing.T) []*types.Log
+		expected      uint64
+		wantPositions uint64
+		wantErr       func(error) bool
+	}{
+		{
+			name:   "success",
+			status: types.ReceiptStatusSuccessful,
+			logs: func(t *testing.T) []*types.Log {
+				return []*types.Log{rebalanceExecutedLog(t, testHookAddress, poolId, 3, -120)}
+			},
+			expected:      3,
+			wantPositions: 3,
+		},
+		{
+			name:   "partial",
+			status: types.ReceiptStatusSuccessful,
+			logs: func(t *testing.T) []*types.Log {
+				return []*types.Log{rebalanceExecutedLog(t, testHookAddress, poolId, 1, 60)}
+			},
+			expected:      4,
+			wantPositions: 1,
+		},
+		{
+			name:     "reverted",
+			status:   types.ReceiptStatusFailed,
+			logs:     func(t *testing.T) []*types.Log { return nil },
+			expected: 1,
+			wantErr: func(err error) bool {
+				var e *TxRevertedError
+				return errors.As(err, &e)
+			},
+		},
+		{
+			name:   "zero of N",
+			status: types.ReceiptStatusSuccessful,
+			logs: func(t *testing.T) []*types.Log {
+				return []*types.Log{rebalanceExecutedLog(t, testHookAddress, poolId, 0, 60)}
+			},
+			expected: 2,
+			wantErr: func(err error) bool {
+				var e *NoPositionsRebalancedError
+				return errors.As(err, &e) && e.Expected == 2
+			},
+		},
+		{
+			name:   "event from other contract",
+			status: types.ReceiptStatusSuccessful,
+			logs: func(t *testing.T) []*types.Log {
+				return []*types.Log{rebalanceExecutedLog(t, common.Address{0x99}, poolId, 3, 60)}
+			},
+			expected: 3,
+			wantErr:  func(err error) bool { return errors.Is(err, ErrRebalanceEventMissing) },
+		},
+		{
+			name:   "event for other pool",
+			status: types.ReceiptStatusSuccessful,
+			logs: func(t *testing.T) []*types.Log {
+				return []*types.Log{
+					rebalanceExecutedLog(t, testHookAddress, common.Hash{0x02}, 5, 60),
+					rebalanceExecutedLog(t, testHookAddress, poolId, 2, 60),
+				}
+			},
+			expected:      2,
+			wantPositions: 2,
+		},
+		{
+			name:   "positions out of range",
+			status: types.ReceiptStatusSuccessful,
+			logs: func(t *testing.T) []*types.Log {
+				l := rebalanceExecutedLog(t, testHookAddress, poolId, 0, 60)
+				// positionsRebalanced is the first data word; set it to 2^64.
+				l.Data[23] = 0x01
+				return []*types.Log{l}
+			},
+			expected: 3,
+			wantErr:  func(err error) bool { return errors.Is(err, ErrMalformedRebalanceEvent) },
+		},
+		{
+			name:   "only other pool",
+			status: types.ReceiptStatusSuccessful,
+			logs: func(t *testing.T) []*types.Log {
+				return []*types.Log{rebalanceExecutedLog(t, testHookAddress, common.Hash{0x02}, 3, 60)}
+			},
+			expected: 3,
+			wantErr:  func(err error) bool { return errors.Is(err, ErrRebalanceEventMissing) },
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tw := newTestHookWorker(t)
+			chain := newFakeChain(10)
+			tx := testTx(uint64(i))
+			chain.mine(&types.Receipt{
+				Status:      tt.status,
+				TxHash:      tx.Hash(),
+				BlockNumber: big.NewInt(10),
+				BlockHash:   common.Hash{0x0a},
+				Logs:        tt.logs(t),
+			})
+
+			outcome, err := tw.waitForRebalance(context.Background(), chain, &pendingTx{poolId: poolId, sent: []*types.Transaction{tx}}, tt.expected)
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("waitForRebalance failed: %v", err)
+			}
+			if outcome.PositionsRebalanced != tt.wantPositions {
+				t.Errorf("positionsRebalanced = %d, want %d", outcome.PositionsRebalanced, tt.wantPositions)
+			}
+		})
+	}
+}
+
+func Test_WaitForRebalanceRevertReason(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	chainID := big.NewInt(31338)
+	tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		To:        &testHookAddress,
+		Gas:       500000,
+		GasFeeCap: big.NewInt(1),
+	}), types.LatestSignerForChainID(chainID), key)
+	if err != nil {
+		t.Fatalf("failed to sign tx: %v", err)
+	}
+
+	tw := newTestHookWorker(t)
+	chain := newFakeChain(10)
+	chain.callErr = &fakeDataError{data: hexutil.Encode(crypto.Keccak256([]byte("onlyAvsOperator()"))[:4])}
+	chain.mine(&types.Receipt{
+		Status:      types.ReceiptStatusFailed,
+		TxHash:      tx.Hash(),
+		BlockNumber: big.NewInt(10),
+		BlockHash:   common.Hash{0x0a},
+	})
+
+	_, err = tw.waitForRebalance(context.Background(), chain, &pendingTx{sent: []*types.Transaction{tx}}, 1)
+
+	var reverted *TxRevertedError
+	if !errors.As(err, &reverted) {
+		t.Fatalf("expected *TxRevertedError, got %v", err)
+	}
+	if !errors.Is(err, ErrOnlyAvsOperator) {
+		t.Errorf("expected ErrOnlyAvsOperator, got %v", err)
+	}
+}
+
+func Test_WaitForRebalanceConfirmations(t *testing.T) {
+	tw := newTestHookWorker(t)
+	tw.confirmations = 3
+
+	chain := newFakeChain(10)
+	tx := testTx(0)
+	chain.mine(&types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		TxHash:      tx.Hash(),
+		BlockNumber: big.NewInt(10),
+		BlockHash:   common.Hash{0x0a},
+		Logs:        []*types.Log{rebalanceExecutedLog(t, testHookAddress, common.Hash{0x01}, 1, 60)},
+	})
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		chain.mu.Lock()
+		chain.head = 12
+		chain.mu.Unlock()
+	}()
+
+	outcome, err := tw.waitForRebalance(context.Background(), chain, &pendingTx{poolId: common.Hash{0x01}, sent: []*types.Transaction{tx}}, 1)
+	if err != nil {
+		t.Fatalf("waitForRebalance failed: %v", err)
+	}
+	if outcome.BlockNumber != 10 {
+		t.Errorf("blockNumber = %d, want 10", outcome.BlockNumber)
+	}
+}
+
+func Test_WaitForRebalanceTimeout(t *testing.T) {
+	tw := newTestHookWorker(t)
+	tw.receiptTimeout = 50 * time.Millisecond
+
+	_, err := tw.waitForRebalance(context.Background(), newFakeChain(10), &pendingTx{sent: []*types.Transaction{testTx(0)}}, 1)
+
+	var timeoutErr *ReceiptTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected *ReceiptTimeoutError, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
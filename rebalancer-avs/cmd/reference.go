@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// headerBackend is the subset of a client needed to map timestamps to blocks.
+type headerBackend interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// TaskNotOnChainError is returned when no RebalanceRequested event on the hook
+// matches the task payload, so the hook never requested the task.
+type TaskNotOnChainError struct {
+	PoolId    common.Hash
+	Timestamp uint64
+}
+
+func (e *TaskNotOnChainError) Error() string {
+	return fmt.Sprintf("unauthorized task: no RebalanceRequested for pool %s at timestamp %d", e.PoolId.Hex(), e.Timestamp)
+}
+
+func (e *TaskNotOnChainError) Is(target error) bool { return target == ErrUnauthorizedTask }
+
+// TaskReference is the hook's RebalanceRequested event a task was built from.
+// Every chain read behind a result is pinned to its block, so operators whose
+// heads differ compute the same result.
+type TaskReference struct {
+	BlockNumber uint64
+	BlockHash   common.Hash
+	Time        uint64
+	TxHash      common.Hash
+	LogIndex    uint
+}
+
+// Block returns the reference block number for CallOpts and FilterOpts.
+func (r *TaskReference) Block() *big.Int {
+	return new(big.Int).SetUint64(r.BlockNumber)
+}
+
+// locateTask finds the RebalanceRequested event the payload mirrors. The hook
+// stamps the event with block.timestamp, so only the blocks with the task's
+// timestamp are searched.
+func (tw *TaskWorker) locateTask(ctx context.Context, headers headerBackend, pool *Pool, data *RebalanceTaskData) (*TaskReference, error) {
+	head, err := headers.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L2 head: %w", err)
+	}
+	if head.Time < data.Timestamp {
+		return nil, fmt.Errorf("L2 head %s at %d is behind task timestamp %d", head.Number, head.Time, data.Timestamp)
+	}
+
+	from, err := blockAtTime(ctx, headers, head.Number.Uint64(), data.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	to, err := searchBlock(ctx, headers, head.Number.Uint64(), func(h *types.Header) bool { return h.Time > data.Timestamp })
+	if err != nil {
+		return nil, err
+	}
+	if to == from {
+		return nil, &TaskNotOnChainError{PoolId: pool.Id, Timestamp: data.Timestamp}
+	}
+	end := to - 1
+
+	it, err := tw.hook.FilterRebalanceRequested(&bind.FilterOpts{Start: from, End: &end, Context: ctx}, [][32]byte{pool.Id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter RebalanceRequested for pool %s: %w", pool.Id.Hex(), err)
+	}
+	defer it.Close()
+	for it.Next() {
+		if !matchesRequest(it.Event, data) {
+			continue
+		}
+		ref := &TaskReference{
+			BlockNumber: it.Event.Raw.BlockNumber,
+			BlockHash:   it.Event.Raw.BlockHash,
+			Time:        data.Timestamp,
+			TxHash:      it.Event.Raw.TxHash,
+			LogIndex:    it.Event.Raw.Index,
+		}
+		tw.logger.Sugar().Infow("📍 Located task on chain",
+			"poolId", pool.Id.Hex(),
+			"blockNumber", ref.BlockNumber,
+			"blockHash", ref.BlockHash.Hex(),
+			"txHash", ref.TxHash.Hex(),
+			"logIndex", ref.LogIndex,
+		)
+		return ref, nil
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("failed to read RebalanceRequested for pool %s: %w", pool.Id.Hex(), err)
+	}
+	return nil, &TaskNotOnChainError{PoolId: pool.Id, Timestamp: data.Timestamp}
+}
+
+func matchesRequest(ev *lstrebalancehook.LSTrebalanceHookRebalanceRequested, data *RebalanceTaskData) bool {
+	equal := func(a *big.Int, b uint64) bool { return a.IsUint64() && a.Uint64() == b }
+	yieldAmount, lstBalance := data.YieldAmount, data.LSTBalance
+	if yieldAmount == nil {
+		yieldAmount = new(big.Int)
+	}
+	if lstBalance == nil {
+		lstBalance = new(big.Int)
+	}
+	return ev.YieldAmount.Cmp(yieldAmount) == 0 &&
+		ev.CurrentStETHBalance.Cmp(lstBalance) == 0 &&
+		equal(ev.YieldBps, data.YieldBps) &&
+		equal(ev.CumulativeYieldBps, data.CumulativeYield) &&
+		equal(ev.PositionsToRebalance, data.PositionCount) &&
+		equal(ev.Timestamp, data.Timestamp)
+}
+
+// blockAtTime returns the first block up to head whose timestamp is at least
+// ts, or head+1 if there is none.
+func blockAtTime(ctx context.Context, headers headerBackend, head, ts uint64) (uint64, error) {
+	return searchBlock(ctx, headers, head, func(h *types.Header) bool { return h.Time >= ts })
+}
+
+// searchBlock returns the first block in [0, head] whose header satisfies
+// pred, or head+1 if none does. pred must be monotonic in the block number.
+func searchBlock(ctx context.Context, headers headerBackend, head uint64, pred func(*types.Header) bool) (uint64, error) {
+	lo, hi := uint64(0), head+1
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		h, err := headers.HeaderByNumber(ctx, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, fmt.Errorf("failed to get header %d: %w", mid, err)
+		}
+		if pred(h) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, nil
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/taskresult"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+const testGenesisTime = 1_700_000_000
+
+var testHookABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(lstrebalancehook.LSTrebalanceHookMetaData.ABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+func l2BlockTime(n uint64) uint64 { return testGenesisTime + 2*n }
+
+func ether(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), oneEther) }
+
+// hookHistory is the L2 history shared by the reference tests. The hook's
+// first yield check records a balance at block 20, a check at block 30 sees
+// no growth, and the task is requested at block 50. From block 81 on the pool
+// trades elsewhere and its position has moved, so anything read at the head
+// instead of the reference block changes the result.
+type hookHistory struct {
+	pool *Pool
+	data *RebalanceTaskData
+	// revert, if set, is the revert data executeRebalance fails with.
+	revert []byte
+}
+
+const (
+	historyBaselineBlock = 20
+	historyIdleBlock     = 30
+	historyRequestBlock  = 50
+	historyMovedBlock    = 81
+)
+
+var historyServiceManager = common.HexToAddress("0x5e")
+
+func newHookHistory(t *testing.T) *hookHistory {
+	t.Helper()
+
+	pools := testPoolRegistry(t)
+	pool, _ := pools.Lookup(testPoolId(t, pools))
+	pool.YieldSource = &YieldSourceConfig{Type: YieldSourceWstETH, Address: testLST.Hex(), Chain: "l2"}
+	return &hookHistory{
+		pool: pool,
+		data: &RebalanceTaskData{
+			Version:         PayloadVersionV1,
+			PoolId:          pool.Id,
+			YieldAmount:     ether(5),
+			YieldBps:        50,
+			CumulativeYield: 50,
+			PositionCount:   1,
+			LSTBalance:      ether(1005),
+			Timestamp:       l2BlockTime(historyRequestBlock),
+		},
+	}
+}
+
+// yieldInfo mirrors getYieldInfo after block n.
+func (h *hookHistory) yieldInfo(n uint64) (balance *big.Int, lastCheck uint64) {
+	switch {
+	case n >= historyRequestBlock:
+		return ether(1005), l2BlockTime(historyRequestBlock)
+	case n >= historyIdleBlock:
+		return ether(1000), l2BlockTime(historyIdleBlock)
+	case n >= historyBaselineBlock:
+		return ether(1000), l2BlockTime(historyBaselineBlock)
+	default:
+		return new(big.Int), l2BlockTime(10)
+	}
+}
+
+func (h *hookHistory) rate(n uint64) *big.Int {
+	if n >= 40 {
+		return big.NewInt(1_005_000)
+	}
+	return big.NewInt(1_000_000)
+}
+
+func (h *hookHistory) positions(n uint64) []lstrebalancehook.LSTrebalanceHookLpPosition {
+	lower, upper := int64(-120), int64(120)
+	if n >= historyMovedBlock {
+		lower, upper = 600, 840
+	}
+	return []lstrebalancehook.LSTrebalanceHookLpPosition{{
+		Owner:     common.Address{0x0b},
+		TickLower: big.NewInt(lower),
+		TickUpper: big.NewInt(upper),
+		Liquidity: ether(1),
+	}}
+}
+
+func (h *hookHistory) tick(n uint64) int32 {
+	if n >= historyMovedBlock {
+		return 300
+	}
+	return 0
+}
+
+func (h *hookHistory) requestLog(t *testing.T, poolId common.Hash, block uint64, yieldAmount *big.Int) types.Log {
+	t.Helper()
+
+	ev := testHookABI.Events["RebalanceRequested"]
+	data, err := ev.Inputs.NonIndexed().Pack(
+		yieldAmount,
+		new(big.Int).SetUint64(h.data.YieldBps),
+		new(big.Int).SetUint64(h.data.CumulativeYield),
+		new(big.Int).SetUint64(h.data.PositionCount),
+		h.data.LSTBalance,
+		new(big.Int).SetUint64(l2BlockTime(block)),
+	)
+	if err != nil {
+		t.Fatalf("failed to pack RebalanceRequested: %v", err)
+	}
+	return types.Log{
+		Address:     testHookAddress,
+		Topics:      []common.Hash{ev.ID, poolId},
+		Data:        data,
+		BlockNumber: block,
+		BlockHash:   common.BigToHash(new(big.Int).SetUint64(block)),
+		TxHash:      common.Hash{0xaa, byte(block)},
+	}
+}
+
+// chain returns the history as seen by a node whose head is head.
+func (h *hookHistory) chain(t *testing.T, head uint64) *fakeChain {
+	t.Helper()
+
+	chain := newFakeChain(head)
+	chain.blockTime = l2BlockTime
+	chain.logs = []types.Log{
+		// Same block, other pool; same pool, other block.
+		h.requestLog(t, common.Hash{0x02}, historyRequestBlock, h.data.YieldAmount),
+		h.requestLog(t, h.pool.Id, historyRequestBlock-1, h.data.YieldAmount),
+		h.requestLog(t, h.pool.Id, historyRequestBlock, h.data.YieldAmount),
+	}
+	chain.callFn = func(msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+		n := head
+		if block != nil {
+			n = block.Uint64()
+		}
+		switch *msg.To {
+		case testHookAddress:
+			method, err := testHookABI.MethodById(msg.Data[:4])
+			if err != nil {
+				t.Fatalf("unknown hook selector %x", msg.Data[:4])
+			}
+			switch method.Name {
+			case "getYieldInfo":
+				balance, lastCheck := h.yieldInfo(n)
+				return method.Outputs.Pack(balance, new(big.Int).SetUint64(lastCheck), big.NewInt(50))
+			case "getPositions":
+				return method.Outputs.Pack(h.positions(n))
+			case "avsServiceManager":
+				return method.Outputs.Pack(historyServiceManager)
+			case "executeRebalance":
+				if msg.From != historyServiceManager {
+					return nil, &fakeDataError{data: hexutil.Encode(selector("onlyAvsOperator()"))}
+				}
+				if h.revert != nil {
+					return nil, &fakeDataError{data: hexutil.Encode(h.revert)}
+				}
+				return method.Outputs.Pack(big.NewInt(int64(len(h.positions(n)))))
+			}
+			t.Fatalf("unexpected hook call %s", method.Name)
+		case testPoolManager:
+			var liq [32]byte
+			ether(1).FillBytes(liq[:])
+			slot0 := packSlot0(new(big.Int).Lsh(big.NewInt(1), 96), h.tick(n), 0, 3000)
+			return extsloadABI.Methods["extsload"].Outputs.Pack([][32]byte{slot0, {}, {}, liq})
+		case testLST:
+			return yieldSourceABI.Methods["stEthPerToken"].Outputs.Pack(h.rate(n))
+		}
+		t.Fatalf("unexpected call to %s", msg.To.Hex())
+		return nil, nil
+	}
+	return chain
+}
+
+// worker returns a performer reading the history through chain.
+func (h *hookHistory) worker(t *testing.T, chain *fakeChain) *TaskWorker {
+	t.Helper()
+
+	hook, err := lstrebalancehook.NewLSTrebalanceHook(testHookAddress, chain)
+	if err != nil {
+		t.Fatalf("failed to bind hook: %v", err)
+	}
+	source, err := NewYieldSource(h.pool.YieldSource, nil, chain)
+	if err != nil {
+		t.Fatalf("NewYieldSource failed: %v", err)
+	}
+	pools := &PoolRegistry{pools: map[common.Hash]*Pool{h.pool.Id: h.pool}}
+	return &TaskWorker{
+		logger:            zap.NewNop(),
+		hookAddress:       testHookAddress,
+		hook:              hook,
+		pools:             pools,
+		tickRounding:      defaultTickRounding,
+		poolState:         NewPoolStateReader(chain, testPoolManager),
+		yieldSources:      map[common.Hash]YieldSource{h.pool.Id: source},
+		yieldToleranceBps: defaultYieldToleranceBps,
+	}
+}
+
+func Test_LocateTask(t *testing.T) {
+	h := newHookHistory(t)
+	chain := h.chain(t, 60)
+	tw := h.worker(t, chain)
+
+	ref, err := tw.locateTask(context.Background(), chain, h.pool, h.data)
+	if err != nil {
+		t.Fatalf("locateTask failed: %v", err)
+	}
+	if ref.BlockNumber != historyRequestBlock || ref.BlockHash != common.BigToHash(big.NewInt(historyRequestBlock)) {
+		t.Errorf("reference = %+v, want block %d", ref, historyRequestBlock)
+	}
+
+	forged := *h.data
+	forged.YieldBps = 80
+	if _, err := tw.locateTask(context.Background(), chain, h.pool, &forged); !errors.Is(err, ErrUnauthorizedTask) {
+		t.Errorf("forged payload: expected ErrUnauthorizedTask, got %v", err)
+	}
+
+	forged = *h.data
+	forged.Timestamp = l2BlockTime(historyRequestBlock) + 1
+	if _, err := tw.locateTask(context.Background(), chain, h.pool, &forged); !errors.Is(err, ErrUnauthorizedTask) {
+		t.Errorf("timestamp between blocks: expected ErrUnauthorizedTask, got %v", err)
+	}
+
+	lagging := h.chain(t, historyRequestBlock-1)
+	if _, err := tw.locateTask(context.Background(), lagging, h.pool, h.data); err == nil || errors.Is(err, ErrUnauthorizedTask) {
+		t.Errorf("lagging node: expected a retryable error, got %v", err)
+	}
+}
+
+// Operators whose heads differ must sign the same result for a task.
+func Test_ComputeResultDeterministic(t *testing.T) {
+	h := newHookHistory(t)
+	taskId := []byte("task-1")
+
+	var results [][]byte
+	for _, head := range []uint64{historyRequestBlock, 60, 120, 500} {
+		chain := h.chain(t, head)
+		computed, err := h.worker(t, chain).computeResult(context.Background(), nil, chain, taskId, h.data)
+		if err != nil {
+			t.Fatalf("head %d: computeResult failed: %v", head, err)
+		}
+		want := SimulationVerdict{BlockNumber: historyRequestBlock, From: historyServiceManager, PositionsRebalanced: 1}
+		if computed.Simulation == nil || *computed.Simulation != want {
+			t.Errorf("head %d: simulation = %+v, want %+v", head, computed.Simulation, want)
+		}
+		results = append(results, computed.Result)
+	}
+	for i := 1; i < len(results); i++ {
+		if !bytes.Equal(results[i], results[0]) {
+			t.Fatalf("result %d differs:\n%x\n%x", i, results[i], results[0])
+		}
+	}
+
+	result, err := taskresult.Decode(results[0])
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if result.ReferenceBlockNumber != historyRequestBlock || result.ReferenceBlockHash != common.BigToHash(big.NewInt(historyRequestBlock)) {
+		t.Errorf("reference block = %d %s, want %d", result.ReferenceBlockNumber, result.ReferenceBlockHash.Hex(), historyRequestBlock)
+	}
+	if result.TickShift != 60 || result.PositionsRebalanced != 1 {
+		t.Errorf("result = %+v, want a 60 tick shift of one position", result)
+	}
+	// The per-position plan travels in the result.
+	var moved int
+	for _, p := range result.Positions {
+		if p.Skip == "" {
+			moved++
+			if p.TargetTickLower != p.TickLower+60 || p.TargetTickUpper != p.TickUpper+60 {
+				t.Errorf("position %+v, want its range shifted by 60", p)
+			}
+		}
+	}
+	if len(result.Positions) == 0 || moved != 1 {
+		t.Errorf("positions = %+v, want one of them moved", result.Positions)
+	}
+}
+
+func Test_ComputeResultSimulationRejects(t *testing.T) {
+	h := newHookHistory(t)
+	h.revert = selector("onlyAvsOperator()")
+	chain := h.chain(t, 60)
+
+	computed, err := h.worker(t, chain).computeResult(context.Background(), nil, chain, []byte("task-1"), h.data)
+	var simErr *SimulationError
+	if !errors.As(err, &simErr) || !errors.Is(err, ErrOnlyAvsOperator) {
+		t.Fatalf("expected *SimulationError wrapping ErrOnlyAvsOperator, got %v", err)
+	}
+	if simErr.Block == nil || simErr.Block.Uint64() != historyRequestBlock {
+		t.Errorf("simulated at block %v, want %d", simErr.Block, historyRequestBlock)
+	}
+	if computed == nil || computed.Simulation == nil || computed.Simulation.Reason != err.Error() {
+		t.Fatalf("expected the verdict to carry the rejection, got %+v", computed)
+	}
+	if computed.Result != nil {
+		t.Errorf("a rejected plan must not be encoded, got %x", computed.Result)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// defaultStuckTxTimeout is how long a transaction may stay pending
+	// before it is replaced (performer.stuck_tx_timeout).
+	defaultStuckTxTimeout = 45 * time.Second
+	// defaultFeeBumpPercent is the default performer.fee_bump_percent.
+	defaultFeeBumpPercent uint64 = 20
+	// defaultMaxFeeBumps is the default performer.max_fee_bumps.
+	defaultMaxFeeBumps uint64 = 3
+
+	// minFeeBumpPercent is the smallest bump geth accepts for a replacement.
+	minFeeBumpPercent = 10
+)
+
+// ErrFeeBumpCapped is returned when a replacement would need fees above the
+// gas policy's caps.
+var ErrFeeBumpCapped = errors.New("fee bump exceeds gas policy caps")
+
+// pendingTx is a rebalance transaction and every replacement signed for its
+// nonce, oldest first. Any of them may be the one that gets mined.
+type pendingTx struct {
+	taskId string
+	poolId common.Hash
+	from   common.Address
+	// signer re-signs replacements; nil disables replacement.
+	signer bind.SignerFn
+	// budget is the pool's MaxTxCost, nil if unlimited.
+	budget *big.Int
+	sent   []*types.Transaction
+}
+
+func (p *pendingTx) original() *types.Transaction {
+	return p.sent[0]
+}
+
+func (p *pendingTx) latest() *types.Transaction {
+	return p.sent[len(p.sent)-1]
+}
+
+// find returns the sent transaction with hash h.
+func (p *pendingTx) find(h common.Hash) (*types.Transaction, bool) {
+	for _, tx := range p.sent {
+		if tx.Hash() == h {
+			return tx, true
+		}
+	}
+	return nil, false
+}
+
+func (p *pendingTx) hashes() []common.Hash {
+	hashes := make([]common.Hash, len(p.sent))
+	for i, tx := range p.sent {
+		hashes[i] = tx.Hash()
+	}
+	return hashes
+}
+
+// waitMined polls for a receipt of any transaction in p. When the newest one
+// has been pending longer than the stuck timeout, it is replaced with bumped
+// fees, up to MaxFeeBumps times.
+func (tw *TaskWorker) waitMined(ctx context.Context, backend receiptBackend, p *pendingTx) (*types.Receipt, error) {
+	ticker := time.NewTicker(confirmationPollInterval)
+	defer ticker.Stop()
+
+	lastSent := tw.now()
+	for {
+		for _, tx := range p.sent {
+			if receipt, err := backend.TransactionReceipt(ctx, tx.Hash()); err == nil {
+				tw.nonces.Confirmed(tx.Nonce())
+				return receipt, nil
+			}
+		}
+
+		if p.signer != nil && uint64(len(p.sent)-1) < tw.gas.MaxFeeBumps && tw.now().Sub(lastSent) >= tw.gas.StuckTxTimeout {
+			if err := tw.replaceStuck(ctx, backend, p); err != nil {
+				tw.logger.Sugar().Warnw("Failed to replace stuck transaction",
+					"taskId", p.taskId,
+					"originalTxHash", p.original().Hash().Hex(),
+					"error", err,
+				)
+			}
+			lastSent = tw.now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// replaceStuck re-signs the newest transaction in p with the same nonce and
+// bumped fees and broadcasts it.
+func (tw *TaskWorker) replaceStuck(ctx context.Context, backend receiptBackend, p *pendingTx) error {
+	last := p.latest()
+
+	tip := bumpFee(last.GasTipCap(), tw.gas.FeeBumpPercent)
+	feeCap := bumpFee(last.GasFeeCap(), tw.gas.FeeBumpPercent)
+	if tw.gas.MaxFeePerGas != nil && feeCap.Cmp(tw.gas.MaxFeePerGas) > 0 {
+		feeCap = new(big.Int).Set(tw.gas.MaxFeePerGas)
+	}
+	if feeCap.Cmp(bumpFee(last.GasFeeCap(), minFeeBumpPercent)) < 0 {
+		return fmt.Errorf("%w: fee cap %s wei already at MaxFeePerGas", ErrFeeBumpCapped, last.GasFeeCap())
+	}
+	if tip.Cmp(feeCap) > 0 {
+		tip = new(big.Int).Set(feeCap)
+	}
+	// geth also requires the tip to grow by minFeeBumpPercent; make sure the
+	// clamp to feeCap did not undo that.
+	if tip.Cmp(bumpFee(last.GasTipCap(), minFeeBumpPercent)) < 0 {
+		return fmt.Errorf("%w: tip %s wei cannot be bumped under fee cap %s wei", ErrFeeBumpCapped, last.GasTipCap(), feeCap)
+	}
+
+	maxCost := new(big.Int).Mul(new(big.Int).SetUint64(last.Gas()), feeCap)
+	for _, budget := range []*big.Int{tw.gas.MaxTxCost, p.budget} {
+		if budget != nil && maxCost.Cmp(budget) > 0 {
+			return fmt.Errorf("%w: cost %s wei above budget %s wei", ErrFeeBumpCapped, maxCost, budget)
+		}
+	}
+
+	replacement, err := p.signer(p.from, types.NewTx(&types.DynamicFeeTx{
+		ChainID:   last.ChainId(),
+		Nonce:     last.Nonce(),
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       last.Gas(),
+		To:        last.To(),
+		Value:     last.Value(),
+		Data:      last.Data(),
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to sign replacement: %w", err)
+	}
+	if err := backend.SendTransaction(ctx, replacement); err != nil {
+		return fmt.Errorf("failed to send replacement: %w", err)
+	}
+
+	p.sent = append(p.sent, replacement)
+	tw.recordSubmitted(p.taskId, replacement)
+	tw.logger.Sugar().Warnw("⛽ Replaced stuck rebalance transaction",
+		"taskId", p.taskId,
+		"originalTxHash", p.original().Hash().Hex(),
+		"replacedTxHash", last.Hash().Hex(),
+		"replacementTxHash", replacement.Hash().Hex(),
+		"nonce", replacement.Nonce(),
+		"attempt", len(p.sent)-1,
+		"gasTipCap", tip,
+		"gasFeeCap", feeCap,
+	)
+	return nil
+}
+
+// bumpFee returns fee increased by percent, rounded up.
+func bumpFee(fee *big.Int, percent uint64) *big.Int {
+	bumped := new(big.Int).Mul(fee, new(big.Int).SetUint64(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+func testPendingTx(t *testing.T) *pendingTx {
+	t.Helper()
+	return testPendingTxWithFees(t, gwei(1), gwei(3))
+}
+
+func testPendingTxWithFees(t *testing.T, tip, feeCap *big.Int) *pendingTx {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(31337))
+	if err != nil {
+		t.Fatalf("failed to create transactor: %v", err)
+	}
+	tx, err := auth.Signer(auth.From, types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(31337),
+		Nonce:     4,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       120000,
+		To:        &testHookAddress,
+		Data:      []byte{0x01},
+	}))
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	return &pendingTx{taskId: "task-1", from: auth.From, signer: auth.Signer, sent: []*types.Transaction{tx}}
+}
+
+func Test_WaitMinedReplacesStuckTransaction(t *testing.T) {
+	chain := newFakeChain(10)
+	p := testPendingTx(t)
+
+	policy := DefaultGasPolicy()
+	policy.StuckTxTimeout = 0
+	policy.MaxFeeBumps = 2
+	tw := &TaskWorker{logger: zap.NewNop(), gas: policy, now: time.Now}
+
+	// Mine the second replacement once it is broadcast.
+	go func() {
+		for {
+			chain.mu.Lock()
+			n := len(chain.sent)
+			chain.mu.Unlock()
+			if n == 2 {
+				chain.mine(&types.Receipt{
+					TxHash:      chain.sent[1].Hash(),
+					Status:      types.ReceiptStatusSuccessful,
+					BlockNumber: big.NewInt(11),
+				})
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	receipt, err := tw.waitMined(ctx, chain, p)
+	if err != nil {
+		t.Fatalf("waitMined failed: %v", err)
+	}
+
+	if len(p.sent) != 3 {
+		t.Fatalf("sent %d transactions, want original and 2 replacements", len(p.sent))
+	}
+	if receipt.TxHash != p.sent[2].Hash() {
+		t.Fatalf("mined %s, want second replacement %s", receipt.TxHash.Hex(), p.sent[2].Hash().Hex())
+	}
+
+	wantTips := []*big.Int{gwei(1), big.NewInt(12e8), big.NewInt(144e7)}
+	wantCaps := []*big.Int{gwei(3), big.NewInt(36e8), big.NewInt(432e7)}
+	for i, tx := range p.sent {
+		if tx.Nonce() != 4 {
+			t.Errorf("tx %d nonce = %d, want 4", i, tx.Nonce())
+		}
+		if tx.GasTipCap().Cmp(wantTips[i]) != 0 || tx.GasFeeCap().Cmp(wantCaps[i]) != 0 {
+			t.Errorf("tx %d fees = (%s, %s), want (%s, %s)", i, tx.GasTipCap(), tx.GasFeeCap(), wantTips[i], wantCaps[i])
+		}
+		sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil || sender != p.from {
+			t.Errorf("tx %d signed by %s (%v), want %s", i, sender.Hex(), err, p.from.Hex())
+		}
+	}
+}
+
+func Test_ReplaceStuckRespectsCaps(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy func(*GasPolicy)
+		budget *big.Int
+		tip    *big.Int
+	}{
+		{"fee cap at max", func(p *GasPolicy) { p.MaxFeePerGas = big.NewInt(32e8) }, nil, nil},
+		{"transaction ceiling", func(p *GasPolicy) { p.MaxTxCost = big.NewInt(4e14) }, nil, nil},
+		{"pool budget", func(*GasPolicy) {}, big.NewInt(4e14), nil},
+		// The bumped tip is clamped to the 3.3 gwei fee cap, below 110% of 5 gwei.
+		{"tip clamped under the bump", func(p *GasPolicy) { p.MaxFeePerGas = big.NewInt(33e8) }, nil, gwei(5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := newFakeChain(10)
+			p := testPendingTx(t)
+			if tt.tip != nil {
+				p = testPendingTxWithFees(t, tt.tip, gwei(3))
+			}
+			p.budget = tt.budget
+
+			policy := DefaultGasPolicy()
+			tt.policy(&policy)
+			tw := &TaskWorker{logger: zap.NewNop(), gas: policy, now: time.Now}
+
+			err := tw.replaceStuck(context.Background(), chain, p)
+			if !errors.Is(err, ErrFeeBumpCapped) {
+				t.Fatalf("expected ErrFeeBumpCapped, got %v", err)
+			}
+			if len(chain.sent) != 0 || len(p.sent) != 1 {
+				t.Fatalf("replacement sent despite cap")
+			}
+		})
+	}
+}
+
+func Test_BumpFee(t *testing.T) {
+	tests := []struct {
+		fee     int64
+		percent uint64
+		want    int64
+	}{
+		{100, 10, 110},
+		{1000000000, 20, 1200000000},
+		{7, 10, 8},
+		{0, 10, 0},
+	}
+
+	for _, tt := range tests {
+		if got := bumpFee(big.NewInt(tt.fee), tt.percent); got.Int64() != tt.want {
+			t.Errorf("bumpFee(%d, %d) = %s, want %d", tt.fee, tt.percent, got, tt.want)
+		}
+	}
+}
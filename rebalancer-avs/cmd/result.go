@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/binary"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/taskresult"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TaskHash returns the TaskMailbox task hash a TaskRequest.TaskId refers to.
+// The executor sends the hash as a 0x-prefixed hex string; a raw 32-byte id is
+// used as is and any other id is hashed, so every id maps to one bytes32.
+func TaskHash(taskId []byte) common.Hash {
+	if decoded, err := hexutil.Decode(string(taskId)); err == nil && len(decoded) == common.HashLength {
+		return common.BytesToHash(decoded)
+	}
+	if len(taskId) == common.HashLength {
+		return common.BytesToHash(taskId)
+	}
+	return crypto.Keccak256Hash(taskId)
+}
+
+// OnchainTaskId derives the uint32 taskId passed to executeRebalance from the
+// task hash: its first four bytes, big-endian. Every operator derives the same
+// id for a task; distinct tasks collide with probability 2^-32.
+func OnchainTaskId(taskHash common.Hash) uint32 {
+	return binary.BigEndian.Uint32(taskHash[:4])
+}
+
+// encodeTaskResult builds the TaskResponse.Result for plan, the per-position
+// plan included, see package taskresult for the layout.
+func encodeTaskResult(taskId []byte, plan *RebalancePlan) ([]byte, error) {
+	r := &taskresult.Result{
+		PoolId:              plan.PoolId,
+		TaskId:              TaskHash(taskId),
+		TickShift:           plan.TickShift,
+		ReferenceBlockHash:  plan.BlockHash,
+		PositionsRebalanced: uint64(plan.Rebalanced),
+	}
+	if plan.BlockNumber != nil {
+		r.ReferenceBlockNumber = plan.BlockNumber.Uint64()
+	}
+	for _, p := range plan.Positions {
+		r.Positions = append(r.Positions, taskresult.Position{
+			Owner:           p.Owner,
+			Liquidity:       p.Liquidity,
+			TickLower:       p.TickLower,
+			TickUpper:       p.TickUpper,
+			TargetTickLower: p.TargetTickLower,
+			TargetTickUpper: p.TargetTickUpper,
+			Skip:            string(p.Skip),
+		})
+	}
+	return taskresult.Encode(r)
+}
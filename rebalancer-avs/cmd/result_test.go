@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func Test_TaskHash(t *testing.T) {
+	hash := common.HexToHash("0x8f3a6c1b5e2d4f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8")
+
+	tests := []struct {
+		name   string
+		taskId []byte
+		want   common.Hash
+	}{
+		{"hex string", []byte(hash.Hex()), hash},
+		{"raw bytes", hash.Bytes(), hash},
+		{"opaque id", []byte("test-task-id"), crypto.Keccak256Hash([]byte("test-task-id"))},
+		{"short hex", []byte("0x1234"), crypto.Keccak256Hash([]byte("0x1234"))},
+	}
+
+	for _, tt := range tests {
+		if got := TaskHash(tt.taskId); got != tt.want {
+			t.Errorf("%s: TaskHash = %s, want %s", tt.name, got.Hex(), tt.want.Hex())
+		}
+	}
+}
+
+func Test_OnchainTaskId(t *testing.T) {
+	taskHash := common.HexToHash("0x8f3a6c1b5e2d4f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8")
+	if got := OnchainTaskId(taskHash); got != 0x8f3a6c1b {
+		t.Errorf("OnchainTaskId = %#x, want 0x8f3a6c1b", got)
+	}
+	if OnchainTaskId(TaskHash([]byte(taskHash.Hex()))) != OnchainTaskId(taskHash) {
+		t.Error("hex and raw task ids map to different on-chain ids")
+	}
+}
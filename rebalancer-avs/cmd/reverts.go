@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Hook custom errors, see LSTrebalanceHook.
+var (
+	ErrOnlyAvsOperator        = errors.New("hook reverted: onlyAvsOperator (sender is not the hook's avsServiceManager)")
+	ErrInvalidTickShift       = errors.New("hook reverted: invalidTickShift")
+	ErrInsufficientYield      = errors.New("hook reverted: insufficientYield")
+	ErrNoPositionsToRebalance = errors.New("hook reverted: noPositionsToRebalance")
+)
+
+var (
+	errorStringSelector = [4]byte{0x08, 0xc3, 0x79, 0xa0} // Error(string)
+	panicSelector       = [4]byte{0x4e, 0x48, 0x7b, 0x71} // Panic(uint256)
+
+	hookErrors = map[string]error{
+		"onlyAvsOperator":        ErrOnlyAvsOperator,
+		"invalidTickShift":       ErrInvalidTickShift,
+		"insufficientYield":      ErrInsufficientYield,
+		"noPositionsToRebalance": ErrNoPositionsToRebalance,
+	}
+
+	hookErrorSelectors = func() map[[4]byte]error {
+		parsed, err := abi.JSON(strings.NewReader(lstrebalancehook.LSTrebalanceHookMetaData.ABI))
+		if err != nil {
+			panic(fmt.Errorf("failed to parse hook ABI: %w", err))
+		}
+		selectors := make(map[[4]byte]error, len(hookErrors))
+		for name, sentinel := range hookErrors {
+			e, ok := parsed.Errors[name]
+			if !ok {
+				panic(fmt.Errorf("hook ABI has no error %s", name))
+			}
+			selectors[[4]byte(e.ID[:4])] = sentinel
+		}
+		return selectors
+	}()
+)
+
+// RevertReasonError is a revert with a standard Error(string) reason.
+type RevertReasonError struct {
+	Reason string
+}
+
+func (e *RevertReasonError) Error() string {
+	return fmt.Sprintf("execution reverted: %s", e.Reason)
+}
+
+// PanicError is a revert with a standard Panic(uint256) code.
+type PanicError struct {
+	Code *big.Int
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("execution panicked: code 0x%x (%s)", e.Code, panicReason(e.Code))
+}
+
+// UnknownRevertError is a revert whose data does not match any known selector.
+type UnknownRevertError struct {
+	Data []byte
+}
+
+func (e *UnknownRevertError) Error() string {
+	if len(e.Data) == 0 {
+		return "execution reverted without data"
+	}
+	return fmt.Sprintf("execution reverted with unknown data %s", hexutil.Encode(e.Data))
+}
+
+func panicReason(code *big.Int) string {
+	if !code.IsUint64() {
+		return "unknown"
+	}
+	switch code.Uint64() {
+	case 0x01:
+		return "assertion failed"
+	case 0x11:
+		return "arithmetic overflow or underflow"
+	case 0x12:
+		return "division by zero"
+	case 0x21:
+		return "invalid enum value"
+	case 0x22:
+		return "invalid storage byte array"
+	case 0x31:
+		return "pop on empty array"
+	case 0x32:
+		return "array index out of bounds"
+	case 0x41:
+		return "out of memory"
+	case 0x51:
+		return "call to zero-initialized function"
+	default:
+		return "unknown"
+	}
+}
+
+// DecodeRevert turns raw revert data into a Go error. Hook custom errors map to
+// the exported sentinels and can be matched with errors.Is.
+func DecodeRevert(data []byte) error {
+	if len(data) < 4 {
+		return &UnknownRevertError{Data: data}
+	}
+
+	selector := [4]byte(data[:4])
+	if sentinel, ok := hookErrorSelectors[selector]; ok {
+		return sentinel
+	}
+
+	switch selector {
+	case errorStringSelector:
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			return &UnknownRevertError{Data: data}
+		}
+		return &RevertReasonError{Reason: reason}
+	case panicSelector:
+		if len(data) != 4+32 {
+			return &UnknownRevertError{Data: data}
+		}
+		return &PanicError{Code: new(big.Int).SetBytes(data[4:])}
+	}
+
+	return &UnknownRevertError{Data: data}
+}
+
+// revertData extracts revert data from a JSON-RPC error, if present.
+func revertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+
+	switch data := dataErr.ErrorData().(type) {
+	case string:
+		b, decodeErr := hexutil.Decode(data)
+		if decodeErr != nil {
+			return nil, false
+		}
+		return b, true
+	case []byte:
+		return data, true
+	default:
+		return nil, false
+	}
+}
+
+// HookCallError wraps an RPC error whose revert data was decoded. errors.Is
+// matches both the decoded revert and the original RPC error.
+type HookCallError struct {
+	Revert error
+	Err    error
+}
+
+func (e *HookCallError) Error() string {
+	return e.Revert.Error()
+}
+
+func (e *HookCallError) Unwrap() []error {
+	return []error{e.Revert, e.Err}
+}
+
+// decodeCallError replaces opaque RPC revert errors with decoded ones. Errors
+// without revert data are returned unchanged.
+func decodeCallError(err error) error {
+	if err == nil {
+		return nil
+	}
+	data, ok := revertData(err)
+	if !ok {
+		return err
+	}
+	return &HookCallError{Revert: DecodeRevert(data), Err: err}
+}
+
+// IsRetryable reports whether sending the same rebalance again could succeed.
+// Hook custom errors are deterministic: retrying burns gas for the same revert.
+func IsRetryable(err error) bool {
+	for _, sentinel := range hookErrors {
+		if errors.Is(err, sentinel) {
+			return false
+		}
+	}
+	var reason *RevertReasonError
+	var panicErr *PanicError
+	return !errors.As(err, &reason) && !errors.As(err, &panicErr)
+}
+
+// reportMisconfiguredServiceManager logs the onlyAvsOperator revert with the
+// details needed to fix it.
+func (tw *TaskWorker) reportMisconfiguredServiceManager(operator common.Address, err error) {
+	tw.logger.Sugar().Errorw("🚨 Hook rejected the operator: avsServiceManager is misconfigured. "+
+		"Call setAvsServiceManager(operator) on the hook; the task will not be retried.",
+		"hookAddress", tw.hookAddress.Hex(),
+		"operator", operator.Hex(),
+		"error", err,
+	)
+}
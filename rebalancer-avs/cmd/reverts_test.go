@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// fakeDataError mimics the JSON-RPC error returned for reverted calls.
+type fakeDataError struct {
+	data interface{}
+}
+
+func (e *fakeDataError) Error() string          { return "execution reverted" }
+func (e *fakeDataError) ErrorData() interface{} { return e.data }
+
+func selector(sig string) []byte {
+	return crypto.Keccak256([]byte(sig))[:4]
+}
+
+func Test_DecodeRevert(t *testing.T) {
+	stringType, _ := abi.NewType("string", "", nil)
+	reason, err := abi.Arguments{{Type: stringType}}.Pack("zero address")
+	if err != nil {
+		t.Fatalf("failed to pack reason: %v", err)
+	}
+	panicCode, err := abi.Arguments{{Type: uint256Type}}.Pack(big.NewInt(0x11))
+	if err != nil {
+		t.Fatalf("failed to pack panic code: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		data  []byte
+		check func(error) bool
+	}{
+		{"onlyAvsOperator", selector("onlyAvsOperator()"), func(err error) bool { return errors.Is(err, ErrOnlyAvsOperator) }},
+		{"invalidTickShift", selector("invalidTickShift()"), func(err error) bool { return errors.Is(err, ErrInvalidTickShift) }},
+		{"insufficientYield", selector("insufficientYield()"), func(err error) bool { return errors.Is(err, ErrInsufficientYield) }},
+		{"noPositionsToRebalance", selector("noPositionsToRebalance()"), func(err error) bool { return errors.Is(err, ErrNoPositionsToRebalance) }},
+		{"Error(string)", append(selector("Error(string)"), reason...), func(err error) bool {
+			var e *RevertReasonError
+			return errors.As(err, &e) && e.Reason == "zero address"
+		}},
+		{"Panic(uint256)", append(selector("Panic(uint256)"), panicCode...), func(err error) bool {
+			var e *PanicError
+			return errors.As(err, &e) && e.Code.Int64() == 0x11
+		}},
+		{"truncated Error(string)", selector("Error(string)"), func(err error) bool {
+			var e *UnknownRevertError
+			return errors.As(err, &e)
+		}},
+		{"unknown selector", selector("somethingElse()"), func(err error) bool {
+			var e *UnknownRevertError
+			return errors.As(err, &e)
+		}},
+		{"empty", nil, func(err error) bool {
+			var e *UnknownRevertError
+			return errors.As(err, &e)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := DecodeRevert(tt.data); !tt.check(err) {
+				t.Errorf("unexpected decoded error: %v", err)
+			}
+		})
+	}
+}
+
+func Test_DecodeCallError(t *testing.T) {
+	rpcErr := &fakeDataError{data: hexutil.Encode(selector("invalidTickShift()"))}
+	err := decodeCallError(fmt.Errorf("failed to estimate gas: %w", rpcErr))
+
+	if !errors.Is(err, ErrInvalidTickShift) {
+		t.Errorf("expected ErrInvalidTickShift, got %v", err)
+	}
+	if !errors.Is(err, rpcErr) {
+		t.Errorf("expected original RPC error to be preserved, got %v", err)
+	}
+
+	plain := errors.New("connection refused")
+	if got := decodeCallError(plain); got != plain {
+		t.Errorf("expected error without revert data to pass through, got %v", got)
+	}
+}
+
+func Test_IsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("wrapped: %w", ErrOnlyAvsOperator), false},
+		{&HookCallError{Revert: ErrInvalidTickShift, Err: errors.New("rpc")}, false},
+		{&RevertReasonError{Reason: "zero address"}, false},
+		{&PanicError{Code: big.NewInt(1)}, false},
+		{errors.New("connection refused"), true},
+		{&UnknownRevertError{}, true},
+	}
+
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// v4 reads a hook's callbacks from the low 14 bits of its address (Hooks.sol).
+const (
+	hookBeforeInitialize uint16 = 1 << (13 - iota)
+	hookAfterInitialize
+	hookBeforeAddLiquidity
+	hookAfterAddLiquidity
+	hookBeforeRemoveLiquidity
+	hookAfterRemoveLiquidity
+	hookBeforeSwap
+	hookAfterSwap
+	hookBeforeDonate
+	hookAfterDonate
+	hookBeforeSwapReturnDelta
+	hookAfterSwapReturnDelta
+	hookAfterAddLiquidityReturnDelta
+	hookAfterRemoveLiquidityReturnDelta
+
+	hookPermissionMask uint16 = 1<<14 - 1
+
+	// expectedHookPermissions are the callbacks LSTrebalanceHook.getHookPermissions enables.
+	expectedHookPermissions = hookAfterInitialize | hookAfterAddLiquidity | hookAfterRemoveLiquidity | hookAfterSwap
+)
+
+var hookPermissionNames = []struct {
+	flag uint16
+	name string
+}{
+	{hookBeforeInitialize, "beforeInitialize"},
+	{hookAfterInitialize, "afterInitialize"},
+	{hookBeforeAddLiquidity, "beforeAddLiquidity"},
+	{hookAfterAddLiquidity, "afterAddLiquidity"},
+	{hookBeforeRemoveLiquidity, "beforeRemoveLiquidity"},
+	{hookAfterRemoveLiquidity, "afterRemoveLiquidity"},
+	{hookBeforeSwap, "beforeSwap"},
+	{hookAfterSwap, "afterSwap"},
+	{hookBeforeDonate, "beforeDonate"},
+	{hookAfterDonate, "afterDonate"},
+	{hookBeforeSwapReturnDelta, "beforeSwapReturnDelta"},
+	{hookAfterSwapReturnDelta, "afterSwapReturnDelta"},
+	{hookAfterAddLiquidityReturnDelta, "afterAddLiquidityReturnDelta"},
+	{hookAfterRemoveLiquidityReturnDelta, "afterRemoveLiquidityReturnDelta"},
+}
+
+// HookPermissions returns the permission bits encoded in a hook address.
+func HookPermissions(hook common.Address) uint16 {
+	return (uint16(hook[18])<<8 | uint16(hook[19])) & hookPermissionMask
+}
+
+// permissionNames lists the callbacks set in flags.
+func permissionNames(flags uint16) []string {
+	var names []string
+	for _, p := range hookPermissionNames {
+		if flags&p.flag != 0 {
+			names = append(names, p.name)
+		}
+	}
+	return names
+}
+
+// CheckStatus is the outcome of a self-check.
+type CheckStatus string
+
+const (
+	CheckPassed  CheckStatus = "pass"
+	CheckFailed  CheckStatus = "FAIL"
+	CheckSkipped CheckStatus = "skip"
+	// CheckWarned is a check that found something to look at that does not
+	// stop the performer from serving tasks.
+	CheckWarned CheckStatus = "warn"
+)
+
+// CheckResult is one self-check. Fix says what to change when it failed or
+// warned.
+type CheckResult struct {
+	Name   string
+	Status CheckStatus
+	Detail string
+	Fix    string
+}
+
+// SelfCheckReport is the outcome of every startup self-check.
+type SelfCheckReport struct {
+	Results []CheckResult
+}
+
+func (r *SelfCheckReport) add(name string, status CheckStatus, detail, fix string) {
+	r.Results = append(r.Results, CheckResult{Name: name, Status: status, Detail: detail, Fix: fix})
+}
+
+// Failed reports whether any check failed.
+func (r *SelfCheckReport) Failed() bool {
+	for _, c := range r.Results {
+		if c.Status == CheckFailed {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *SelfCheckReport) String() string {
+	var b strings.Builder
+	b.WriteString("Startup self-checks:\n")
+	for _, c := range r.Results {
+		fmt.Fprintf(&b, "  [%s] %s: %s\n", c.Status, c.Name, c.Detail)
+		if (c.Status == CheckFailed || c.Status == CheckWarned) && c.Fix != "" {
+			fmt.Fprintf(&b, "         fix: %s\n", c.Fix)
+		}
+	}
+	return b.String()
+}
+
+// selfCheckTimeout bounds the RPC calls made by the startup self-checks.
+const selfCheckTimeout = 15 * time.Second
+
+// selfCheckBackend is what the self-checks read from the L2.
+type selfCheckBackend interface {
+	bind.ContractCaller
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// RunSelfChecks checks the L2 the performer is connected to against cfg: the
+// chain ID, the hook's code and permission bits, and whether the hook's
+// avsServiceManager is operator. An operator the hook does not accept still
+// computes and signs results, so that check only warns. Checks whose inputs
+// are not configured are skipped; operator is the zero address without a key.
+func RunSelfChecks(ctx context.Context, backend selfCheckBackend, cfg *PerformerConfig, hook, operator common.Address) *SelfCheckReport {
+	report := &SelfCheckReport{}
+
+	if hook == (common.Address{}) {
+		report.add("hook permissions", CheckSkipped, "no hook address configured", "")
+	} else if got := HookPermissions(hook); got != expectedHookPermissions {
+		report.add("hook permissions", CheckFailed,
+			fmt.Sprintf("%s encodes %#04x (%s), want %#04x (%s)",
+				hook.Hex(), got, strings.Join(permissionNames(got), ", "),
+				expectedHookPermissions, strings.Join(permissionNames(expectedHookPermissions), ", ")),
+			"set HOOK_ADDRESS to the LSTrebalanceHook deployment, or redeploy it with HookMiner to an address with these flags")
+	} else {
+		report.add("hook permissions", CheckPassed, strings.Join(permissionNames(got), ", "), "")
+	}
+
+	if backend == nil {
+		for _, name := range []string{"l2 chain id", "hook code", "avsServiceManager"} {
+			report.add(name, CheckSkipped, "no L2 RPC configured", "")
+		}
+		return report
+	}
+
+	want := cfg.Context.Chains.L2.ChainId
+	if chainID, err := backend.ChainID(ctx); err != nil {
+		report.add("l2 chain id", CheckFailed, fmt.Sprintf("failed to read chain ID: %v", err),
+			"check that L2_RPC_URL points at a reachable L2 node")
+	} else if want == 0 {
+		report.add("l2 chain id", CheckSkipped, fmt.Sprintf("node reports %s, chains.l2.chain_id not configured", chainID), "")
+	} else if !chainID.IsUint64() || chainID.Uint64() != want {
+		report.add("l2 chain id", CheckFailed, fmt.Sprintf("node reports %s, context %q expects %d", chainID, cfg.Context.Name, want),
+			"point L2_RPC_URL at the right chain, or fix chains.l2.chain_id (L2_CHAIN_ID)")
+	} else {
+		report.add("l2 chain id", CheckPassed, chainID.String(), "")
+	}
+
+	if hook == (common.Address{}) {
+		report.add("hook code", CheckSkipped, "no hook address configured", "")
+		report.add("avsServiceManager", CheckSkipped, "no hook address configured", "")
+		return report
+	}
+
+	code, err := backend.CodeAt(ctx, hook, nil)
+	switch {
+	case err != nil:
+		report.add("hook code", CheckFailed, fmt.Sprintf("failed to read code at %s: %v", hook.Hex(), err),
+			"check that L2_RPC_URL points at a reachable L2 node")
+	case len(code) == 0:
+		report.add("hook code", CheckFailed, fmt.Sprintf("no contract at %s", hook.Hex()),
+			"deploy the hook on this chain or fix HOOK_ADDRESS")
+	default:
+		report.add("hook code", CheckPassed, fmt.Sprintf("%d bytes at %s", len(code), hook.Hex()), "")
+	}
+	if err != nil || len(code) == 0 {
+		report.add("avsServiceManager", CheckSkipped, "no hook to read from", "")
+		return report
+	}
+
+	if operator == (common.Address{}) {
+		report.add("avsServiceManager", CheckSkipped, "no operator key configured", "")
+		return report
+	}
+	caller, err := lstrebalancehook.NewLSTrebalanceHookCaller(hook, backend)
+	if err != nil {
+		report.add("avsServiceManager", CheckFailed, fmt.Sprintf("failed to bind hook: %v", err), "")
+		return report
+	}
+	manager, err := caller.AvsServiceManager(&bind.CallOpts{Context: ctx})
+	switch {
+	case err != nil:
+		report.add("avsServiceManager", CheckFailed, fmt.Sprintf("avsServiceManager() failed: %v", err),
+			"check that HOOK_ADDRESS is an LSTrebalanceHook")
+	case manager != operator:
+		report.add("avsServiceManager", CheckWarned,
+			fmt.Sprintf("hook accepts %s, operator signs as %s; this operator computes results only and never executes", manager.Hex(), operator.Hex()),
+			fmt.Sprintf("to execute from this operator, call setAvsServiceManager(%s) on the hook", operator.Hex()))
+	default:
+		report.add("avsServiceManager", CheckPassed, manager.Hex(), "")
+	}
+	return report
+}
+
+// selfCheck runs the startup self-checks with tw's L2 client, hook and key.
+func (tw *TaskWorker) selfCheck(ctx context.Context, cfg *PerformerConfig) *SelfCheckReport {
+	ctx, cancel := context.WithTimeout(ctx, selfCheckTimeout)
+	defer cancel()
+
+	var backend selfCheckBackend
+	if tw.l2Client != nil {
+		backend = tw.l2Client
+	}
+	var operator common.Address
+	if tw.signer != nil {
+		operator = tw.signer.Address()
+	}
+	return RunSelfChecks(ctx, backend, cfg, tw.hookAddress, operator)
+}
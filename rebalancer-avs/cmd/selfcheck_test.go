@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// selfCheckHook carries exactly the LSTrebalanceHook permission bits.
+var selfCheckHook = common.HexToAddress("0x4444444444444444444444444444444444441540")
+
+func Test_HookPermissions(t *testing.T) {
+	if got := HookPermissions(selfCheckHook); got != expectedHookPermissions {
+		t.Errorf("HookPermissions = %#04x, want %#04x", got, expectedHookPermissions)
+	}
+	if got := strings.Join(permissionNames(expectedHookPermissions), ","); got != "afterInitialize,afterAddLiquidity,afterRemoveLiquidity,afterSwap" {
+		t.Errorf("permissionNames = %s", got)
+	}
+	// Bits above the low 14 are not permissions.
+	if got := HookPermissions(common.HexToAddress("0x000000000000000000000000000000000000d540")); got != expectedHookPermissions {
+		t.Errorf("HookPermissions ignores high bits: got %#04x", got)
+	}
+}
+
+func Test_RunSelfChecks(t *testing.T) {
+	operator := common.HexToAddress("0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65")
+
+	hookABI, err := abi.JSON(strings.NewReader(lstrebalancehook.LSTrebalanceHookMetaData.ABI))
+	if err != nil {
+		t.Fatalf("failed to parse hook ABI: %v", err)
+	}
+	serviceManager := func(manager common.Address) func(ethereum.CallMsg, *big.Int) ([]byte, error) {
+		return func(ethereum.CallMsg, *big.Int) ([]byte, error) {
+			return hookABI.Methods["avsServiceManager"].Outputs.Pack(manager)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		chain   func() *fakeChain
+		hook    common.Address
+		chainId uint64
+		failed  []string
+		warned  []string
+		fix     string
+	}{
+		{
+			name:    "all good",
+			chain:   func() *fakeChain { c := newFakeChain(1); c.callFn = serviceManager(operator); return c },
+			hook:    selfCheckHook,
+			chainId: 31338,
+		},
+		{
+			name:    "wrong chain",
+			chain:   func() *fakeChain { c := newFakeChain(1); c.callFn = serviceManager(operator); return c },
+			hook:    selfCheckHook,
+			chainId: 1,
+			failed:  []string{"l2 chain id"},
+			fix:     "L2_RPC_URL",
+		},
+		{
+			name:    "no code",
+			chain:   func() *fakeChain { c := newFakeChain(1); c.noCode = true; return c },
+			hook:    selfCheckHook,
+			chainId: 31338,
+			failed:  []string{"hook code"},
+			fix:     "deploy the hook",
+		},
+		{
+			name:    "wrong permission bits",
+			chain:   func() *fakeChain { c := newFakeChain(1); c.callFn = serviceManager(operator); return c },
+			hook:    testHookAddress,
+			chainId: 31338,
+			failed:  []string{"hook permissions"},
+			fix:     "HookMiner",
+		},
+		{
+			name:    "other service manager",
+			chain:   func() *fakeChain { c := newFakeChain(1); c.callFn = serviceManager(common.Address{0x01}); return c },
+			hook:    selfCheckHook,
+			chainId: 31338,
+			warned:  []string{"avsServiceManager"},
+			fix:     "setAvsServiceManager(" + operator.Hex() + ")",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &PerformerConfig{}
+			cfg.Context.Name = "devnet"
+			cfg.Context.Chains.L2.ChainId = tt.chainId
+
+			report := RunSelfChecks(context.Background(), tt.chain(), cfg, tt.hook, operator)
+			if report.Failed() != (len(tt.failed) > 0) {
+				t.Fatalf("Failed = %v, report:\n%s", report.Failed(), report)
+			}
+
+			var failed, warned []string
+			for _, c := range report.Results {
+				switch c.Status {
+				case CheckFailed:
+					failed = append(failed, c.Name)
+				case CheckWarned:
+					warned = append(warned, c.Name)
+				}
+			}
+			if strings.Join(failed, ",") != strings.Join(tt.failed, ",") {
+				t.Errorf("failed checks = %v, want %v\n%s", failed, tt.failed, report)
+			}
+			if strings.Join(warned, ",") != strings.Join(tt.warned, ",") {
+				t.Errorf("warned checks = %v, want %v\n%s", warned, tt.warned, report)
+			}
+			if tt.fix != "" && !strings.Contains(report.String(), tt.fix) {
+				t.Errorf("report does not suggest %q:\n%s", tt.fix, report)
+			}
+		})
+	}
+}
+
+func Test_RunSelfChecksSkipsUnconfigured(t *testing.T) {
+	report := RunSelfChecks(context.Background(), nil, &PerformerConfig{}, common.Address{}, common.Address{})
+	if report.Failed() {
+		t.Fatalf("unconfigured checks failed:\n%s", report)
+	}
+	for _, c := range report.Results {
+		if c.Status != CheckSkipped {
+			t.Errorf("%s = %s, want skip", c.Name, c.Status)
+		}
+	}
+}
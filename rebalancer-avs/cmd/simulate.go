@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrSimulationNoop is returned when the simulation would rebalance no
+// positions although the task reported some.
+var ErrSimulationNoop = errors.New("simulation rebalanced 0 positions")
+
+// SimulationError reports a pre-flight executeRebalance simulation that
+// rejected the task. The transaction is not sent.
+type SimulationError struct {
+	PoolId            common.Hash
+	TickShift         int32
+	ExpectedPositions uint64
+	// Block is the block the simulation ran at, nil for the latest.
+	Block *big.Int
+	Err   error
+}
+
+func (e *SimulationError) Error() string {
+	at := "latest"
+	if e.Block != nil {
+		at = e.Block.String()
+	}
+	return fmt.Sprintf("pre-flight simulation of executeRebalance(pool %s, tickShift %d) at block %s failed, transaction not sent: %v",
+		e.PoolId.Hex(), e.TickShift, at, e.Err)
+}
+
+func (e *SimulationError) Unwrap() error {
+	return e.Err
+}
+
+// SimulationVerdict is the outcome of simulating a task's result at its
+// reference block. It is kept in the task record.
+type SimulationVerdict struct {
+	BlockNumber         uint64         `json:"blockNumber"`
+	From                common.Address `json:"from"`
+	PositionsRebalanced uint64         `json:"positionsRebalanced"`
+	// Reason is why the simulation rejected the result, empty if it passed.
+	Reason string `json:"reason,omitempty"`
+}
+
+// simulateRebalance runs executeRebalance as an eth_call from from at block,
+// nil for the latest, and returns the positionsRebalanced it would report.
+func (tw *TaskWorker) simulateRebalance(ctx context.Context, from common.Address, block *big.Int, pool *Pool, tickShift int32, taskId uint32, expectedPositions uint64) (uint64, error) {
+	raw := &lstrebalancehook.LSTrebalanceHookRaw{Contract: tw.hook}
+
+	var out []interface{}
+	err := raw.Call(&bind.CallOpts{From: from, BlockNumber: block, Context: ctx}, &out,
+		"executeRebalance", pool.Key, big.NewInt(int64(tickShift)), taskId)
+	if err != nil {
+		return 0, &SimulationError{
+			PoolId:            pool.Id,
+			TickShift:         tickShift,
+			ExpectedPositions: expectedPositions,
+			Block:             block,
+			Err:               decodeCallError(err),
+		}
+	}
+
+	positions := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	if !positions.IsUint64() {
+		return 0, &SimulationError{
+			PoolId:    pool.Id,
+			TickShift: tickShift,
+			Block:     block,
+			Err:       fmt.Errorf("positionsRebalanced %s overflows uint64", positions),
+		}
+	}
+
+	simulated := positions.Uint64()
+	if simulated == 0 && expectedPositions > 0 {
+		return 0, &SimulationError{
+			PoolId:            pool.Id,
+			TickShift:         tickShift,
+			ExpectedPositions: expectedPositions,
+			Block:             block,
+			Err:               fmt.Errorf("%w, task reported %d", ErrSimulationNoop, expectedPositions),
+		}
+	}
+
+	return simulated, nil
+}
+
+// simulateResult simulates plan at the task's reference block from the
+// hook's avsServiceManager at that block, the only caller executeRebalance
+// accepts. Every operator gets the same verdict, which is returned even when
+// the simulation rejects the plan.
+func (tw *TaskWorker) simulateResult(ctx context.Context, pool *Pool, plan *RebalancePlan, ref *TaskReference, taskId []byte) (*SimulationVerdict, error) {
+	block := ref.Block()
+	manager, err := tw.hook.AvsServiceManager(&bind.CallOpts{BlockNumber: block, Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avsServiceManager at block %d: %w", ref.BlockNumber, err)
+	}
+
+	verdict := &SimulationVerdict{BlockNumber: ref.BlockNumber, From: manager}
+	onchainTaskId := OnchainTaskId(TaskHash(taskId))
+	simulated, err := tw.simulateRebalance(ctx, manager, block, pool, plan.TickShift, onchainTaskId, uint64(plan.Rebalanced))
+	if err != nil {
+		verdict.Reason = err.Error()
+		return verdict, err
+	}
+	verdict.PositionsRebalanced = simulated
+
+	tw.logger.Sugar().Infow("🧪 Simulated rebalance at reference block",
+		"poolId", pool.Id.Hex(),
+		"blockNumber", ref.BlockNumber,
+		"from", manager.Hex(),
+		"positionsRebalanced", simulated,
+		"positionsPlanned", plan.Rebalanced,
+	)
+	if simulated != uint64(plan.Rebalanced) {
+		tw.logger.Sugar().Warnw("⚠️  Simulation disagrees with rebalance plan",
+			"poolId", pool.Id.Hex(),
+			"simulated", simulated,
+			"planned", plan.Rebalanced,
+		)
+	}
+	return verdict, nil
+}
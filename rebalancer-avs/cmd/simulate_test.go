@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.uber.org/zap"
+)
+
+func Test_SimulateRebalance(t *testing.T) {
+	operator := common.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+	pools := testPoolRegistry(t)
+	pool, _ := pools.Lookup(testPoolId(t, pools))
+
+	returns := func(n int64) func(ethereum.CallMsg, *big.Int) ([]byte, error) {
+		return func(msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+			if msg.From != operator {
+				t.Errorf("simulation sent from %s, want %s", msg.From.Hex(), operator.Hex())
+			}
+			if block != nil {
+				t.Errorf("simulation pinned to block %s, want latest", block)
+			}
+			if taskId := new(big.Int).SetBytes(msg.Data[len(msg.Data)-32:]); taskId.Int64() != 7 {
+				t.Errorf("simulated with taskId %s, want 7", taskId)
+			}
+			return abi.Arguments{{Type: uint256Type}}.Pack(big.NewInt(n))
+		}
+	}
+
+	tests := []struct {
+		name     string
+		callFn   func(ethereum.CallMsg, *big.Int) ([]byte, error)
+		expected uint64
+		want     uint64
+		wantErr  error
+	}{
+		{"rebalances all", returns(3), 3, 3, nil},
+		{"rebalances some", returns(1), 3, 1, nil},
+		{"empty pool", returns(0), 0, 0, nil},
+		{"noop", returns(0), 2, 0, ErrSimulationNoop},
+		{"reverts", func(ethereum.CallMsg, *big.Int) ([]byte, error) {
+			return nil, &fakeDataError{data: hexutil.Encode(selector("onlyAvsOperator()"))}
+		}, 2, 0, ErrOnlyAvsOperator},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := newFakeChain(10)
+			chain.callFn = tt.callFn
+
+			hook, err := lstrebalancehook.NewLSTrebalanceHook(testHookAddress, chain)
+			if err != nil {
+				t.Fatalf("failed to bind hook: %v", err)
+			}
+			tw := &TaskWorker{logger: zap.NewNop(), hookAddress: testHookAddress, hook: hook}
+
+			got, err := tw.simulateRebalance(context.Background(), operator, nil, pool, 60, 7, tt.expected)
+			if tt.wantErr != nil {
+				var simErr *SimulationError
+				if !errors.As(err, &simErr) || !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected *SimulationError wrapping %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("simulateRebalance failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("positionsRebalanced = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
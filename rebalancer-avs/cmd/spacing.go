@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tick bounds enforced by the hook's _boundTick, equal to v4's TickMath.
+const (
+	MinTick int32 = -887272
+	MaxTick int32 = 887272
+)
+
+// TickRounding selects how a tick shift is aligned to the pool's tick spacing.
+type TickRounding string
+
+const (
+	// RoundNearest picks the closest multiple, ties away from zero.
+	RoundNearest TickRounding = "nearest"
+	// RoundTowardZero never shifts further than the yield implies.
+	RoundTowardZero TickRounding = "toward_zero"
+	// RoundAwayFromZero always shifts at least as far as the yield implies.
+	RoundAwayFromZero TickRounding = "away_from_zero"
+
+	// defaultTickRounding is the default performer.tick_rounding.
+	defaultTickRounding = RoundNearest
+)
+
+// ParseTickRounding validates a TICK_ROUNDING value.
+func ParseTickRounding(v string) (TickRounding, error) {
+	switch r := TickRounding(v); r {
+	case RoundNearest, RoundTowardZero, RoundAwayFromZero:
+		return r, nil
+	default:
+		return "", fmt.Errorf("unknown tick rounding %q, want %s, %s or %s",
+			v, RoundNearest, RoundTowardZero, RoundAwayFromZero)
+	}
+}
+
+// AlignTickShift rounds shift to a multiple of spacing. The hook adds the
+// shift to both ends of every position, and v4 rejects ticks that are not
+// multiples of the spacing, so an unaligned shift rebalances nothing.
+func AlignTickShift(shift, spacing int32, mode TickRounding) int32 {
+	if spacing <= 1 {
+		return shift
+	}
+
+	magnitude, sign := shift, int32(1)
+	if shift < 0 {
+		magnitude, sign = -shift, -1
+	}
+
+	down := magnitude / spacing * spacing
+	rem := magnitude - down
+
+	switch {
+	case rem == 0:
+		return shift
+	case mode == RoundTowardZero:
+		return sign * down
+	case mode == RoundAwayFromZero:
+		return sign * (down + spacing)
+	case 2*rem >= spacing:
+		return sign * (down + spacing)
+	default:
+		return sign * down
+	}
+}
+
+// PositionOutcome is the predicted effect of a shift on one hook position.
+type PositionOutcome string
+
+const (
+	// PositionShifted moves by the full shift.
+	PositionShifted PositionOutcome = "shifted"
+	// PositionClamped has a tick clamped by _boundTick; the clamped tick is
+	// generally not a multiple of the spacing, so v4 rejects the new range and
+	// the hook silently restores the position.
+	PositionClamped PositionOutcome = "clamped"
+	// PositionCollapsed becomes an empty range after clamping and is skipped.
+	PositionCollapsed PositionOutcome = "collapsed"
+	// PositionMisaligned would land on ticks that are not multiples of the
+	// spacing and is skipped.
+	PositionMisaligned PositionOutcome = "misaligned"
+	// PositionEmpty has no liquidity and is skipped.
+	PositionEmpty PositionOutcome = "empty"
+)
+
+// PositionForecast predicts how executeRebalance treats one position.
+type PositionForecast struct {
+	Index        int             `json:"index"`
+	Owner        common.Address  `json:"owner"`
+	TickLower    int32           `json:"tickLower"`
+	TickUpper    int32           `json:"tickUpper"`
+	NewTickLower int32           `json:"newTickLower"`
+	NewTickUpper int32           `json:"newTickUpper"`
+	Outcome      PositionOutcome `json:"outcome"`
+}
+
+// ForecastPositions mirrors executeRebalance's per-position logic, including
+// _boundTick, without touching the chain.
+func ForecastPositions(positions []lstrebalancehook.LSTrebalanceHookLpPosition, shift, spacing int32) []PositionForecast {
+	forecasts := make([]PositionForecast, len(positions))
+	for i, pos := range positions {
+		lower, upper := int32(pos.TickLower.Int64()), int32(pos.TickUpper.Int64())
+		f := PositionForecast{
+			Index:        i,
+			Owner:        pos.Owner,
+			TickLower:    lower,
+			TickUpper:    upper,
+			NewTickLower: boundTick(lower + shift),
+			NewTickUpper: boundTick(upper + shift),
+		}
+
+		switch {
+		case pos.Liquidity.Sign() == 0:
+			f.Outcome = PositionEmpty
+		case f.NewTickLower >= f.NewTickUpper:
+			f.Outcome = PositionCollapsed
+		case f.NewTickLower != lower+shift || f.NewTickUpper != upper+shift:
+			f.Outcome = PositionClamped
+		case f.NewTickLower%spacing != 0 || f.NewTickUpper%spacing != 0:
+			f.Outcome = PositionMisaligned
+		default:
+			f.Outcome = PositionShifted
+		}
+		forecasts[i] = f
+	}
+	return forecasts
+}
+
+func boundTick(tick int32) int32 {
+	if tick < MinTick {
+		return MinTick
+	}
+	if tick > MaxTick {
+		return MaxTick
+	}
+	return tick
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/lstrebalancehook"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_AlignTickShift(t *testing.T) {
+	tests := []struct {
+		shift   int32
+		spacing int32
+		mode    TickRounding
+		want    int32
+	}{
+		{49, 60, RoundNearest, 60},
+		{29, 60, RoundNearest, 0},
+		{30, 60, RoundNearest, 60},
+		{-30, 60, RoundNearest, -60},
+		{-89, 60, RoundNearest, -60},
+		{-91, 60, RoundNearest, -120},
+		{49, 60, RoundTowardZero, 0},
+		{-119, 60, RoundTowardZero, -60},
+		{1, 60, RoundAwayFromZero, 60},
+		{-61, 60, RoundAwayFromZero, -120},
+		{120, 60, RoundAwayFromZero, 120},
+		{-120, 60, RoundTowardZero, -120},
+		{0, 60, RoundAwayFromZero, 0},
+		{953, 10, RoundNearest, 950},
+		{955, 10, RoundNearest, 960},
+		{7, 1, RoundAwayFromZero, 7},
+	}
+
+	for _, tt := range tests {
+		if got := AlignTickShift(tt.shift, tt.spacing, tt.mode); got != tt.want {
+			t.Errorf("AlignTickShift(%d, %d, %s) = %d, want %d", tt.shift, tt.spacing, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func Test_ParseTickRounding(t *testing.T) {
+	for _, v := range []string{"nearest", "toward_zero", "away_from_zero"} {
+		if r, err := ParseTickRounding(v); err != nil || string(r) != v {
+			t.Errorf("ParseTickRounding(%q) = %q, %v", v, r, err)
+		}
+	}
+	if _, err := ParseTickRounding("up"); err == nil {
+		t.Error("ParseTickRounding accepted an unknown mode")
+	}
+}
+
+func Test_ForecastPositions(t *testing.T) {
+	position := func(lower, upper, liquidity int64) lstrebalancehook.LSTrebalanceHookLpPosition {
+		return lstrebalancehook.LSTrebalanceHookLpPosition{
+			Owner:     common.HexToAddress("0x1000000000000000000000000000000000000001"),
+			TickLower: big.NewInt(lower),
+			TickUpper: big.NewInt(upper),
+			Liquidity: big.NewInt(liquidity),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		position  lstrebalancehook.LSTrebalanceHookLpPosition
+		shift     int32
+		want      PositionOutcome
+		wantLower int32
+		wantUpper int32
+	}{
+		{"shifted", position(-600, 600, 1e6), 60, PositionShifted, -540, 660},
+		{"empty", position(-600, 600, 0), 60, PositionEmpty, -540, 660},
+		{"clamped upper", position(887100, 887220, 1e6), 120, PositionClamped, 887220, MaxTick},
+		{"clamped lower", position(-887220, -600, 1e6), -120, PositionClamped, MinTick, -720},
+		{"collapsed", position(887220, 887280, 1e6), 600, PositionCollapsed, MaxTick, MaxTick},
+		{"misaligned", position(-600, 600, 1e6), 49, PositionMisaligned, -551, 649},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ForecastPositions([]lstrebalancehook.LSTrebalanceHookLpPosition{tt.position}, tt.shift, 60)
+			if len(got) != 1 {
+				t.Fatalf("got %d forecasts, want 1", len(got))
+			}
+			f := got[0]
+			if f.Outcome != tt.want || f.NewTickLower != tt.wantLower || f.NewTickUpper != tt.wantUpper {
+				t.Errorf("forecast = %s [%d, %d], want %s [%d, %d]",
+					f.Outcome, f.NewTickLower, f.NewTickUpper, tt.want, tt.wantLower, tt.wantUpper)
+			}
+		})
+	}
+}
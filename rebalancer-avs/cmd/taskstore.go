@@ -0,0 +1,500 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	bolt "go.etcd.io/bbolt"
+)
+
+// defaultTaskStorePath is the default performer.task_store_path.
+const defaultTaskStorePath = "data/tasks.db"
+
+var (
+	tasksBucket = []byte("tasks")
+	// txIndexBucket maps each transaction hash of a task to its task hash.
+	txIndexBucket = []byte("txIndex")
+	// onchainIdIndexBucket holds onchainTaskId ‖ taskHash keys with empty
+	// values; distinct tasks may share an on-chain id.
+	onchainIdIndexBucket = []byte("onchainIdIndex")
+)
+
+// TaskState is a step of a task's lifecycle.
+type TaskState string
+
+const (
+	StateReceived  TaskState = "received"
+	StateValidated TaskState = "validated"
+	StatePlanned   TaskState = "planned"
+	StateSimulated TaskState = "simulated"
+	StateSubmitted TaskState = "submitted"
+	StateMined     TaskState = "mined"
+	StateConfirmed TaskState = "confirmed"
+	StateFailed    TaskState = "failed"
+	// StateSkipped is final: this operator answered the task but will not
+	// execute it, for the SkipReason recorded with it.
+	StateSkipped TaskState = "skipped"
+)
+
+// Reasons a task is skipped.
+const (
+	skipNotExecutor   = "not the hook's avsServiceManager"
+	skipNotWorthwhile = "plan rebalances no positions"
+)
+
+// stateOrder ranks the non-failed states; tasks only move forward.
+var stateOrder = map[TaskState]int{
+	StateReceived:  0,
+	StateValidated: 1,
+	StatePlanned:   2,
+	StateSimulated: 3,
+	StateSubmitted: 4,
+	StateMined:     5,
+	StateConfirmed: 6,
+}
+
+// ErrInvalidTransition is returned for a state change the lifecycle does not allow.
+var ErrInvalidTransition = errors.New("invalid task state transition")
+
+// canTransition reports whether a task in from may move to to. Tasks move
+// forward, may fail from any state but confirmed and skipped, are skipped only
+// once planned and before a transaction is sent, and a failed task may be
+// received again when the aggregator redelivers it.
+func canTransition(from, to TaskState) bool {
+	switch {
+	case from == to:
+		return true
+	case from == StateSkipped:
+		return false
+	case to == StateSkipped:
+		return from == StatePlanned || from == StateSimulated
+	case to == StateFailed:
+		return from != StateConfirmed
+	case from == StateFailed:
+		return to == StateReceived
+	default:
+		return stateOrder[to] > stateOrder[from]
+	}
+}
+
+// StateChange is one entry of a task's history.
+type StateChange struct {
+	State TaskState `json:"state"`
+	At    time.Time `json:"at"`
+}
+
+// StoredTask is the persisted record of a task.
+type StoredTask struct {
+	TaskId   string      `json:"taskId"`
+	TaskHash common.Hash `json:"taskHash"`
+	State    TaskState   `json:"state"`
+	// Result is the TaskResponse.Result returned for the task.
+	Result []byte      `json:"result,omitempty"`
+	PoolId common.Hash `json:"poolId"`
+	// RawTxs are the signed transaction and its replacements, oldest first,
+	// kept so receipt tracking can resume after a restart.
+	RawTxs            [][]byte `json:"rawTxs,omitempty"`
+	ExpectedPositions uint64   `json:"expectedPositions"`
+	// Simulation is the verdict of simulating the result at the task's
+	// reference block, recorded before the result is returned.
+	Simulation *SimulationVerdict `json:"simulation,omitempty"`
+	Outcome    *RebalanceOutcome  `json:"outcome,omitempty"`
+	Error      string             `json:"error,omitempty"`
+	SkipReason string             `json:"skipReason,omitempty"`
+	History    []StateChange      `json:"history"`
+}
+
+// Answered reports whether a redelivery of the task gets the stored result
+// back. A task that failed before any transaction was sent is handled again.
+func (t *StoredTask) Answered() bool {
+	return t != nil && t.Result != nil && (t.State != StateFailed || len(t.RawTxs) > 0)
+}
+
+// Transactions decodes RawTxs.
+func (t *StoredTask) Transactions() ([]*types.Transaction, error) {
+	txs := make([]*types.Transaction, len(t.RawTxs))
+	for i, raw := range t.RawTxs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(raw); err != nil {
+			return nil, fmt.Errorf("failed to decode stored transaction %d of task %s: %w", i, t.TaskId, err)
+		}
+		txs[i] = tx
+	}
+	return txs, nil
+}
+
+// TaskStore persists task records in a bbolt file, keyed by task hash. A nil
+// *TaskStore stores nothing.
+type TaskStore struct {
+	db  *bolt.DB
+	now func() time.Time
+}
+
+// OpenTaskStore opens or creates the store at path.
+func OpenTaskStore(path string) (*TaskStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create task store directory: %w", err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open task store %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{tasksBucket, txIndexBucket, onchainIdIndexBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize task store: %w", err)
+	}
+	return &TaskStore{db: db, now: time.Now}, nil
+}
+
+// Close closes the underlying file.
+func (s *TaskStore) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// Get returns the record of taskId, or nil if there is none.
+func (s *TaskStore) Get(taskId []byte) (*StoredTask, error) {
+	if s == nil {
+		return nil, nil
+	}
+	var task *StoredTask
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		task, err = getTask(tx, TaskHash(taskId))
+		return err
+	})
+	return task, err
+}
+
+// Transition moves taskId to state, creating the record if needed, and applies
+// update to it in the same transaction. update may be nil.
+func (s *TaskStore) Transition(taskId string, state TaskState, update func(*StoredTask)) error {
+	if s == nil {
+		return nil
+	}
+	taskHash := TaskHash([]byte(taskId))
+	return s.db.Update(func(tx *bolt.Tx) error {
+		task, err := getTask(tx, taskHash)
+		if err != nil {
+			return err
+		}
+		if task == nil {
+			task = &StoredTask{TaskId: taskId, TaskHash: taskHash, State: StateReceived}
+		} else if !canTransition(task.State, state) {
+			return fmt.Errorf("%w: task %s from %s to %s", ErrInvalidTransition, taskId, task.State, state)
+		}
+
+		if task.State != state || len(task.History) == 0 {
+			task.History = append(task.History, StateChange{State: state, At: s.now()})
+		}
+		task.State = state
+		if update != nil {
+			update(task)
+		}
+
+		encoded, err := json.Marshal(task)
+		if err != nil {
+			return fmt.Errorf("failed to encode task %s: %w", taskId, err)
+		}
+		if err := tx.Bucket(tasksBucket).Put(taskHash.Bytes(), encoded); err != nil {
+			return err
+		}
+		return indexTask(tx, task)
+	})
+}
+
+// indexTask records the reverse lookups of a task that has sent a
+// transaction: every transaction hash, the landed one included, and the
+// on-chain task id.
+func indexTask(tx *bolt.Tx, task *StoredTask) error {
+	if len(task.RawTxs) == 0 && task.Outcome == nil {
+		return nil
+	}
+	txs, err := task.Transactions()
+	if err != nil {
+		return err
+	}
+	hashes := make([]common.Hash, 0, len(txs)+1)
+	for _, t := range txs {
+		hashes = append(hashes, t.Hash())
+	}
+	if task.Outcome != nil {
+		hashes = append(hashes, task.Outcome.TxHash)
+	}
+	for _, h := range hashes {
+		if err := tx.Bucket(txIndexBucket).Put(h.Bytes(), task.TaskHash.Bytes()); err != nil {
+			return err
+		}
+	}
+	return tx.Bucket(onchainIdIndexBucket).Put(onchainIdKey(OnchainTaskId(task.TaskHash), task.TaskHash), nil)
+}
+
+func onchainIdKey(id uint32, taskHash common.Hash) []byte {
+	return append(binary.BigEndian.AppendUint32(nil, id), taskHash.Bytes()...)
+}
+
+// ForEach calls fn for every stored task.
+func (s *TaskStore) ForEach(fn func(*StoredTask) error) error {
+	if s == nil {
+		return nil
+	}
+	return s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(tasksBucket).ForEach(func(_, v []byte) error {
+			var task StoredTask
+			if err := json.Unmarshal(v, &task); err != nil {
+				return fmt.Errorf("failed to decode stored task: %w", err)
+			}
+			return fn(&task)
+		})
+	})
+}
+
+// Unconfirmed returns the tasks with a transaction submitted or mined but not
+// yet confirmed.
+func (s *TaskStore) Unconfirmed() ([]*StoredTask, error) {
+	var tasks []*StoredTask
+	err := s.ForEach(func(t *StoredTask) error {
+		if t.State == StateSubmitted || t.State == StateMined {
+			tasks = append(tasks, t)
+		}
+		return nil
+	})
+	return tasks, err
+}
+
+// Unexecuted returns the tasks whose result moves positions but that were
+// never handed a transaction: the performer stopped between persisting the
+// result and sending it. Skipped tasks are not included.
+func (s *TaskStore) Unexecuted() ([]*StoredTask, error) {
+	var tasks []*StoredTask
+	err := s.ForEach(func(t *StoredTask) error {
+		if (t.State == StatePlanned || t.State == StateSimulated) && t.Result != nil && t.ExpectedPositions > 0 {
+			tasks = append(tasks, t)
+		}
+		return nil
+	})
+	return tasks, err
+}
+
+// ByTx returns the task that sent txHash, or nil if there is none.
+func (s *TaskStore) ByTx(txHash common.Hash) (*StoredTask, error) {
+	if s == nil {
+		return nil, nil
+	}
+	var task *StoredTask
+	err := s.db.View(func(tx *bolt.Tx) error {
+		taskHash := tx.Bucket(txIndexBucket).Get(txHash.Bytes())
+		if taskHash == nil {
+			return nil
+		}
+		var err error
+		task, err = getTask(tx, common.BytesToHash(taskHash))
+		return err
+	})
+	return task, err
+}
+
+// ByOnchainTaskId returns the tasks that sent a transaction carrying id, the
+// taskId passed to executeRebalance.
+func (s *TaskStore) ByOnchainTaskId(id uint32) ([]*StoredTask, error) {
+	if s == nil {
+		return nil, nil
+	}
+	var tasks []*StoredTask
+	err := s.db.View(func(tx *bolt.Tx) error {
+		prefix := binary.BigEndian.AppendUint32(nil, id)
+		c := tx.Bucket(onchainIdIndexBucket).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			task, err := getTask(tx, common.BytesToHash(k[len(prefix):]))
+			if err != nil {
+				return err
+			}
+			if task != nil {
+				tasks = append(tasks, task)
+			}
+		}
+		return nil
+	})
+	return tasks, err
+}
+
+func getTask(tx *bolt.Tx, taskHash common.Hash) (*StoredTask, error) {
+	v := tx.Bucket(tasksBucket).Get(taskHash.Bytes())
+	if v == nil {
+		return nil, nil
+	}
+	var task StoredTask
+	if err := json.Unmarshal(v, &task); err != nil {
+		return nil, fmt.Errorf("failed to decode stored task %s: %w", taskHash.Hex(), err)
+	}
+	return &task, nil
+}
+
+// transition records a state change, logging instead of failing the task when
+// the store cannot be written.
+func (tw *TaskWorker) transition(taskId string, state TaskState, update func(*StoredTask)) {
+	if err := tw.store.Transition(taskId, state, update); err != nil {
+		tw.logger.Sugar().Errorw("Failed to persist task state",
+			"taskId", taskId,
+			"state", state,
+			"error", err,
+		)
+		return
+	}
+	tw.logger.Sugar().Debugw("Task state", "taskId", taskId, "state", state)
+}
+
+// failTask records err as the reason taskId failed.
+func (tw *TaskWorker) failTask(taskId string, err error) {
+	tw.transition(taskId, StateFailed, func(t *StoredTask) { t.Error = err.Error() })
+}
+
+// skipTask records that this operator will not execute taskId, and why.
+func (tw *TaskWorker) skipTask(taskId, reason string) {
+	tw.transition(taskId, StateSkipped, func(t *StoredTask) { t.SkipReason = reason })
+}
+
+// recordSubmitted stores a signed transaction of taskId, so its receipt can
+// still be tracked if the performer restarts before it is confirmed.
+func (tw *TaskWorker) recordSubmitted(taskId string, tx *types.Transaction) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		tw.logger.Sugar().Errorw("Failed to encode transaction for the task store",
+			"taskId", taskId,
+			"txHash", tx.Hash().Hex(),
+			"error", err,
+		)
+		return
+	}
+	tw.transition(taskId, StateSubmitted, func(t *StoredTask) { t.RawTxs = append(t.RawTxs, raw) })
+}
+
+// resumeTask picks up receipt tracking for a task whose transaction was sent
+// before the performer stopped. The newest transaction is rebroadcast in case
+// the node dropped it, and stuck ones are still replaced.
+func (tw *TaskWorker) resumeTask(ctx context.Context, backend receiptBackend, task *StoredTask) (*RebalanceOutcome, error) {
+	txs, err := task.Transactions()
+	if err != nil {
+		return nil, err
+	}
+	if len(txs) == 0 {
+		return nil, fmt.Errorf("task %s is %s but has no stored transaction", task.TaskId, task.State)
+	}
+	pool, ok := tw.pools.Lookup(task.PoolId)
+	if !ok {
+		return nil, &UnknownPoolError{PoolId: task.PoolId}
+	}
+
+	p := &pendingTx{
+		taskId: task.TaskId,
+		poolId: task.PoolId,
+		budget: pool.MaxTxCost,
+		sent:   txs,
+	}
+	latest := p.latest()
+	if tw.signer != nil {
+		p.from = tw.signer.Address()
+		p.signer = signer.SignerFn(ctx, tw.signer, latest.ChainId())
+	}
+
+	tw.logger.Sugar().Infow("🔁 Resuming receipt tracking",
+		"taskId", task.TaskId,
+		"state", task.State,
+		"txHashes", p.hashes(),
+	)
+	if err := backend.SendTransaction(ctx, latest); err != nil {
+		tw.logger.Sugar().Debugw("Rebroadcast of stored transaction not accepted",
+			"txHash", latest.Hash().Hex(),
+			"error", err,
+		)
+	}
+
+	outcome, err := tw.waitForRebalance(ctx, backend, p, task.ExpectedPositions)
+	if outcome != nil {
+		if err == nil {
+			tw.transition(task.TaskId, StateConfirmed, func(t *StoredTask) { t.Outcome = outcome })
+		}
+	}
+	return outcome, err
+}
+
+// taskCommand prints the stored tasks ref refers to: a TaskRequest task id or
+// task hash, a transaction hash of the task, or the decimal on-chain task id.
+// bbolt locks the store, so it runs against a stopped performer or a copy of
+// its store.
+func taskCommand(args []string, out io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintln(out, "usage: avs task <task id | task hash | tx hash | on-chain task id>")
+		return 2
+	}
+	ref := args[0]
+
+	path := os.Getenv("TASK_STORE_PATH")
+	if path == "" {
+		path = defaultTaskStorePath
+	}
+	store, err := OpenTaskStore(path)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 2
+	}
+	defer store.Close()
+
+	var tasks []*StoredTask
+	if id, err := strconv.ParseUint(ref, 10, 32); err == nil {
+		tasks, err = store.ByOnchainTaskId(uint32(id))
+		if err != nil {
+			fmt.Fprintln(out, err)
+			return 2
+		}
+	} else {
+		task, err := store.Get([]byte(ref))
+		if hash, decodeErr := hexutil.Decode(ref); err == nil && task == nil && decodeErr == nil && len(hash) == common.HashLength {
+			task, err = store.ByTx(common.BytesToHash(hash))
+		}
+		if err != nil {
+			fmt.Fprintln(out, err)
+			return 2
+		}
+		if task != nil {
+			tasks = append(tasks, task)
+		}
+	}
+	if len(tasks) == 0 {
+		fmt.Fprintf(out, "no task found for %s\n", ref)
+		return 1
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	for _, task := range tasks {
+		if err := enc.Encode(task); err != nil {
+			fmt.Fprintln(out, err)
+			return 2
+		}
+	}
+	return 0
+}
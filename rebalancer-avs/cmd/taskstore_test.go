@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func openTestTaskStore(t *testing.T, path string) *TaskStore {
+	t.Helper()
+
+	store, err := OpenTaskStore(path)
+	if err != nil {
+		t.Fatalf("OpenTaskStore failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func Test_CanTransition(t *testing.T) {
+	tests := []struct {
+		from, to TaskState
+		want     bool
+	}{
+		{StateReceived, StateValidated, true},
+		{StatePlanned, StateSubmitted, true},
+		{StateSubmitted, StateSubmitted, true},
+		{StateMined, StateConfirmed, true},
+		{StateSubmitted, StatePlanned, false},
+		{StateConfirmed, StateMined, false},
+		{StateSimulated, StateFailed, true},
+		{StateConfirmed, StateFailed, false},
+		{StateFailed, StateReceived, true},
+		{StateFailed, StateSubmitted, false},
+		{StatePlanned, StateSkipped, true},
+		{StateSimulated, StateSkipped, true},
+		{StateSubmitted, StateSkipped, false},
+		{StateSkipped, StateFailed, false},
+		{StateSkipped, StateReceived, false},
+	}
+
+	for _, tt := range tests {
+		if got := canTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("canTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func Test_TaskStoreLifecycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "tasks.db")
+	store := openTestTaskStore(t, path)
+	taskId := "0x" + common.Bytes2Hex(common.Hash{0x01}.Bytes())
+	poolId := common.Hash{0xaa}
+	tx := testTx(4)
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode tx: %v", err)
+	}
+
+	for _, state := range []TaskState{StateReceived, StateValidated} {
+		if err := store.Transition(taskId, state, nil); err != nil {
+			t.Fatalf("Transition(%s) failed: %v", state, err)
+		}
+	}
+	err = store.Transition(taskId, StatePlanned, func(st *StoredTask) {
+		st.Result = []byte{0x01, 0x02}
+		st.PoolId = poolId
+	})
+	if err != nil {
+		t.Fatalf("Transition(planned) failed: %v", err)
+	}
+	err = store.Transition(taskId, StateSubmitted, func(st *StoredTask) { st.RawTxs = append(st.RawTxs, raw) })
+	if err != nil {
+		t.Fatalf("Transition(submitted) failed: %v", err)
+	}
+
+	if err := store.Transition(taskId, StateValidated, nil); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition moving back, got %v", err)
+	}
+
+	// Reopen to check the record survives a restart.
+	store.Close()
+	store = openTestTaskStore(t, path)
+
+	got, err := store.Get([]byte(taskId))
+	if err != nil || got == nil {
+		t.Fatalf("Get = (%v, %v), want the stored task", got, err)
+	}
+	if got.State != StateSubmitted || got.PoolId != poolId || string(got.Result) != "\x01\x02" {
+		t.Errorf("stored task = %+v", got)
+	}
+	if got.TaskHash != TaskHash([]byte(taskId)) {
+		t.Errorf("taskHash = %s, want %s", got.TaskHash.Hex(), TaskHash([]byte(taskId)).Hex())
+	}
+	if len(got.History) != 4 {
+		t.Errorf("history has %d entries, want 4", len(got.History))
+	}
+	txs, err := got.Transactions()
+	if err != nil {
+		t.Fatalf("Transactions failed: %v", err)
+	}
+	if len(txs) != 1 || txs[0].Hash() != tx.Hash() {
+		t.Errorf("stored transactions do not round-trip")
+	}
+
+	unconfirmed, err := store.Unconfirmed()
+	if err != nil {
+		t.Fatalf("Unconfirmed failed: %v", err)
+	}
+	if len(unconfirmed) != 1 || unconfirmed[0].TaskId != taskId {
+		t.Errorf("Unconfirmed = %v, want the submitted task", unconfirmed)
+	}
+
+	if err := store.Transition(taskId, StateConfirmed, nil); err != nil {
+		t.Fatalf("Transition(confirmed) failed: %v", err)
+	}
+	if unconfirmed, _ := store.Unconfirmed(); len(unconfirmed) != 0 {
+		t.Errorf("confirmed task still unconfirmed")
+	}
+	if err := store.Transition(taskId, StateFailed, nil); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition failing a confirmed task, got %v", err)
+	}
+}
+
+func Test_NilTaskStore(t *testing.T) {
+	var store *TaskStore
+	if err := store.Transition("task", StatePlanned, nil); err != nil {
+		t.Errorf("Transition on nil store: %v", err)
+	}
+	if got, err := store.Get([]byte("task")); got != nil || err != nil {
+		t.Errorf("Get on nil store = (%v, %v)", got, err)
+	}
+	if tasks, err := store.Unconfirmed(); len(tasks) != 0 || err != nil {
+		t.Errorf("Unconfirmed on nil store = (%v, %v)", tasks, err)
+	}
+	if got, err := store.ByTx(common.Hash{0x01}); got != nil || err != nil {
+		t.Errorf("ByTx on nil store = (%v, %v)", got, err)
+	}
+	if tasks, err := store.ByOnchainTaskId(1); len(tasks) != 0 || err != nil {
+		t.Errorf("ByOnchainTaskId on nil store = (%v, %v)", tasks, err)
+	}
+}
+
+func Test_ResumeTask(t *testing.T) {
+	tw := newTestHookWorker(t)
+	tw.pools = testPoolRegistry(t)
+	tw.store = openTestTaskStore(t, filepath.Join(t.TempDir(), "tasks.db"))
+	poolId := testPoolId(t, tw.pools)
+
+	taskId := "task-resumed"
+	tx := testTx(9)
+	tw.transition(taskId, StatePlanned, func(st *StoredTask) {
+		st.PoolId = poolId
+		st.ExpectedPositions = 2
+	})
+	tw.recordSubmitted(taskId, tx)
+
+	chain := newFakeChain(10)
+	chain.mine(&types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		TxHash:      tx.Hash(),
+		BlockNumber: big.NewInt(10),
+		BlockHash:   common.Hash{0x0a},
+		Logs:        []*types.Log{rebalanceExecutedLog(t, testHookAddress, poolId, 2, 60)},
+	})
+
+	unconfirmed, err := tw.store.Unconfirmed()
+	if err != nil || len(unconfirmed) != 1 {
+		t.Fatalf("Unconfirmed = (%v, %v), want one task", unconfirmed, err)
+	}
+	outcome, err := tw.resumeTask(context.Background(), chain, unconfirmed[0])
+	if err != nil {
+		t.Fatalf("resumeTask failed: %v", err)
+	}
+	if outcome.TxHash != tx.Hash() || outcome.PositionsRebalanced != 2 {
+		t.Errorf("outcome = %+v", outcome)
+	}
+
+	stored, err := tw.store.Get([]byte(taskId))
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if stored.State != StateConfirmed || stored.Outcome == nil || stored.Outcome.TxHash != tx.Hash() {
+		t.Errorf("stored task = %+v, want confirmed with outcome", stored)
+	}
+}
+
+func Test_TaskStoreReverseLookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+	store := openTestTaskStore(t, path)
+	taskId := "task-1"
+	taskHash := TaskHash([]byte(taskId))
+	sent, landed := testTx(4), testTx(5)
+	raw, err := sent.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode tx: %v", err)
+	}
+
+	if err := store.Transition(taskId, StatePlanned, nil); err != nil {
+		t.Fatalf("Transition(planned) failed: %v", err)
+	}
+	if got, err := store.ByOnchainTaskId(OnchainTaskId(taskHash)); err != nil || len(got) != 0 {
+		t.Errorf("ByOnchainTaskId before a transaction = (%v, %v), want none", got, err)
+	}
+	if err := store.Transition(taskId, StateSubmitted, func(st *StoredTask) { st.RawTxs = [][]byte{raw} }); err != nil {
+		t.Fatalf("Transition(submitted) failed: %v", err)
+	}
+	// The replacement that landed is indexed from the outcome.
+	err = store.Transition(taskId, StateConfirmed, func(st *StoredTask) {
+		st.Outcome = &RebalanceOutcome{TxHash: landed.Hash(), BlockNumber: 12}
+	})
+	if err != nil {
+		t.Fatalf("Transition(confirmed) failed: %v", err)
+	}
+
+	for _, h := range []common.Hash{sent.Hash(), landed.Hash()} {
+		if got, err := store.ByTx(h); err != nil || got == nil || got.TaskId != taskId {
+			t.Errorf("ByTx(%s) = (%v, %v), want %s", h.Hex(), got, err, taskId)
+		}
+	}
+	if got, err := store.ByTx(common.Hash{0x01}); err != nil || got != nil {
+		t.Errorf("ByTx(unknown) = (%v, %v), want nil", got, err)
+	}
+	got, err := store.ByOnchainTaskId(OnchainTaskId(taskHash))
+	if err != nil || len(got) != 1 || got[0].TaskId != taskId {
+		t.Errorf("ByOnchainTaskId = (%v, %v), want %s", got, err, taskId)
+	}
+
+	// The task command looks tasks up by any of these references.
+	store.Close()
+	t.Setenv("TASK_STORE_PATH", path)
+	for _, ref := range []string{taskId, taskHash.Hex(), landed.Hash().Hex(), strconv.FormatUint(uint64(OnchainTaskId(taskHash)), 10)} {
+		var out bytes.Buffer
+		if code := taskCommand([]string{ref}, &out); code != 0 || !strings.Contains(out.String(), `"taskId": "task-1"`) {
+			t.Errorf("task %s = %d: %s", ref, code, out.String())
+		}
+	}
+	if code := taskCommand([]string{common.Hash{0x02}.Hex()}, io.Discard); code != 1 {
+		t.Errorf("task of an unknown hash = %d, want 1", code)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"math"
+	"math/big"
+)
+
+const (
+	// maxTickShift bounds a single rebalance, see the README's AVS configuration.
+	maxTickShift uint32 = 1000
+
+	bpsDenominator = 10_000
+)
+
+var (
+	// A tick is a price ratio of 1.0001 = tickBaseNum / tickBaseDen.
+	tickBaseNum = big.NewInt(10_001)
+	tickBaseDen = big.NewInt(10_000)
+)
+
+// YieldToTicks converts a yield in basis points into the number of ticks the
+// price moves when it grows by (1 + yield), log(1 + yield) / log(1.0001),
+// capped at limit.
+//
+// The result is the largest k with 1.0001^k <= 1 + yield, so it rounds toward
+// zero and a shift never overstates the yield. The comparison is done in exact
+// integer arithmetic, which makes the answer identical on every operator;
+// floating point is only used to pick the starting candidate.
+func YieldToTicks(yieldBps uint64, limit uint32) uint32 {
+	estimate := math.Log1p(float64(yieldBps)/bpsDenominator) / math.Log1p(1.0/bpsDenominator)
+	if estimate >= float64(limit)+1 {
+		return limit
+	}
+
+	k := uint32(estimate)
+	for k > 0 && !ticksWithinYield(k, yieldBps) {
+		k--
+	}
+	for k < limit && ticksWithinYield(k+1, yieldBps) {
+		k++
+	}
+	return k
+}
+
+// ticksWithinYield reports whether 1.0001^k <= (10000 + yieldBps) / 10000,
+// evaluated as 10001^k * 10000 <= (10000 + yieldBps) * 10000^k.
+func ticksWithinYield(k uint32, yieldBps uint64) bool {
+	exp := big.NewInt(int64(k))
+
+	lhs := new(big.Int).Exp(tickBaseNum, exp, nil)
+	lhs.Mul(lhs, tickBaseDen)
+
+	rhs := new(big.Int).Exp(tickBaseDen, exp, nil)
+	rhs.Mul(rhs, new(big.Int).Add(tickBaseDen, new(big.Int).SetUint64(yieldBps)))
+
+	return lhs.Cmp(rhs) <= 0
+}
+
+// TickShiftForYield returns the signed tick shift for an LST that appreciated
+// by yieldBps against the other currency of pool. v4 prices are currency1 per
+// currency0, so the price rises when the LST is currency0 and falls when it is
+// currency1.
+func TickShiftForYield(pool *Pool, yieldBps uint64, limit uint32) int32 {
+	ticks := int32(YieldToTicks(yieldBps, limit))
+	if !pool.LSTIsCurrency0 {
+		return -ticks
+	}
+	return ticks
+}
+
+// RelativeTickShift adjusts the yield-implied shift for where the pool trades.
+// yieldShift is how far the fair price moved since the positions were
+// centered; drift is the current tick minus the positions' center. Drift in
+// the yield's direction, up to yieldShift, is yield the market already priced
+// in. Drift beyond it or against it is followed, so the positions end up
+// centered on the current price plus whatever yield is not yet priced in.
+// This is the median of yieldShift, drift and yieldShift + drift.
+func RelativeTickShift(yieldShift, drift int32) int32 {
+	a, b, c := yieldShift, drift, yieldShift+drift
+	if a > b {
+		a, b = b, a
+	}
+	if b > c {
+		b = c
+	}
+	if a > b {
+		return a
+	}
+	return b
+}
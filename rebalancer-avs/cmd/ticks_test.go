@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_YieldToTicks(t *testing.T) {
+	// Expected values are floor(log(1 + bps/1e4) / log(1.0001)).
+	tests := []struct {
+		yieldBps uint64
+		limit    uint32
+		want     uint32
+	}{
+		{0, maxTickShift, 0},
+		{1, maxTickShift, 1}, // 1.0001^1 is exactly 1 bp
+		{2, maxTickShift, 1}, // 1.99990 ticks rounds toward zero
+		{10, maxTickShift, 9},
+		{25, maxTickShift, 24},
+		{50, maxTickShift, 49},
+		{100, maxTickShift, 99},
+		{500, maxTickShift, 487},
+		{1000, maxTickShift, 953},
+		{1052, maxTickShift, 1000},
+		{1100, maxTickShift, 1000},
+		{1100, 2000, 1043},
+		{10_000, 10_000, 6931}, // doubling
+		{math.MaxUint64, maxTickShift, maxTickShift},
+	}
+
+	for _, tt := range tests {
+		if got := YieldToTicks(tt.yieldBps, tt.limit); got != tt.want {
+			t.Errorf("YieldToTicks(%d, %d) = %d, want %d", tt.yieldBps, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func Test_YieldToTicksBracketsPrice(t *testing.T) {
+	for bps := uint64(0); bps <= 1052; bps++ {
+		k := YieldToTicks(bps, maxTickShift)
+		if !ticksWithinYield(k, bps) {
+			t.Fatalf("%d bps: 1.0001^%d exceeds 1 + yield", bps, k)
+		}
+		if k < maxTickShift && ticksWithinYield(k+1, bps) {
+			t.Fatalf("%d bps: %d ticks is not the largest within the yield", bps, k)
+		}
+	}
+}
+
+func Test_TickShiftForYield(t *testing.T) {
+	pools := testPoolRegistry(t)
+	pool, _ := pools.Lookup(testPoolId(t, pools))
+
+	lst0 := *pool
+	lst0.LSTIsCurrency0 = true
+	if got := TickShiftForYield(&lst0, 50, maxTickShift); got != 49 {
+		t.Errorf("LST as currency0: shift = %d, want 49", got)
+	}
+
+	lst1 := *pool
+	lst1.LSTIsCurrency0 = false
+	if got := TickShiftForYield(&lst1, 50, maxTickShift); got != -49 {
+		t.Errorf("LST as currency1: shift = %d, want -49", got)
+	}
+	if got := TickShiftForYield(&lst1, 5000, maxTickShift); got != -int32(maxTickShift) {
+		t.Errorf("LST as currency1: capped shift = %d, want %d", got, -int32(maxTickShift))
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// MinYieldThresholdBps mirrors the hook's MIN_YIELD_THRESHOLD.
+	MinYieldThresholdBps uint64 = 10
+
+	// defaultMaxTaskAge is the age past which tasks are rejected as stale
+	// (performer.task_max_age).
+	defaultMaxTaskAge = 30 * time.Minute
+
+	// maxClockSkew tolerates task timestamps slightly ahead of the local clock.
+	maxClockSkew = time.Minute
+)
+
+// Rejection categories. Every validation error matches exactly one of these
+// through errors.Is, so callers can group rejections without knowing every type.
+var (
+	ErrStaleTask        = errors.New("stale task")
+	ErrMalformedTask    = errors.New("malformed task")
+	ErrUnauthorizedTask = errors.New("unauthorized task")
+	ErrIneligibleTask   = errors.New("ineligible task")
+)
+
+// StaleTaskError is returned when the task timestamp is older than the admission window.
+type StaleTaskError struct {
+	Timestamp time.Time
+	Age       time.Duration
+	MaxAge    time.Duration
+}
+
+func (e *StaleTaskError) Error() string {
+	return fmt.Sprintf("stale task: timestamp %s is %s old, max age is %s",
+		e.Timestamp.UTC().Format(time.RFC3339), e.Age.Truncate(time.Second), e.MaxAge)
+}
+
+func (e *StaleTaskError) Is(target error) bool { return target == ErrStaleTask }
+
+// FutureTaskError is returned when the task timestamp is ahead of the local clock.
+type FutureTaskError struct {
+	Timestamp time.Time
+	Ahead     time.Duration
+}
+
+func (e *FutureTaskError) Error() string {
+	return fmt.Sprintf("malformed task: timestamp %s is %s in the future",
+		e.Timestamp.UTC().Format(time.RFC3339), e.Ahead.Truncate(time.Second))
+}
+
+func (e *FutureTaskError) Is(target error) bool { return target == ErrMalformedTask }
+
+// InvalidTimestampError is returned when the task timestamp does not fit a
+// Unix time. Converting it would wrap negative and report the task as stale.
+type InvalidTimestampError struct {
+	Timestamp uint64
+}
+
+func (e *InvalidTimestampError) Error() string {
+	return fmt.Sprintf("malformed task: timestamp %d is out of range", e.Timestamp)
+}
+
+func (e *InvalidTimestampError) Is(target error) bool { return target == ErrMalformedTask }
+
+// YieldBelowThresholdError is returned when the reported yield would not have
+// triggered RebalanceRequested on the hook.
+type YieldBelowThresholdError struct {
+	YieldBps  uint64
+	Threshold uint64
+}
+
+func (e *YieldBelowThresholdError) Error() string {
+	return fmt.Sprintf("ineligible task: yield %d bps is below threshold %d bps", e.YieldBps, e.Threshold)
+}
+
+func (e *YieldBelowThresholdError) Is(target error) bool { return target == ErrIneligibleTask }
+
+// UnknownPoolError is returned when the task targets a pool this performer is not configured for.
+type UnknownPoolError struct {
+	PoolId common.Hash
+}
+
+func (e *UnknownPoolError) Error() string {
+	return fmt.Sprintf("unauthorized task: pool %s is not configured", e.PoolId.Hex())
+}
+
+func (e *UnknownPoolError) Is(target error) bool { return target == ErrUnauthorizedTask }
+
+// NoPositionsError is returned when the task reports no positions to rebalance.
+type NoPositionsError struct {
+	PoolId common.Hash
+}
+
+func (e *NoPositionsError) Error() string {
+	return fmt.Sprintf("ineligible task: pool %s has no positions to rebalance", e.PoolId.Hex())
+}
+
+func (e *NoPositionsError) Is(target error) bool { return target == ErrIneligibleTask }
+
+// InvalidCumulativeYieldError is returned when the cumulative yield cannot be
+// reached from the reported yield. The hook adds yieldBps to the running total
+// before emitting, so the total is never smaller than a single observation.
+type InvalidCumulativeYieldError struct {
+	YieldBps        uint64
+	CumulativeYield uint64
+}
+
+func (e *InvalidCumulativeYieldError) Error() string {
+	return fmt.Sprintf("malformed task: cumulative yield %d bps is less than yield %d bps",
+		e.CumulativeYield, e.YieldBps)
+}
+
+func (e *InvalidCumulativeYieldError) Is(target error) bool { return target == ErrMalformedTask }
+
+// validateTaskData runs the semantic admission checks on a decoded payload.
+func (tw *TaskWorker) validateTaskData(data *RebalanceTaskData) error {
+	poolId := common.Hash(data.PoolId)
+
+	if _, ok := tw.pools.Lookup(poolId); !ok {
+		return &UnknownPoolError{PoolId: poolId}
+	}
+
+	if data.Timestamp > math.MaxInt64 {
+		return &InvalidTimestampError{Timestamp: data.Timestamp}
+	}
+	now := tw.now()
+	ts := time.Unix(int64(data.Timestamp), 0)
+	if age := now.Sub(ts); age > tw.maxTaskAge {
+		return &StaleTaskError{Timestamp: ts, Age: age, MaxAge: tw.maxTaskAge}
+	} else if -age > maxClockSkew {
+		return &FutureTaskError{Timestamp: ts, Ahead: -age}
+	}
+
+	if data.CumulativeYield < data.YieldBps {
+		return &InvalidCumulativeYieldError{YieldBps: data.YieldBps, CumulativeYield: data.CumulativeYield}
+	}
+
+	if data.YieldBps < MinYieldThresholdBps {
+		return &YieldBelowThresholdError{YieldBps: data.YieldBps, Threshold: MinYieldThresholdBps}
+	}
+
+	if data.PositionCount == 0 {
+		return &NoPositionsError{PoolId: poolId}
+	}
+
+	return nil
+}
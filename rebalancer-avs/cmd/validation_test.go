@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func Test_ValidateTaskData(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	pools := testPoolRegistry(t)
+	poolId := [32]byte(testPoolId(t, pools))
+
+	tw := &TaskWorker{
+		logger:     zap.NewNop(),
+		pools:      pools,
+		maxTaskAge: 10 * time.Minute,
+		now:        func() time.Time { return now },
+	}
+
+	valid := func() *RebalanceTaskData {
+		return &RebalanceTaskData{
+			PoolId:          poolId,
+			YieldBps:        25,
+			CumulativeYield: 40,
+			PositionCount:   2,
+			Timestamp:       uint64(now.Add(-time.Minute).Unix()),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		mutate   func(d *RebalanceTaskData)
+		wantErr  error
+		wantType any
+	}{
+		{"valid", func(d *RebalanceTaskData) {}, nil, nil},
+		{"threshold yield", func(d *RebalanceTaskData) { d.YieldBps = MinYieldThresholdBps }, nil, nil},
+		{"stale", func(d *RebalanceTaskData) { d.Timestamp = uint64(now.Add(-11 * time.Minute).Unix()) }, ErrStaleTask, &StaleTaskError{}},
+		{"future", func(d *RebalanceTaskData) { d.Timestamp = uint64(now.Add(5 * time.Minute).Unix()) }, ErrMalformedTask, &FutureTaskError{}},
+		{"timestamp beyond int64", func(d *RebalanceTaskData) { d.Timestamp = math.MaxInt64 + 1 }, ErrMalformedTask, &InvalidTimestampError{}},
+		{"unknown pool", func(d *RebalanceTaskData) { d.PoolId = [32]byte{0x02} }, ErrUnauthorizedTask, &UnknownPoolError{}},
+		{"low yield", func(d *RebalanceTaskData) { d.YieldBps = 9 }, ErrIneligibleTask, &YieldBelowThresholdError{}},
+		{"no positions", func(d *RebalanceTaskData) { d.PositionCount = 0 }, ErrIneligibleTask, &NoPositionsError{}},
+		{"impossible cumulative", func(d *RebalanceTaskData) { d.CumulativeYield = 5 }, ErrMalformedTask, &InvalidCumulativeYieldError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := valid()
+			tt.mutate(d)
+
+			err := tw.validateTaskData(d)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if got, want := fmt.Sprintf("%T", err), fmt.Sprintf("%T", tt.wantType); got != want {
+				t.Errorf("error type = %s, want %s", got, want)
+			}
+		})
+	}
+}
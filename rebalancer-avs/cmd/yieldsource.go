@@ -0,0 +1,388 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Yield source types accepted in the pools config.
+const (
+	YieldSourceStETH   = "steth"
+	YieldSourceWstETH  = "wsteth"
+	YieldSourceRETH    = "reth"
+	YieldSourceERC4626 = "erc4626"
+
+	// defaultYieldToleranceBps is the default performer.yield_tolerance_bps.
+	defaultYieldToleranceBps uint64 = 5
+)
+
+var (
+	oneEther = big.NewInt(1e18)
+
+	yieldSourceABI = func() abi.ABI {
+		parsed, err := abi.JSON(strings.NewReader(`[
+			{"type": "function", "name": "getPooledEthByShares", "stateMutability": "view",
+			 "inputs": [{"name": "sharesAmount", "type": "uint256"}], "outputs": [{"name": "", "type": "uint256"}]},
+			{"type": "function", "name": "stEthPerToken", "stateMutability": "view",
+			 "inputs": [], "outputs": [{"name": "", "type": "uint256"}]},
+			{"type": "function", "name": "getExchangeRate", "stateMutability": "view",
+			 "inputs": [], "outputs": [{"name": "", "type": "uint256"}]},
+			{"type": "function", "name": "convertToAssets", "stateMutability": "view",
+			 "inputs": [{"name": "shares", "type": "uint256"}], "outputs": [{"name": "", "type": "uint256"}]},
+			{"type": "function", "name": "decimals", "stateMutability": "view",
+			 "inputs": [], "outputs": [{"name": "", "type": "uint8"}]}
+		]`))
+		if err != nil {
+			panic(fmt.Errorf("failed to parse yield source ABI: %w", err))
+		}
+		return parsed
+	}()
+)
+
+// YieldSource reads an LST's redemption rate, the amount of underlying one
+// token redeems for. Rates from one source are comparable with each other;
+// their scale differs between sources.
+type YieldSource interface {
+	// Name identifies the source in logs.
+	Name() string
+	// RedemptionRate returns the rate at block, or at the latest block when
+	// block is nil.
+	RedemptionRate(ctx context.Context, block *big.Int) (*big.Int, error)
+}
+
+// YieldSourceConfig selects a pool's yield source in the pools config.
+type YieldSourceConfig struct {
+	// Type is one of steth, wsteth, reth or erc4626.
+	Type string `yaml:"type"`
+	// Address is the token or vault contract.
+	Address string `yaml:"address"`
+	// Chain is l1 or l2, the client the contract is read through. Defaults to l1.
+	Chain string `yaml:"chain"`
+}
+
+func (c *YieldSourceConfig) verify() error {
+	switch c.Type {
+	case YieldSourceStETH, YieldSourceWstETH, YieldSourceRETH, YieldSourceERC4626:
+	default:
+		return fmt.Errorf("unknown yield source type %q", c.Type)
+	}
+	if _, err := parseAddress("yield_source.address", c.Address, false); err != nil {
+		return err
+	}
+	switch c.Chain {
+	case "", "l1", "l2":
+	default:
+		return fmt.Errorf("yield_source.chain must be l1 or l2, got %q", c.Chain)
+	}
+	return nil
+}
+
+// NewYieldSource builds the adapter for cfg, reading through the L1 or L2 client.
+func NewYieldSource(cfg *YieldSourceConfig, l1, l2 bind.ContractCaller) (YieldSource, error) {
+	if err := cfg.verify(); err != nil {
+		return nil, err
+	}
+
+	caller := l1
+	if cfg.Chain == "l2" {
+		caller = l2
+	}
+	if caller == nil {
+		chain := cfg.Chain
+		if chain == "" {
+			chain = "l1"
+		}
+		return nil, fmt.Errorf("yield source %s needs an %s client", cfg.Type, chain)
+	}
+
+	c := contractReader{caller: caller, address: common.HexToAddress(cfg.Address)}
+	switch cfg.Type {
+	case YieldSourceStETH:
+		return &StETHSource{c}, nil
+	case YieldSourceWstETH:
+		return &WstETHSource{c}, nil
+	case YieldSourceRETH:
+		return &RETHSource{c}, nil
+	default:
+		return &ERC4626Source{c}, nil
+	}
+}
+
+type contractReader struct {
+	caller  bind.ContractCaller
+	address common.Address
+}
+
+func (c contractReader) callUint(ctx context.Context, block *big.Int, method string, args ...interface{}) (*big.Int, error) {
+	input, err := yieldSourceABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+	output, err := c.caller.CallContract(ctx, ethereum.CallMsg{To: &c.address, Data: input}, block)
+	if err != nil {
+		return nil, fmt.Errorf("%s on %s failed: %w", method, c.address.Hex(), err)
+	}
+	values, err := yieldSourceABI.Unpack(method, output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s from %s: %w", method, c.address.Hex(), err)
+	}
+	switch v := values[0].(type) {
+	case *big.Int:
+		return v, nil
+	case uint8:
+		return big.NewInt(int64(v)), nil
+	default:
+		return nil, fmt.Errorf("unexpected %s result %T", method, v)
+	}
+}
+
+// StETHSource reads Lido stETH's getPooledEthByShares(1e18).
+type StETHSource struct{ contractReader }
+
+func (s *StETHSource) Name() string { return "stETH@" + s.address.Hex() }
+
+func (s *StETHSource) RedemptionRate(ctx context.Context, block *big.Int) (*big.Int, error) {
+	return s.callUint(ctx, block, "getPooledEthByShares", oneEther)
+}
+
+// WstETHSource reads wstETH's stEthPerToken().
+type WstETHSource struct{ contractReader }
+
+func (s *WstETHSource) Name() string { return "wstETH@" + s.address.Hex() }
+
+func (s *WstETHSource) RedemptionRate(ctx context.Context, block *big.Int) (*big.Int, error) {
+	return s.callUint(ctx, block, "stEthPerToken")
+}
+
+// RETHSource reads Rocket Pool rETH's getExchangeRate().
+type RETHSource struct{ contractReader }
+
+func (s *RETHSource) Name() string { return "rETH@" + s.address.Hex() }
+
+func (s *RETHSource) RedemptionRate(ctx context.Context, block *big.Int) (*big.Int, error) {
+	return s.callUint(ctx, block, "getExchangeRate")
+}
+
+// ERC4626Source reads convertToAssets for one whole share of an ERC-4626 vault.
+type ERC4626Source struct{ contractReader }
+
+func (s *ERC4626Source) Name() string { return "ERC4626@" + s.address.Hex() }
+
+func (s *ERC4626Source) RedemptionRate(ctx context.Context, block *big.Int) (*big.Int, error) {
+	decimals, err := s.callUint(ctx, block, "decimals")
+	if err != nil {
+		return nil, err
+	}
+	oneShare := new(big.Int).Exp(big.NewInt(10), decimals, nil)
+	return s.callUint(ctx, block, "convertToAssets", oneShare)
+}
+
+// YieldMismatchError is returned when the payload's YieldBps disagrees with
+// the yield measured from the LST's redemption rate.
+type YieldMismatchError struct {
+	PoolId      common.Hash
+	Source      string
+	ReportedBps uint64
+	MeasuredBps int64
+	Tolerance   uint64
+}
+
+func (e *YieldMismatchError) Error() string {
+	return fmt.Sprintf("malformed task: pool %s reports %d bps yield but %s measured %d bps (tolerance %d bps)",
+		e.PoolId.Hex(), e.ReportedBps, e.Source, e.MeasuredBps, e.Tolerance)
+}
+
+func (e *YieldMismatchError) Is(target error) bool { return target == ErrMalformedTask }
+
+// YieldBaselineError is returned when the redemption rate the task's yield is
+// measured against cannot be established. The cross-check fails closed.
+type YieldBaselineError struct {
+	PoolId common.Hash
+	Reason string
+}
+
+func (e *YieldBaselineError) Error() string {
+	return fmt.Sprintf("ineligible task: no yield baseline for pool %s: %s", e.PoolId.Hex(), e.Reason)
+}
+
+func (e *YieldBaselineError) Is(target error) bool { return target == ErrIneligibleTask }
+
+// YieldBps returns (current - base) / base in basis points, rounded toward zero.
+func YieldBps(base, current *big.Int) int64 {
+	delta := new(big.Int).Sub(current, base)
+	delta.Mul(delta, big.NewInt(bpsDenominator))
+	return delta.Quo(delta, base).Int64()
+}
+
+// checkYield measures the pool's yield from the LST's redemption rate and
+// compares it with the payload. The baseline is the rate when the hook recorded
+// the balance the task's yield is measured against; both rates are read at
+// blocks derived from ref, so every operator compares the same numbers.
+func (tw *TaskWorker) checkYield(ctx context.Context, l1, l2 headerBackend, pool *Pool, data *RebalanceTaskData, ref *TaskReference) error {
+	source, ok := tw.yieldSources[pool.Id]
+	if !ok {
+		return nil
+	}
+	if ref == nil || l2 == nil {
+		return &YieldBaselineError{PoolId: pool.Id, Reason: "the task was not located on L2"}
+	}
+
+	baseBlock, err := tw.yieldBaselineBlock(ctx, l2, pool, data, ref)
+	if err != nil {
+		return err
+	}
+	chain := "l1"
+	if pool.YieldSource != nil && pool.YieldSource.Chain == "l2" {
+		chain = "l2"
+	}
+	baseAt, err := rateBlock(ctx, l1, l2, chain, baseBlock)
+	if err != nil {
+		return err
+	}
+	rateAt, err := rateBlock(ctx, l1, l2, chain, ref.BlockNumber)
+	if err != nil {
+		return err
+	}
+
+	base, err := source.RedemptionRate(ctx, baseAt)
+	if err != nil {
+		return fmt.Errorf("failed to read baseline redemption rate: %w", err)
+	}
+	if base.Sign() == 0 {
+		return &YieldBaselineError{PoolId: pool.Id, Reason: fmt.Sprintf("%s rate is zero at %s block %s", source.Name(), chain, baseAt)}
+	}
+	rate, err := source.RedemptionRate(ctx, rateAt)
+	if err != nil {
+		return fmt.Errorf("failed to read redemption rate: %w", err)
+	}
+
+	measured := YieldBps(base, rate)
+	tw.logger.Sugar().Infow("📏 Measured yield from redemption rate",
+		"poolId", pool.Id.Hex(),
+		"source", source.Name(),
+		"baseRate", base,
+		"baseBlock", baseAt,
+		"rate", rate,
+		"rateBlock", rateAt,
+		"measuredBps", measured,
+		"reportedBps", data.YieldBps,
+	)
+
+	diff := measured - int64(data.YieldBps)
+	if diff < 0 {
+		diff = -diff
+	}
+	if data.YieldBps > math.MaxInt64 || uint64(diff) > tw.yieldToleranceBps {
+		return &YieldMismatchError{
+			PoolId:      pool.Id,
+			Source:      source.Name(),
+			ReportedBps: data.YieldBps,
+			MeasuredBps: measured,
+			Tolerance:   tw.yieldToleranceBps,
+		}
+	}
+	return nil
+}
+
+// maxBaselineChecks bounds how many of the hook's yield checks are walked back
+// to find the one that recorded the task's previous balance.
+const maxBaselineChecks = 16
+
+// yieldBaselineBlock returns the L2 block at which the hook recorded the LST
+// balance the task's yield was measured against. The hook moves that balance
+// only on checks that saw it grow, so the walk starts at its last check before
+// the task and steps back one check at a time until the balance differs.
+func (tw *TaskWorker) yieldBaselineBlock(ctx context.Context, headers headerBackend, pool *Pool, data *RebalanceTaskData, ref *TaskReference) (uint64, error) {
+	fail := func(format string, args ...interface{}) error {
+		return &YieldBaselineError{PoolId: pool.Id, Reason: fmt.Sprintf(format, args...)}
+	}
+	if data.YieldAmount == nil || data.LSTBalance == nil || data.YieldAmount.Cmp(data.LSTBalance) > 0 {
+		return 0, fail("yield amount exceeds the LST balance")
+	}
+	previous := new(big.Int).Sub(data.LSTBalance, data.YieldAmount)
+
+	yieldInfo := func(block uint64) (*big.Int, uint64, error) {
+		info, err := tw.hook.GetYieldInfo(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(block)}, pool.Id)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read yield info for pool %s at block %d: %w", pool.Id.Hex(), block, err)
+		}
+		if !info.LastCheck.IsUint64() {
+			return nil, 0, fail("last check %s at block %d is out of range", info.LastCheck, block)
+		}
+		return info.LastBalance, info.LastCheck.Uint64(), nil
+	}
+
+	if ref.BlockNumber == 0 {
+		return 0, fail("the task is in the genesis block")
+	}
+	block := ref.BlockNumber - 1
+	for i := 0; i < maxBaselineChecks; i++ {
+		balance, lastCheck, err := yieldInfo(block)
+		if err != nil {
+			return 0, err
+		}
+		if balance.Cmp(previous) != 0 {
+			return 0, fail("hook balance at block %d is %s, the task measured yield against %s", block, balance, previous)
+		}
+		if lastCheck == 0 {
+			return 0, fail("the hook has no yield check before block %d", block)
+		}
+		checked, err := blockAtTime(ctx, headers, block, lastCheck)
+		if err != nil {
+			return 0, err
+		}
+		if checked == 0 || checked > block {
+			return 0, fail("no block at the hook's last check time %d", lastCheck)
+		}
+		before, _, err := yieldInfo(checked - 1)
+		if err != nil {
+			return 0, err
+		}
+		if before.Cmp(previous) != 0 {
+			return checked, nil
+		}
+		block = checked - 1
+	}
+	return 0, fail("balance %s predates the last %d yield checks", previous, maxBaselineChecks)
+}
+
+// rateBlock maps an L2 block to the block a yield source on chain is read at:
+// the block itself on L2, the last L1 block at or before its timestamp on L1.
+func rateBlock(ctx context.Context, l1, l2 headerBackend, chain string, l2Block uint64) (*big.Int, error) {
+	if chain == "l2" {
+		return new(big.Int).SetUint64(l2Block), nil
+	}
+	if l1 == nil {
+		return nil, fmt.Errorf("yield source needs an l1 client")
+	}
+	header, err := l2.HeaderByNumber(ctx, new(big.Int).SetUint64(l2Block))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L2 header %d: %w", l2Block, err)
+	}
+	head, err := l1.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L1 head: %w", err)
+	}
+	// Wait for a later L1 block so that no other node can see one more block
+	// at or before the timestamp.
+	if head.Time <= header.Time {
+		return nil, fmt.Errorf("L1 head %s at %d has not passed L2 block %d at %d", head.Number, head.Time, l2Block, header.Time)
+	}
+	after, err := searchBlock(ctx, l1, head.Number.Uint64(), func(h *types.Header) bool { return h.Time > header.Time })
+	if err != nil {
+		return nil, err
+	}
+	if after == 0 {
+		return nil, fmt.Errorf("no L1 block at or before %d", header.Time)
+	}
+	return new(big.Int).SetUint64(after - 1), nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+var testLST = common.HexToAddress("0xae7ab96520DE3A18E5e111B5EaAb095312D7fE84")
+
+// rateChain answers yield source calls on testLST with the given results,
+// keyed by method name.
+func rateChain(t *testing.T, results map[string]interface{}) *fakeChain {
+	t.Helper()
+
+	chain := newFakeChain(10)
+	chain.callFn = func(msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+		if *msg.To != testLST {
+			t.Errorf("called %s, want %s", msg.To.Hex(), testLST.Hex())
+		}
+		method, err := yieldSourceABI.MethodById(msg.Data[:4])
+		if err != nil {
+			t.Fatalf("unknown selector %x", msg.Data[:4])
+		}
+		result, ok := results[method.Name]
+		if !ok {
+			t.Fatalf("unexpected call to %s", method.Name)
+		}
+		return method.Outputs.Pack(result)
+	}
+	return chain
+}
+
+func Test_YieldSources(t *testing.T) {
+	rate, _ := new(big.Int).SetString("1180000000000000000", 10)
+
+	tests := []struct {
+		typ     string
+		results map[string]interface{}
+	}{
+		{YieldSourceStETH, map[string]interface{}{"getPooledEthByShares": rate}},
+		{YieldSourceWstETH, map[string]interface{}{"stEthPerToken": rate}},
+		{YieldSourceRETH, map[string]interface{}{"getExchangeRate": rate}},
+		{YieldSourceERC4626, map[string]interface{}{"decimals": uint8(18), "convertToAssets": rate}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			chain := rateChain(t, tt.results)
+			source, err := NewYieldSource(&YieldSourceConfig{Type: tt.typ, Address: testLST.Hex()}, chain, nil)
+			if err != nil {
+				t.Fatalf("NewYieldSource failed: %v", err)
+			}
+			got, err := source.RedemptionRate(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("RedemptionRate failed: %v", err)
+			}
+			if got.Cmp(rate) != 0 {
+				t.Errorf("rate = %s, want %s", got, rate)
+			}
+		})
+	}
+}
+
+func Test_NewYieldSourceConfig(t *testing.T) {
+	chain := newFakeChain(10)
+
+	tests := []struct {
+		name string
+		cfg  YieldSourceConfig
+		l2   bool
+	}{
+		{"unknown type", YieldSourceConfig{Type: "cbeth", Address: testLST.Hex()}, false},
+		{"bad address", YieldSourceConfig{Type: YieldSourceRETH, Address: "0x12"}, false},
+		{"bad chain", YieldSourceConfig{Type: YieldSourceRETH, Address: testLST.Hex(), Chain: "l3"}, false},
+		{"missing l2 client", YieldSourceConfig{Type: YieldSourceRETH, Address: testLST.Hex(), Chain: "l2"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewYieldSource(&tt.cfg, chain, nil); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func Test_YieldBps(t *testing.T) {
+	tests := []struct {
+		base, current int64
+		want          int64
+	}{
+		{1_000_000, 1_000_000, 0},
+		{1_000_000, 1_005_000, 50},
+		{1_000_000, 1_005_099, 50},
+		{1_000_000, 999_000, -10},
+	}
+
+	for _, tt := range tests {
+		if got := YieldBps(big.NewInt(tt.base), big.NewInt(tt.current)); got != tt.want {
+			t.Errorf("YieldBps(%d, %d) = %d, want %d", tt.base, tt.current, got, tt.want)
+		}
+	}
+}
+
+func Test_CheckYield(t *testing.T) {
+	ctx := context.Background()
+
+	check := func(t *testing.T, h *hookHistory, data *RebalanceTaskData) error {
+		chain := h.chain(t, 60)
+		tw := h.worker(t, chain)
+		ref, err := tw.locateTask(ctx, chain, h.pool, h.data)
+		if err != nil {
+			t.Fatalf("locateTask failed: %v", err)
+		}
+		return tw.checkYield(ctx, nil, chain, h.pool, data, ref)
+	}
+
+	t.Run("within tolerance", func(t *testing.T) {
+		h := newHookHistory(t)
+		data := *h.data
+		data.YieldBps = 47
+		if err := check(t, h, &data); err != nil {
+			t.Fatalf("checkYield failed: %v", err)
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		h := newHookHistory(t)
+		data := *h.data
+		data.YieldBps = 500
+		err := check(t, h, &data)
+
+		var mismatch *YieldMismatchError
+		if !errors.As(err, &mismatch) || !errors.Is(err, ErrMalformedTask) {
+			t.Fatalf("expected *YieldMismatchError, got %v", err)
+		}
+		if mismatch.MeasuredBps != 50 {
+			t.Errorf("measured = %d bps, want 50 since the baseline at block %d", mismatch.MeasuredBps, historyBaselineBlock)
+		}
+	})
+
+	t.Run("balance not on chain", func(t *testing.T) {
+		h := newHookHistory(t)
+		data := *h.data
+		data.YieldAmount = ether(3)
+		var baseline *YieldBaselineError
+		if err := check(t, h, &data); !errors.As(err, &baseline) || !errors.Is(err, ErrIneligibleTask) {
+			t.Fatalf("expected *YieldBaselineError, got %v", err)
+		}
+	})
+
+	t.Run("not located", func(t *testing.T) {
+		h := newHookHistory(t)
+		tw := h.worker(t, h.chain(t, 60))
+		var baseline *YieldBaselineError
+		if err := tw.checkYield(ctx, nil, nil, h.pool, h.data, nil); !errors.As(err, &baseline) {
+			t.Fatalf("expected *YieldBaselineError, got %v", err)
+		}
+	})
+
+	t.Run("l1 source", func(t *testing.T) {
+		h := newHookHistory(t)
+		l2 := h.chain(t, 60)
+		tw := h.worker(t, l2)
+		h.pool.YieldSource.Chain = "l1"
+
+		// L1 blocks are 12s apart and start 6s after L2's genesis, so the
+		// baseline and request blocks map to the L1 blocks before them.
+		l1 := newFakeChain(100)
+		l1.blockTime = func(n uint64) uint64 { return testGenesisTime + 6 + 12*n }
+		var reads []uint64
+		l1.callFn = func(msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+			reads = append(reads, block.Uint64())
+			rate := big.NewInt(1_000_000)
+			if block.Uint64() >= 5 {
+				rate = big.NewInt(1_005_000)
+			}
+			return yieldSourceABI.Methods["stEthPerToken"].Outputs.Pack(rate)
+		}
+		source, err := NewYieldSource(h.pool.YieldSource, l1, nil)
+		if err != nil {
+			t.Fatalf("NewYieldSource failed: %v", err)
+		}
+		tw.yieldSources[h.pool.Id] = source
+
+		ref, err := tw.locateTask(ctx, l2, h.pool, h.data)
+		if err != nil {
+			t.Fatalf("locateTask failed: %v", err)
+		}
+		if err := tw.checkYield(ctx, l1, l2, h.pool, h.data, ref); err != nil {
+			t.Fatalf("checkYield failed: %v", err)
+		}
+		// L2 block 20 is at +40s, L1 block 2 at +30s; L2 block 50 is at
+		// +100s, L1 block 7 at +90s.
+		if len(reads) != 2 || reads[0] != 2 || reads[1] != 7 {
+			t.Errorf("read L1 rates at blocks %v, want [2 7]", reads)
+		}
+	})
+
+	t.Run("no source", func(t *testing.T) {
+		h := newHookHistory(t)
+		tw := &TaskWorker{logger: zap.NewNop()}
+		if err := tw.checkYield(ctx, nil, nil, h.pool, h.data, nil); err != nil {
+			t.Fatalf("checkYield = %v, want no-op", err)
+		}
+	})
+}
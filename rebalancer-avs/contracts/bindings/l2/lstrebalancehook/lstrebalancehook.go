@@ -0,0 +1,1777 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package lstrebalancehook
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// HooksPermissions is an auto generated low-level Go binding around an user-defined struct.
+type HooksPermissions struct {
+	BeforeInitialize                bool
+	AfterInitialize                 bool
+	BeforeAddLiquidity              bool
+	AfterAddLiquidity               bool
+	BeforeRemoveLiquidity           bool
+	AfterRemoveLiquidity            bool
+	BeforeSwap                      bool
+	AfterSwap                       bool
+	BeforeDonate                    bool
+	AfterDonate                     bool
+	BeforeSwapReturnDelta           bool
+	AfterSwapReturnDelta            bool
+	AfterAddLiquidityReturnDelta    bool
+	AfterRemoveLiquidityReturnDelta bool
+}
+
+// LSTrebalanceHookLpPosition is an auto generated low-level Go binding around an user-defined struct.
+type LSTrebalanceHookLpPosition struct {
+	Owner     common.Address
+	TickLower *big.Int
+	TickUpper *big.Int
+	Liquidity *big.Int
+}
+
+// ModifyLiquidityParams is an auto generated low-level Go binding around an user-defined struct.
+type ModifyLiquidityParams struct {
+	TickLower      *big.Int
+	TickUpper      *big.Int
+	LiquidityDelta *big.Int
+	Salt           [32]byte
+}
+
+// PoolKey is an auto generated low-level Go binding around an user-defined struct.
+type PoolKey struct {
+	Currency0   common.Address
+	Currency1   common.Address
+	Fee         *big.Int
+	TickSpacing *big.Int
+	Hooks       common.Address
+}
+
+// SwapParams is an auto generated low-level Go binding around an user-defined struct.
+type SwapParams struct {
+	ZeroForOne        bool
+	AmountSpecified   *big.Int
+	SqrtPriceLimitX96 *big.Int
+}
+
+// LSTrebalanceHookMetaData contains all meta data concerning the LSTrebalanceHook contract.
+var LSTrebalanceHookMetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"constructor\",\"inputs\":[{\"name\":\"_poolManager\",\"type\":\"address\",\"internalType\":\"contractIPoolManager\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"CHECK_INTERVAL\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"MIN_YIELD_THRESHOLD\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"afterAddLiquidity\",\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"key\",\"type\":\"tuple\",\"internalType\":\"structPoolKey\",\"components\":[{\"name\":\"currency0\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"currency1\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"fee\",\"type\":\"uint24\",\"internalType\":\"uint24\"},{\"name\":\"tickSpacing\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"hooks\",\"type\":\"address\",\"internalType\":\"contractIHooks\"}]},{\"name\":\"params\",\"type\":\"tuple\",\"internalType\":\"structModifyLiquidityParams\",\"components\":[{\"name\":\"tickLower\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"tickUpper\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"liquidityDelta\",\"type\":\"int256\",\"internalType\":\"int256\"},{\"name\":\"salt\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}]},{\"name\":\"delta\",\"type\":\"int256\",\"internalType\":\"BalanceDelta\"},{\"name\":\"feesAccrued\",\"type\":\"int256\",\"internalType\":\"BalanceDelta\"},{\"name\":\"hookData\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes4\",\"internalType\":\"bytes4\"},{\"name\":\"\",\"type\":\"int256\",\"internalType\":\"BalanceDelta\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"afterDonate\",\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"key\",\"type\":\"tuple\",\"internalType\":\"structPoolKey\",\"components\":[{\"name\":\"currency0\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"currency1\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"fee\",\"type\":\"uint24\",\"internalType\":\"uint24\"},{\"name\":\"tickSpacing\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"hooks\",\"type\":\"address\",\"internalType\":\"contractIHooks\"}]},{\"name\":\"amount0\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"amount1\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"hookData\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes4\",\"internalType\":\"bytes4\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"afterInitialize\",\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"key\",\"type\":\"tuple\",\"internalType\":\"structPoolKey\",\"components\":[{\"name\":\"currency0\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"currency1\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"fee\",\"type\":\"uint24\",\"internalType\":\"uint24\"},{\"name\":\"tickSpacing\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"hooks\",\"type\":\"address\",\"internalType\":\"contractIHooks\"}]},{\"name\":\"sqrtPriceX96\",\"type\":\"uint160\",\"internalType\":\"uint160\"},{\"name\":\"tick\",\"type\":\"int24\",\"internalType\":\"int24\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes4\",\"internalType\":\"bytes4\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"afterRemoveLiquidity\",\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"key\",\"type\":\"tuple\",\"internalType\":\"structPoolKey\",\"components\":[{\"name\":\"currency0\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"currency1\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"fee\",\"type\":\"uint24\",\"internalType\":\"uint24\"},{\"name\":\"tickSpacing\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"hooks\",\"type\":\"address\",\"internalType\":\"contractIHooks\"}]},{\"name\":\"params\",\"type\":\"tuple\",\"internalType\":\"structModifyLiquidityParams\",\"components\":[{\"name\":\"tickLower\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"tickUpper\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"liquidityDelta\",\"type\":\"int256\",\"internalType\":\"int256\"},{\"name\":\"salt\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}]},{\"name\":\"delta\",\"type\":\"int256\",\"internalType\":\"BalanceDelta\"},{\"name\":\"feesAccrued\",\"type\":\"int256\",\"internalType\":\"BalanceDelta\"},{\"name\":\"hookData\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes4\",\"internalType\":\"bytes4\"},{\"name\":\"\",\"type\":\"int256\",\"internalType\":\"BalanceDelta\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"afterSwap\",\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"key\",\"type\":\"tuple\",\"internalType\":\"structPoolKey\",\"components\":[{\"name\":\"currency0\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"currency1\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"fee\",\"type\":\"uint24\",\"internalType\":\"uint24\"},{\"name\":\"tickSpacing\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"hooks\",\"type\":\"address\",\"internalType\":\"contractIHooks\"}]},{\"name\":\"params\",\"type\":\"tuple\",\"internalType\":\"structSwapParams\",\"components\":[{\"name\":\"zeroForOne\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"amountSpecified\",\"type\":\"int256\",\"internalType\":\"int256\"},{\"name\":\"sqrtPriceLimitX96\",\"type\":\"uint160\",\"internalType\":\"uint160\"}]},{\"name\":\"delta\",\"type\":\"int256\",\"internalType\":\"BalanceDelta\"},{\"name\":\"hookData\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes4\",\"internalType\":\"bytes4\"},{\"name\":\"\",\"type\":\"int128\",\"internalType\":\"int128\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"avsServiceManager\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"beforeAddLiquidity\",\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"key\",\"type\":\"tuple\",\"internalType\":\"structPoolKey\",\"components\":[{\"name\":\"currency0\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"currency1\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"fee\",\"type\":\"uint24\",\"internalType\":\"uint24\"},{\"name\":\"tickSpacing\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"hooks\",\"type\":\"address\",\"internalType\":\"contractIHooks\"}]},{\"name\":\"params\",\"type\":\"tuple\",\"internalType\":\"structModifyLiquidityParams\",\"components\":[{\"name\":\"tickLower\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"tickUpper\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"liquidityDelta\",\"type\":\"int256\",\"internalType\":\"int256\"},{\"name\":\"salt\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}]},{\"name\":\"hookData\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes4\",\"internalType\":\"bytes4\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"beforeDonate\",\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"key\",\"type\":\"tuple\",\"internalType\":\"structPoolKey\",\"components\":[{\"name\":\"currency0\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"currency1\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"fee\",\"type\":\"uint24\",\"internalType\":\"uint24\"},{\"name\":\"tickSpacing\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"hooks\",\"type\":\"address\",\"internalType\":\"contractIHooks\"}]},{\"name\":\"amount0\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"amount1\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"hookData\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes4\",\"internalType\":\"bytes4\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"beforeInitialize\",\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"key\",\"type\":\"tuple\",\"internalType\":\"structPoolKey\",\"components\":[{\"name\":\"currency0\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"currency1\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"fee\",\"type\":\"uint24\",\"internalType\":\"uint24\"},{\"name\":\"tickSpacing\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"hooks\",\"type\":\"address\",\"internalType\":\"contractIHooks\"}]},{\"name\":\"sqrtPriceX96\",\"type\":\"uint160\",\"internalType\":\"uint160\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes4\",\"internalType\":\"bytes4\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"beforeRemoveLiquidity\",\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"key\",\"type\":\"tuple\",\"internalType\":\"structPoolKey\",\"components\":[{\"name\":\"currency0\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"currency1\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"fee\",\"type\":\"uint24\",\"internalType\":\"uint24\"},{\"name\":\"tickSpacing\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"hooks\",\"type\":\"address\",\"internalType\":\"contractIHooks\"}]},{\"name\":\"params\",\"type\":\"tuple\",\"internalType\":\"structModifyLiquidityParams\",\"components\":[{\"name\":\"tickLower\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"tickUpper\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"liquidityDelta\",\"type\":\"int256\",\"internalType\":\"int256\"},{\"name\":\"salt\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}]},{\"name\":\"hookData\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes4\",\"internalType\":\"bytes4\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"beforeSwap\",\"inputs\":[{\"name\":\"sender\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"key\",\"type\":\"tuple\",\"internalType\":\"structPoolKey\",\"components\":[{\"name\":\"currency0\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"currency1\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"fee\",\"type\":\"uint24\",\"internalType\":\"uint24\"},{\"name\":\"tickSpacing\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"hooks\",\"type\":\"address\",\"internalType\":\"contractIHooks\"}]},{\"name\":\"params\",\"type\":\"tuple\",\"internalType\":\"structSwapParams\",\"components\":[{\"name\":\"zeroForOne\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"amountSpecified\",\"type\":\"int256\",\"internalType\":\"int256\"},{\"name\":\"sqrtPriceLimitX96\",\"type\":\"uint160\",\"internalType\":\"uint160\"}]},{\"name\":\"hookData\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes4\",\"internalType\":\"bytes4\"},{\"name\":\"\",\"type\":\"int256\",\"internalType\":\"BeforeSwapDelta\"},{\"name\":\"\",\"type\":\"uint24\",\"internalType\":\"uint24\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"cumulativeYieldBps\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"PoolId\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"demoMode\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"executeRebalance\",\"inputs\":[{\"name\":\"key\",\"type\":\"tuple\",\"internalType\":\"structPoolKey\",\"components\":[{\"name\":\"currency0\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"currency1\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"fee\",\"type\":\"uint24\",\"internalType\":\"uint24\"},{\"name\":\"tickSpacing\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"hooks\",\"type\":\"address\",\"internalType\":\"contractIHooks\"}]},{\"name\":\"tickShift\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"taskId\",\"type\":\"uint32\",\"internalType\":\"uint32\"}],\"outputs\":[{\"name\":\"positionsRebalanced\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"getHookPermissions\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"structHooks.Permissions\",\"components\":[{\"name\":\"beforeInitialize\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"afterInitialize\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"beforeAddLiquidity\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"afterAddLiquidity\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"beforeRemoveLiquidity\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"afterRemoveLiquidity\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"beforeSwap\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"afterSwap\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"beforeDonate\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"afterDonate\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"beforeSwapReturnDelta\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"afterSwapReturnDelta\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"afterAddLiquidityReturnDelta\",\"type\":\"bool\",\"internalType\":\"bool\"},{\"name\":\"afterRemoveLiquidityReturnDelta\",\"type\":\"bool\",\"internalType\":\"bool\"}]}],\"stateMutability\":\"pure\"},{\"type\":\"function\",\"name\":\"getPositionCount\",\"inputs\":[{\"name\":\"poolId\",\"type\":\"bytes32\",\"internalType\":\"PoolId\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getPositions\",\"inputs\":[{\"name\":\"poolId\",\"type\":\"bytes32\",\"internalType\":\"PoolId\"}],\"outputs\":[{\"name\":\"\",\"type\":\"tuple[]\",\"internalType\":\"structLSTrebalanceHook.LpPosition[]\",\"components\":[{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"tickLower\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"tickUpper\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"liquidity\",\"type\":\"uint128\",\"internalType\":\"uint128\"}]}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getYieldInfo\",\"inputs\":[{\"name\":\"poolId\",\"type\":\"bytes32\",\"internalType\":\"PoolId\"}],\"outputs\":[{\"name\":\"lastBalance\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"lastCheck\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"cumulativeYield\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"lastCheckTime\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"PoolId\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"lastStETHBalance\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"PoolId\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"manualRebalance\",\"inputs\":[{\"name\":\"key\",\"type\":\"tuple\",\"internalType\":\"structPoolKey\",\"components\":[{\"name\":\"currency0\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"currency1\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"fee\",\"type\":\"uint24\",\"internalType\":\"uint24\"},{\"name\":\"tickSpacing\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"hooks\",\"type\":\"address\",\"internalType\":\"contractIHooks\"}]}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"poolManager\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"contractIPoolManager\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"positionIndex\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"PoolId\"},{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"positions\",\"inputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"PoolId\"},{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"tickLower\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"tickUpper\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"liquidity\",\"type\":\"uint128\",\"internalType\":\"uint128\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"setAvsServiceManager\",\"inputs\":[{\"name\":\"_serviceManager\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setDemoMode\",\"inputs\":[{\"name\":\"_enabled\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"setInitialBalance\",\"inputs\":[{\"name\":\"key\",\"type\":\"tuple\",\"internalType\":\"structPoolKey\",\"components\":[{\"name\":\"currency0\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"currency1\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"fee\",\"type\":\"uint24\",\"internalType\":\"uint24\"},{\"name\":\"tickSpacing\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"hooks\",\"type\":\"address\",\"internalType\":\"contractIHooks\"}]},{\"name\":\"balance\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"simulateYieldAccumulation\",\"inputs\":[{\"name\":\"key\",\"type\":\"tuple\",\"internalType\":\"structPoolKey\",\"components\":[{\"name\":\"currency0\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"currency1\",\"type\":\"address\",\"internalType\":\"Currency\"},{\"name\":\"fee\",\"type\":\"uint24\",\"internalType\":\"uint24\"},{\"name\":\"tickSpacing\",\"type\":\"int24\",\"internalType\":\"int24\"},{\"name\":\"hooks\",\"type\":\"address\",\"internalType\":\"contractIHooks\"}]},{\"name\":\"additionalYieldBps\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"event\",\"name\":\"DebugAfterInit\",\"inputs\":[{\"name\":\"message\",\"type\":\"string\",\"internalType\":\"string\",\"indexed\":false}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"PositionRegistered\",\"inputs\":[{\"name\":\"poolId\",\"type\":\"bytes32\",\"internalType\":\"PoolId\",\"indexed\":true},{\"name\":\"owner\",\"type\":\"address\",\"internalType\":\"address\",\"indexed\":true},{\"name\":\"tickLower\",\"type\":\"int24\",\"internalType\":\"int24\",\"indexed\":false},{\"name\":\"tickUpper\",\"type\":\"int24\",\"internalType\":\"int24\",\"indexed\":false},{\"name\":\"liquidity\",\"type\":\"uint128\",\"internalType\":\"uint128\",\"indexed\":false}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"RebalanceExecuted\",\"inputs\":[{\"name\":\"poolId\",\"type\":\"bytes32\",\"internalType\":\"PoolId\",\"indexed\":true},{\"name\":\"positionsRebalanced\",\"type\":\"uint256\",\"internalType\":\"uint256\",\"indexed\":false},{\"name\":\"tickShift\",\"type\":\"int24\",\"internalType\":\"int24\",\"indexed\":false}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"RebalanceRequested\",\"inputs\":[{\"name\":\"poolId\",\"type\":\"bytes32\",\"internalType\":\"PoolId\",\"indexed\":true},{\"name\":\"yieldAmount\",\"type\":\"uint256\",\"internalType\":\"uint256\",\"indexed\":false},{\"name\":\"yieldBps\",\"type\":\"uint256\",\"internalType\":\"uint256\",\"indexed\":false},{\"name\":\"cumulativeYieldBps\",\"type\":\"uint256\",\"internalType\":\"uint256\",\"indexed\":false},{\"name\":\"positionsToRebalance\",\"type\":\"uint256\",\"internalType\":\"uint256\",\"indexed\":false},{\"name\":\"currentStETHBalance\",\"type\":\"uint256\",\"internalType\":\"uint256\",\"indexed\":false},{\"name\":\"timestamp\",\"type\":\"uint256\",\"internalType\":\"uint256\",\"indexed\":false}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"YieldDetected\",\"inputs\":[{\"name\":\"poolId\",\"type\":\"bytes32\",\"internalType\":\"PoolId\",\"indexed\":true},{\"name\":\"yieldAmount\",\"type\":\"uint256\",\"internalType\":\"uint256\",\"indexed\":false},{\"name\":\"yieldBps\",\"type\":\"uint256\",\"internalType\":\"uint256\",\"indexed\":false},{\"name\":\"cumulativeYieldBps\",\"type\":\"uint256\",\"internalType\":\"uint256\",\"indexed\":false}],\"anonymous\":false},{\"type\":\"error\",\"name\":\"HookNotImplemented\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"NotPoolManager\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"insufficientYield\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"invalidTickShift\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"noPositionsToRebalance\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"onlyAvsOperator\",\"inputs\":[]}]",
+}
+
+// LSTrebalanceHookABI is the input ABI used to generate the binding from.
+// Deprecated: Use LSTrebalanceHookMetaData.ABI instead.
+var LSTrebalanceHookABI = LSTrebalanceHookMetaData.ABI
+
+// LSTrebalanceHook is an auto generated Go binding around an Ethereum contract.
+type LSTrebalanceHook struct {
+	LSTrebalanceHookCaller     // Read-only binding to the contract
+	LSTrebalanceHookTransactor // Write-only binding to the contract
+	LSTrebalanceHookFilterer   // Log filterer for contract events
+}
+
+// LSTrebalanceHookCaller is an auto generated read-only Go binding around an Ethereum contract.
+type LSTrebalanceHookCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LSTrebalanceHookTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type LSTrebalanceHookTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LSTrebalanceHookFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type LSTrebalanceHookFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LSTrebalanceHookSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type LSTrebalanceHookSession struct {
+	Contract     *LSTrebalanceHook // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// LSTrebalanceHookCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type LSTrebalanceHookCallerSession struct {
+	Contract *LSTrebalanceHookCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts           // Call options to use throughout this session
+}
+
+// LSTrebalanceHookTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type LSTrebalanceHookTransactorSession struct {
+	Contract     *LSTrebalanceHookTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts           // Transaction auth options to use throughout this session
+}
+
+// LSTrebalanceHookRaw is an auto generated low-level Go binding around an Ethereum contract.
+type LSTrebalanceHookRaw struct {
+	Contract *LSTrebalanceHook // Generic contract binding to access the raw methods on
+}
+
+// LSTrebalanceHookCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type LSTrebalanceHookCallerRaw struct {
+	Contract *LSTrebalanceHookCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// LSTrebalanceHookTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type LSTrebalanceHookTransactorRaw struct {
+	Contract *LSTrebalanceHookTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewLSTrebalanceHook creates a new instance of LSTrebalanceHook, bound to a specific deployed contract.
+func NewLSTrebalanceHook(address common.Address, backend bind.ContractBackend) (*LSTrebalanceHook, error) {
+	contract, err := bindLSTrebalanceHook(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &LSTrebalanceHook{LSTrebalanceHookCaller: LSTrebalanceHookCaller{contract: contract}, LSTrebalanceHookTransactor: LSTrebalanceHookTransactor{contract: contract}, LSTrebalanceHookFilterer: LSTrebalanceHookFilterer{contract: contract}}, nil
+}
+
+// NewLSTrebalanceHookCaller creates a new read-only instance of LSTrebalanceHook, bound to a specific deployed contract.
+func NewLSTrebalanceHookCaller(address common.Address, caller bind.ContractCaller) (*LSTrebalanceHookCaller, error) {
+	contract, err := bindLSTrebalanceHook(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LSTrebalanceHookCaller{contract: contract}, nil
+}
+
+// NewLSTrebalanceHookTransactor creates a new write-only instance of LSTrebalanceHook, bound to a specific deployed contract.
+func NewLSTrebalanceHookTransactor(address common.Address, transactor bind.ContractTransactor) (*LSTrebalanceHookTransactor, error) {
+	contract, err := bindLSTrebalanceHook(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LSTrebalanceHookTransactor{contract: contract}, nil
+}
+
+// NewLSTrebalanceHookFilterer creates a new log filterer instance of LSTrebalanceHook, bound to a specific deployed contract.
+func NewLSTrebalanceHookFilterer(address common.Address, filterer bind.ContractFilterer) (*LSTrebalanceHookFilterer, error) {
+	contract, err := bindLSTrebalanceHook(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &LSTrebalanceHookFilterer{contract: contract}, nil
+}
+
+// bindLSTrebalanceHook binds a generic wrapper to an already deployed contract.
+func bindLSTrebalanceHook(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := LSTrebalanceHookMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_LSTrebalanceHook *LSTrebalanceHookRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _LSTrebalanceHook.Contract.LSTrebalanceHookCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_LSTrebalanceHook *LSTrebalanceHookRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.LSTrebalanceHookTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_LSTrebalanceHook *LSTrebalanceHookRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.LSTrebalanceHookTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_LSTrebalanceHook *LSTrebalanceHookCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _LSTrebalanceHook.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.contract.Transact(opts, method, params...)
+}
+
+// CHECKINTERVAL is a free data retrieval call binding the contract method 0x6ea33ceb.
+//
+// Solidity: function CHECK_INTERVAL() view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookCaller) CHECKINTERVAL(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _LSTrebalanceHook.contract.Call(opts, &out, "CHECK_INTERVAL")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// CHECKINTERVAL is a free data retrieval call binding the contract method 0x6ea33ceb.
+//
+// Solidity: function CHECK_INTERVAL() view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) CHECKINTERVAL() (*big.Int, error) {
+	return _LSTrebalanceHook.Contract.CHECKINTERVAL(&_LSTrebalanceHook.CallOpts)
+}
+
+// CHECKINTERVAL is a free data retrieval call binding the contract method 0x6ea33ceb.
+//
+// Solidity: function CHECK_INTERVAL() view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookCallerSession) CHECKINTERVAL() (*big.Int, error) {
+	return _LSTrebalanceHook.Contract.CHECKINTERVAL(&_LSTrebalanceHook.CallOpts)
+}
+
+// MINYIELDTHRESHOLD is a free data retrieval call binding the contract method 0xef68596a.
+//
+// Solidity: function MIN_YIELD_THRESHOLD() view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookCaller) MINYIELDTHRESHOLD(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _LSTrebalanceHook.contract.Call(opts, &out, "MIN_YIELD_THRESHOLD")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// MINYIELDTHRESHOLD is a free data retrieval call binding the contract method 0xef68596a.
+//
+// Solidity: function MIN_YIELD_THRESHOLD() view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) MINYIELDTHRESHOLD() (*big.Int, error) {
+	return _LSTrebalanceHook.Contract.MINYIELDTHRESHOLD(&_LSTrebalanceHook.CallOpts)
+}
+
+// MINYIELDTHRESHOLD is a free data retrieval call binding the contract method 0xef68596a.
+//
+// Solidity: function MIN_YIELD_THRESHOLD() view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookCallerSession) MINYIELDTHRESHOLD() (*big.Int, error) {
+	return _LSTrebalanceHook.Contract.MINYIELDTHRESHOLD(&_LSTrebalanceHook.CallOpts)
+}
+
+// AvsServiceManager is a free data retrieval call binding the contract method 0x6d47ecb9.
+//
+// Solidity: function avsServiceManager() view returns(address)
+func (_LSTrebalanceHook *LSTrebalanceHookCaller) AvsServiceManager(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _LSTrebalanceHook.contract.Call(opts, &out, "avsServiceManager")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// AvsServiceManager is a free data retrieval call binding the contract method 0x6d47ecb9.
+//
+// Solidity: function avsServiceManager() view returns(address)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) AvsServiceManager() (common.Address, error) {
+	return _LSTrebalanceHook.Contract.AvsServiceManager(&_LSTrebalanceHook.CallOpts)
+}
+
+// AvsServiceManager is a free data retrieval call binding the contract method 0x6d47ecb9.
+//
+// Solidity: function avsServiceManager() view returns(address)
+func (_LSTrebalanceHook *LSTrebalanceHookCallerSession) AvsServiceManager() (common.Address, error) {
+	return _LSTrebalanceHook.Contract.AvsServiceManager(&_LSTrebalanceHook.CallOpts)
+}
+
+// CumulativeYieldBps is a free data retrieval call binding the contract method 0x8945683b.
+//
+// Solidity: function cumulativeYieldBps(bytes32 ) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookCaller) CumulativeYieldBps(opts *bind.CallOpts, arg0 [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _LSTrebalanceHook.contract.Call(opts, &out, "cumulativeYieldBps", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// CumulativeYieldBps is a free data retrieval call binding the contract method 0x8945683b.
+//
+// Solidity: function cumulativeYieldBps(bytes32 ) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) CumulativeYieldBps(arg0 [32]byte) (*big.Int, error) {
+	return _LSTrebalanceHook.Contract.CumulativeYieldBps(&_LSTrebalanceHook.CallOpts, arg0)
+}
+
+// CumulativeYieldBps is a free data retrieval call binding the contract method 0x8945683b.
+//
+// Solidity: function cumulativeYieldBps(bytes32 ) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookCallerSession) CumulativeYieldBps(arg0 [32]byte) (*big.Int, error) {
+	return _LSTrebalanceHook.Contract.CumulativeYieldBps(&_LSTrebalanceHook.CallOpts, arg0)
+}
+
+// DemoMode is a free data retrieval call binding the contract method 0x44ba06d5.
+//
+// Solidity: function demoMode() view returns(bool)
+func (_LSTrebalanceHook *LSTrebalanceHookCaller) DemoMode(opts *bind.CallOpts) (bool, error) {
+	var out []interface{}
+	err := _LSTrebalanceHook.contract.Call(opts, &out, "demoMode")
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// DemoMode is a free data retrieval call binding the contract method 0x44ba06d5.
+//
+// Solidity: function demoMode() view returns(bool)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) DemoMode() (bool, error) {
+	return _LSTrebalanceHook.Contract.DemoMode(&_LSTrebalanceHook.CallOpts)
+}
+
+// DemoMode is a free data retrieval call binding the contract method 0x44ba06d5.
+//
+// Solidity: function demoMode() view returns(bool)
+func (_LSTrebalanceHook *LSTrebalanceHookCallerSession) DemoMode() (bool, error) {
+	return _LSTrebalanceHook.Contract.DemoMode(&_LSTrebalanceHook.CallOpts)
+}
+
+// GetHookPermissions is a free data retrieval call binding the contract method 0xc4e833ce.
+//
+// Solidity: function getHookPermissions() pure returns((bool,bool,bool,bool,bool,bool,bool,bool,bool,bool,bool,bool,bool,bool))
+func (_LSTrebalanceHook *LSTrebalanceHookCaller) GetHookPermissions(opts *bind.CallOpts) (HooksPermissions, error) {
+	var out []interface{}
+	err := _LSTrebalanceHook.contract.Call(opts, &out, "getHookPermissions")
+
+	if err != nil {
+		return *new(HooksPermissions), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(HooksPermissions)).(*HooksPermissions)
+
+	return out0, err
+
+}
+
+// GetHookPermissions is a free data retrieval call binding the contract method 0xc4e833ce.
+//
+// Solidity: function getHookPermissions() pure returns((bool,bool,bool,bool,bool,bool,bool,bool,bool,bool,bool,bool,bool,bool))
+func (_LSTrebalanceHook *LSTrebalanceHookSession) GetHookPermissions() (HooksPermissions, error) {
+	return _LSTrebalanceHook.Contract.GetHookPermissions(&_LSTrebalanceHook.CallOpts)
+}
+
+// GetHookPermissions is a free data retrieval call binding the contract method 0xc4e833ce.
+//
+// Solidity: function getHookPermissions() pure returns((bool,bool,bool,bool,bool,bool,bool,bool,bool,bool,bool,bool,bool,bool))
+func (_LSTrebalanceHook *LSTrebalanceHookCallerSession) GetHookPermissions() (HooksPermissions, error) {
+	return _LSTrebalanceHook.Contract.GetHookPermissions(&_LSTrebalanceHook.CallOpts)
+}
+
+// GetPositionCount is a free data retrieval call binding the contract method 0x04d0024c.
+//
+// Solidity: function getPositionCount(bytes32 poolId) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookCaller) GetPositionCount(opts *bind.CallOpts, poolId [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _LSTrebalanceHook.contract.Call(opts, &out, "getPositionCount", poolId)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetPositionCount is a free data retrieval call binding the contract method 0x04d0024c.
+//
+// Solidity: function getPositionCount(bytes32 poolId) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) GetPositionCount(poolId [32]byte) (*big.Int, error) {
+	return _LSTrebalanceHook.Contract.GetPositionCount(&_LSTrebalanceHook.CallOpts, poolId)
+}
+
+// GetPositionCount is a free data retrieval call binding the contract method 0x04d0024c.
+//
+// Solidity: function getPositionCount(bytes32 poolId) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookCallerSession) GetPositionCount(poolId [32]byte) (*big.Int, error) {
+	return _LSTrebalanceHook.Contract.GetPositionCount(&_LSTrebalanceHook.CallOpts, poolId)
+}
+
+// GetPositions is a free data retrieval call binding the contract method 0xba3e4fd7.
+//
+// Solidity: function getPositions(bytes32 poolId) view returns((address,int24,int24,uint128)[])
+func (_LSTrebalanceHook *LSTrebalanceHookCaller) GetPositions(opts *bind.CallOpts, poolId [32]byte) ([]LSTrebalanceHookLpPosition, error) {
+	var out []interface{}
+	err := _LSTrebalanceHook.contract.Call(opts, &out, "getPositions", poolId)
+
+	if err != nil {
+		return *new([]LSTrebalanceHookLpPosition), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]LSTrebalanceHookLpPosition)).(*[]LSTrebalanceHookLpPosition)
+
+	return out0, err
+
+}
+
+// GetPositions is a free data retrieval call binding the contract method 0xba3e4fd7.
+//
+// Solidity: function getPositions(bytes32 poolId) view returns((address,int24,int24,uint128)[])
+func (_LSTrebalanceHook *LSTrebalanceHookSession) GetPositions(poolId [32]byte) ([]LSTrebalanceHookLpPosition, error) {
+	return _LSTrebalanceHook.Contract.GetPositions(&_LSTrebalanceHook.CallOpts, poolId)
+}
+
+// GetPositions is a free data retrieval call binding the contract method 0xba3e4fd7.
+//
+// Solidity: function getPositions(bytes32 poolId) view returns((address,int24,int24,uint128)[])
+func (_LSTrebalanceHook *LSTrebalanceHookCallerSession) GetPositions(poolId [32]byte) ([]LSTrebalanceHookLpPosition, error) {
+	return _LSTrebalanceHook.Contract.GetPositions(&_LSTrebalanceHook.CallOpts, poolId)
+}
+
+// GetYieldInfo is a free data retrieval call binding the contract method 0x0fe38114.
+//
+// Solidity: function getYieldInfo(bytes32 poolId) view returns(uint256 lastBalance, uint256 lastCheck, uint256 cumulativeYield)
+func (_LSTrebalanceHook *LSTrebalanceHookCaller) GetYieldInfo(opts *bind.CallOpts, poolId [32]byte) (struct {
+	LastBalance     *big.Int
+	LastCheck       *big.Int
+	CumulativeYield *big.Int
+}, error) {
+	var out []interface{}
+	err := _LSTrebalanceHook.contract.Call(opts, &out, "getYieldInfo", poolId)
+
+	outstruct := new(struct {
+		LastBalance     *big.Int
+		LastCheck       *big.Int
+		CumulativeYield *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.LastBalance = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	outstruct.LastCheck = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.CumulativeYield = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetYieldInfo is a free data retrieval call binding the contract method 0x0fe38114.
+//
+// Solidity: function getYieldInfo(bytes32 poolId) view returns(uint256 lastBalance, uint256 lastCheck, uint256 cumulativeYield)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) GetYieldInfo(poolId [32]byte) (struct {
+	LastBalance     *big.Int
+	LastCheck       *big.Int
+	CumulativeYield *big.Int
+}, error) {
+	return _LSTrebalanceHook.Contract.GetYieldInfo(&_LSTrebalanceHook.CallOpts, poolId)
+}
+
+// GetYieldInfo is a free data retrieval call binding the contract method 0x0fe38114.
+//
+// Solidity: function getYieldInfo(bytes32 poolId) view returns(uint256 lastBalance, uint256 lastCheck, uint256 cumulativeYield)
+func (_LSTrebalanceHook *LSTrebalanceHookCallerSession) GetYieldInfo(poolId [32]byte) (struct {
+	LastBalance     *big.Int
+	LastCheck       *big.Int
+	CumulativeYield *big.Int
+}, error) {
+	return _LSTrebalanceHook.Contract.GetYieldInfo(&_LSTrebalanceHook.CallOpts, poolId)
+}
+
+// LastCheckTime is a free data retrieval call binding the contract method 0x5ac1b2cb.
+//
+// Solidity: function lastCheckTime(bytes32 ) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookCaller) LastCheckTime(opts *bind.CallOpts, arg0 [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _LSTrebalanceHook.contract.Call(opts, &out, "lastCheckTime", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// LastCheckTime is a free data retrieval call binding the contract method 0x5ac1b2cb.
+//
+// Solidity: function lastCheckTime(bytes32 ) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) LastCheckTime(arg0 [32]byte) (*big.Int, error) {
+	return _LSTrebalanceHook.Contract.LastCheckTime(&_LSTrebalanceHook.CallOpts, arg0)
+}
+
+// LastCheckTime is a free data retrieval call binding the contract method 0x5ac1b2cb.
+//
+// Solidity: function lastCheckTime(bytes32 ) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookCallerSession) LastCheckTime(arg0 [32]byte) (*big.Int, error) {
+	return _LSTrebalanceHook.Contract.LastCheckTime(&_LSTrebalanceHook.CallOpts, arg0)
+}
+
+// LastStETHBalance is a free data retrieval call binding the contract method 0xac05f23d.
+//
+// Solidity: function lastStETHBalance(bytes32 ) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookCaller) LastStETHBalance(opts *bind.CallOpts, arg0 [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _LSTrebalanceHook.contract.Call(opts, &out, "lastStETHBalance", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// LastStETHBalance is a free data retrieval call binding the contract method 0xac05f23d.
+//
+// Solidity: function lastStETHBalance(bytes32 ) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) LastStETHBalance(arg0 [32]byte) (*big.Int, error) {
+	return _LSTrebalanceHook.Contract.LastStETHBalance(&_LSTrebalanceHook.CallOpts, arg0)
+}
+
+// LastStETHBalance is a free data retrieval call binding the contract method 0xac05f23d.
+//
+// Solidity: function lastStETHBalance(bytes32 ) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookCallerSession) LastStETHBalance(arg0 [32]byte) (*big.Int, error) {
+	return _LSTrebalanceHook.Contract.LastStETHBalance(&_LSTrebalanceHook.CallOpts, arg0)
+}
+
+// PoolManager is a free data retrieval call binding the contract method 0xdc4c90d3.
+//
+// Solidity: function poolManager() view returns(address)
+func (_LSTrebalanceHook *LSTrebalanceHookCaller) PoolManager(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _LSTrebalanceHook.contract.Call(opts, &out, "poolManager")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// PoolManager is a free data retrieval call binding the contract method 0xdc4c90d3.
+//
+// Solidity: function poolManager() view returns(address)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) PoolManager() (common.Address, error) {
+	return _LSTrebalanceHook.Contract.PoolManager(&_LSTrebalanceHook.CallOpts)
+}
+
+// PoolManager is a free data retrieval call binding the contract method 0xdc4c90d3.
+//
+// Solidity: function poolManager() view returns(address)
+func (_LSTrebalanceHook *LSTrebalanceHookCallerSession) PoolManager() (common.Address, error) {
+	return _LSTrebalanceHook.Contract.PoolManager(&_LSTrebalanceHook.CallOpts)
+}
+
+// PositionIndex is a free data retrieval call binding the contract method 0x256a64cc.
+//
+// Solidity: function positionIndex(bytes32 , address ) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookCaller) PositionIndex(opts *bind.CallOpts, arg0 [32]byte, arg1 common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _LSTrebalanceHook.contract.Call(opts, &out, "positionIndex", arg0, arg1)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// PositionIndex is a free data retrieval call binding the contract method 0x256a64cc.
+//
+// Solidity: function positionIndex(bytes32 , address ) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) PositionIndex(arg0 [32]byte, arg1 common.Address) (*big.Int, error) {
+	return _LSTrebalanceHook.Contract.PositionIndex(&_LSTrebalanceHook.CallOpts, arg0, arg1)
+}
+
+// PositionIndex is a free data retrieval call binding the contract method 0x256a64cc.
+//
+// Solidity: function positionIndex(bytes32 , address ) view returns(uint256)
+func (_LSTrebalanceHook *LSTrebalanceHookCallerSession) PositionIndex(arg0 [32]byte, arg1 common.Address) (*big.Int, error) {
+	return _LSTrebalanceHook.Contract.PositionIndex(&_LSTrebalanceHook.CallOpts, arg0, arg1)
+}
+
+// Positions is a free data retrieval call binding the contract method 0xfe990ed6.
+//
+// Solidity: function positions(bytes32 , uint256 ) view returns(address owner, int24 tickLower, int24 tickUpper, uint128 liquidity)
+func (_LSTrebalanceHook *LSTrebalanceHookCaller) Positions(opts *bind.CallOpts, arg0 [32]byte, arg1 *big.Int) (struct {
+	Owner     common.Address
+	TickLower *big.Int
+	TickUpper *big.Int
+	Liquidity *big.Int
+}, error) {
+	var out []interface{}
+	err := _LSTrebalanceHook.contract.Call(opts, &out, "positions", arg0, arg1)
+
+	outstruct := new(struct {
+		Owner     common.Address
+		TickLower *big.Int
+		TickUpper *big.Int
+		Liquidity *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Owner = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	outstruct.TickLower = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.TickUpper = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.Liquidity = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// Positions is a free data retrieval call binding the contract method 0xfe990ed6.
+//
+// Solidity: function positions(bytes32 , uint256 ) view returns(address owner, int24 tickLower, int24 tickUpper, uint128 liquidity)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) Positions(arg0 [32]byte, arg1 *big.Int) (struct {
+	Owner     common.Address
+	TickLower *big.Int
+	TickUpper *big.Int
+	Liquidity *big.Int
+}, error) {
+	return _LSTrebalanceHook.Contract.Positions(&_LSTrebalanceHook.CallOpts, arg0, arg1)
+}
+
+// Positions is a free data retrieval call binding the contract method 0xfe990ed6.
+//
+// Solidity: function positions(bytes32 , uint256 ) view returns(address owner, int24 tickLower, int24 tickUpper, uint128 liquidity)
+func (_LSTrebalanceHook *LSTrebalanceHookCallerSession) Positions(arg0 [32]byte, arg1 *big.Int) (struct {
+	Owner     common.Address
+	TickLower *big.Int
+	TickUpper *big.Int
+	Liquidity *big.Int
+}, error) {
+	return _LSTrebalanceHook.Contract.Positions(&_LSTrebalanceHook.CallOpts, arg0, arg1)
+}
+
+// AfterAddLiquidity is a paid mutator transaction binding the contract method 0x9f063efc.
+//
+// Solidity: function afterAddLiquidity(address sender, (address,address,uint24,int24,address) key, (int24,int24,int256,bytes32) params, int256 delta, int256 feesAccrued, bytes hookData) returns(bytes4, int256)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) AfterAddLiquidity(opts *bind.TransactOpts, sender common.Address, key PoolKey, params ModifyLiquidityParams, delta *big.Int, feesAccrued *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "afterAddLiquidity", sender, key, params, delta, feesAccrued, hookData)
+}
+
+// AfterAddLiquidity is a paid mutator transaction binding the contract method 0x9f063efc.
+//
+// Solidity: function afterAddLiquidity(address sender, (address,address,uint24,int24,address) key, (int24,int24,int256,bytes32) params, int256 delta, int256 feesAccrued, bytes hookData) returns(bytes4, int256)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) AfterAddLiquidity(sender common.Address, key PoolKey, params ModifyLiquidityParams, delta *big.Int, feesAccrued *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.AfterAddLiquidity(&_LSTrebalanceHook.TransactOpts, sender, key, params, delta, feesAccrued, hookData)
+}
+
+// AfterAddLiquidity is a paid mutator transaction binding the contract method 0x9f063efc.
+//
+// Solidity: function afterAddLiquidity(address sender, (address,address,uint24,int24,address) key, (int24,int24,int256,bytes32) params, int256 delta, int256 feesAccrued, bytes hookData) returns(bytes4, int256)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) AfterAddLiquidity(sender common.Address, key PoolKey, params ModifyLiquidityParams, delta *big.Int, feesAccrued *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.AfterAddLiquidity(&_LSTrebalanceHook.TransactOpts, sender, key, params, delta, feesAccrued, hookData)
+}
+
+// AfterDonate is a paid mutator transaction binding the contract method 0xe1b4af69.
+//
+// Solidity: function afterDonate(address sender, (address,address,uint24,int24,address) key, uint256 amount0, uint256 amount1, bytes hookData) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) AfterDonate(opts *bind.TransactOpts, sender common.Address, key PoolKey, amount0 *big.Int, amount1 *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "afterDonate", sender, key, amount0, amount1, hookData)
+}
+
+// AfterDonate is a paid mutator transaction binding the contract method 0xe1b4af69.
+//
+// Solidity: function afterDonate(address sender, (address,address,uint24,int24,address) key, uint256 amount0, uint256 amount1, bytes hookData) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) AfterDonate(sender common.Address, key PoolKey, amount0 *big.Int, amount1 *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.AfterDonate(&_LSTrebalanceHook.TransactOpts, sender, key, amount0, amount1, hookData)
+}
+
+// AfterDonate is a paid mutator transaction binding the contract method 0xe1b4af69.
+//
+// Solidity: function afterDonate(address sender, (address,address,uint24,int24,address) key, uint256 amount0, uint256 amount1, bytes hookData) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) AfterDonate(sender common.Address, key PoolKey, amount0 *big.Int, amount1 *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.AfterDonate(&_LSTrebalanceHook.TransactOpts, sender, key, amount0, amount1, hookData)
+}
+
+// AfterInitialize is a paid mutator transaction binding the contract method 0x6fe7e6eb.
+//
+// Solidity: function afterInitialize(address sender, (address,address,uint24,int24,address) key, uint160 sqrtPriceX96, int24 tick) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) AfterInitialize(opts *bind.TransactOpts, sender common.Address, key PoolKey, sqrtPriceX96 *big.Int, tick *big.Int) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "afterInitialize", sender, key, sqrtPriceX96, tick)
+}
+
+// AfterInitialize is a paid mutator transaction binding the contract method 0x6fe7e6eb.
+//
+// Solidity: function afterInitialize(address sender, (address,address,uint24,int24,address) key, uint160 sqrtPriceX96, int24 tick) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) AfterInitialize(sender common.Address, key PoolKey, sqrtPriceX96 *big.Int, tick *big.Int) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.AfterInitialize(&_LSTrebalanceHook.TransactOpts, sender, key, sqrtPriceX96, tick)
+}
+
+// AfterInitialize is a paid mutator transaction binding the contract method 0x6fe7e6eb.
+//
+// Solidity: function afterInitialize(address sender, (address,address,uint24,int24,address) key, uint160 sqrtPriceX96, int24 tick) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) AfterInitialize(sender common.Address, key PoolKey, sqrtPriceX96 *big.Int, tick *big.Int) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.AfterInitialize(&_LSTrebalanceHook.TransactOpts, sender, key, sqrtPriceX96, tick)
+}
+
+// AfterRemoveLiquidity is a paid mutator transaction binding the contract method 0x6c2bbe7e.
+//
+// Solidity: function afterRemoveLiquidity(address sender, (address,address,uint24,int24,address) key, (int24,int24,int256,bytes32) params, int256 delta, int256 feesAccrued, bytes hookData) returns(bytes4, int256)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) AfterRemoveLiquidity(opts *bind.TransactOpts, sender common.Address, key PoolKey, params ModifyLiquidityParams, delta *big.Int, feesAccrued *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "afterRemoveLiquidity", sender, key, params, delta, feesAccrued, hookData)
+}
+
+// AfterRemoveLiquidity is a paid mutator transaction binding the contract method 0x6c2bbe7e.
+//
+// Solidity: function afterRemoveLiquidity(address sender, (address,address,uint24,int24,address) key, (int24,int24,int256,bytes32) params, int256 delta, int256 feesAccrued, bytes hookData) returns(bytes4, int256)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) AfterRemoveLiquidity(sender common.Address, key PoolKey, params ModifyLiquidityParams, delta *big.Int, feesAccrued *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.AfterRemoveLiquidity(&_LSTrebalanceHook.TransactOpts, sender, key, params, delta, feesAccrued, hookData)
+}
+
+// AfterRemoveLiquidity is a paid mutator transaction binding the contract method 0x6c2bbe7e.
+//
+// Solidity: function afterRemoveLiquidity(address sender, (address,address,uint24,int24,address) key, (int24,int24,int256,bytes32) params, int256 delta, int256 feesAccrued, bytes hookData) returns(bytes4, int256)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) AfterRemoveLiquidity(sender common.Address, key PoolKey, params ModifyLiquidityParams, delta *big.Int, feesAccrued *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.AfterRemoveLiquidity(&_LSTrebalanceHook.TransactOpts, sender, key, params, delta, feesAccrued, hookData)
+}
+
+// AfterSwap is a paid mutator transaction binding the contract method 0xb47b2fb1.
+//
+// Solidity: function afterSwap(address sender, (address,address,uint24,int24,address) key, (bool,int256,uint160) params, int256 delta, bytes hookData) returns(bytes4, int128)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) AfterSwap(opts *bind.TransactOpts, sender common.Address, key PoolKey, params SwapParams, delta *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "afterSwap", sender, key, params, delta, hookData)
+}
+
+// AfterSwap is a paid mutator transaction binding the contract method 0xb47b2fb1.
+//
+// Solidity: function afterSwap(address sender, (address,address,uint24,int24,address) key, (bool,int256,uint160) params, int256 delta, bytes hookData) returns(bytes4, int128)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) AfterSwap(sender common.Address, key PoolKey, params SwapParams, delta *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.AfterSwap(&_LSTrebalanceHook.TransactOpts, sender, key, params, delta, hookData)
+}
+
+// AfterSwap is a paid mutator transaction binding the contract method 0xb47b2fb1.
+//
+// Solidity: function afterSwap(address sender, (address,address,uint24,int24,address) key, (bool,int256,uint160) params, int256 delta, bytes hookData) returns(bytes4, int128)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) AfterSwap(sender common.Address, key PoolKey, params SwapParams, delta *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.AfterSwap(&_LSTrebalanceHook.TransactOpts, sender, key, params, delta, hookData)
+}
+
+// BeforeAddLiquidity is a paid mutator transaction binding the contract method 0x259982e5.
+//
+// Solidity: function beforeAddLiquidity(address sender, (address,address,uint24,int24,address) key, (int24,int24,int256,bytes32) params, bytes hookData) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) BeforeAddLiquidity(opts *bind.TransactOpts, sender common.Address, key PoolKey, params ModifyLiquidityParams, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "beforeAddLiquidity", sender, key, params, hookData)
+}
+
+// BeforeAddLiquidity is a paid mutator transaction binding the contract method 0x259982e5.
+//
+// Solidity: function beforeAddLiquidity(address sender, (address,address,uint24,int24,address) key, (int24,int24,int256,bytes32) params, bytes hookData) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) BeforeAddLiquidity(sender common.Address, key PoolKey, params ModifyLiquidityParams, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.BeforeAddLiquidity(&_LSTrebalanceHook.TransactOpts, sender, key, params, hookData)
+}
+
+// BeforeAddLiquidity is a paid mutator transaction binding the contract method 0x259982e5.
+//
+// Solidity: function beforeAddLiquidity(address sender, (address,address,uint24,int24,address) key, (int24,int24,int256,bytes32) params, bytes hookData) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) BeforeAddLiquidity(sender common.Address, key PoolKey, params ModifyLiquidityParams, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.BeforeAddLiquidity(&_LSTrebalanceHook.TransactOpts, sender, key, params, hookData)
+}
+
+// BeforeDonate is a paid mutator transaction binding the contract method 0xb6a8b0fa.
+//
+// Solidity: function beforeDonate(address sender, (address,address,uint24,int24,address) key, uint256 amount0, uint256 amount1, bytes hookData) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) BeforeDonate(opts *bind.TransactOpts, sender common.Address, key PoolKey, amount0 *big.Int, amount1 *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "beforeDonate", sender, key, amount0, amount1, hookData)
+}
+
+// BeforeDonate is a paid mutator transaction binding the contract method 0xb6a8b0fa.
+//
+// Solidity: function beforeDonate(address sender, (address,address,uint24,int24,address) key, uint256 amount0, uint256 amount1, bytes hookData) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) BeforeDonate(sender common.Address, key PoolKey, amount0 *big.Int, amount1 *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.BeforeDonate(&_LSTrebalanceHook.TransactOpts, sender, key, amount0, amount1, hookData)
+}
+
+// BeforeDonate is a paid mutator transaction binding the contract method 0xb6a8b0fa.
+//
+// Solidity: function beforeDonate(address sender, (address,address,uint24,int24,address) key, uint256 amount0, uint256 amount1, bytes hookData) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) BeforeDonate(sender common.Address, key PoolKey, amount0 *big.Int, amount1 *big.Int, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.BeforeDonate(&_LSTrebalanceHook.TransactOpts, sender, key, amount0, amount1, hookData)
+}
+
+// BeforeInitialize is a paid mutator transaction binding the contract method 0xdc98354e.
+//
+// Solidity: function beforeInitialize(address sender, (address,address,uint24,int24,address) key, uint160 sqrtPriceX96) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) BeforeInitialize(opts *bind.TransactOpts, sender common.Address, key PoolKey, sqrtPriceX96 *big.Int) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "beforeInitialize", sender, key, sqrtPriceX96)
+}
+
+// BeforeInitialize is a paid mutator transaction binding the contract method 0xdc98354e.
+//
+// Solidity: function beforeInitialize(address sender, (address,address,uint24,int24,address) key, uint160 sqrtPriceX96) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) BeforeInitialize(sender common.Address, key PoolKey, sqrtPriceX96 *big.Int) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.BeforeInitialize(&_LSTrebalanceHook.TransactOpts, sender, key, sqrtPriceX96)
+}
+
+// BeforeInitialize is a paid mutator transaction binding the contract method 0xdc98354e.
+//
+// Solidity: function beforeInitialize(address sender, (address,address,uint24,int24,address) key, uint160 sqrtPriceX96) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) BeforeInitialize(sender common.Address, key PoolKey, sqrtPriceX96 *big.Int) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.BeforeInitialize(&_LSTrebalanceHook.TransactOpts, sender, key, sqrtPriceX96)
+}
+
+// BeforeRemoveLiquidity is a paid mutator transaction binding the contract method 0x21d0ee70.
+//
+// Solidity: function beforeRemoveLiquidity(address sender, (address,address,uint24,int24,address) key, (int24,int24,int256,bytes32) params, bytes hookData) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) BeforeRemoveLiquidity(opts *bind.TransactOpts, sender common.Address, key PoolKey, params ModifyLiquidityParams, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "beforeRemoveLiquidity", sender, key, params, hookData)
+}
+
+// BeforeRemoveLiquidity is a paid mutator transaction binding the contract method 0x21d0ee70.
+//
+// Solidity: function beforeRemoveLiquidity(address sender, (address,address,uint24,int24,address) key, (int24,int24,int256,bytes32) params, bytes hookData) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) BeforeRemoveLiquidity(sender common.Address, key PoolKey, params ModifyLiquidityParams, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.BeforeRemoveLiquidity(&_LSTrebalanceHook.TransactOpts, sender, key, params, hookData)
+}
+
+// BeforeRemoveLiquidity is a paid mutator transaction binding the contract method 0x21d0ee70.
+//
+// Solidity: function beforeRemoveLiquidity(address sender, (address,address,uint24,int24,address) key, (int24,int24,int256,bytes32) params, bytes hookData) returns(bytes4)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) BeforeRemoveLiquidity(sender common.Address, key PoolKey, params ModifyLiquidityParams, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.BeforeRemoveLiquidity(&_LSTrebalanceHook.TransactOpts, sender, key, params, hookData)
+}
+
+// BeforeSwap is a paid mutator transaction binding the contract method 0x575e24b4.
+//
+// Solidity: function beforeSwap(address sender, (address,address,uint24,int24,address) key, (bool,int256,uint160) params, bytes hookData) returns(bytes4, int256, uint24)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) BeforeSwap(opts *bind.TransactOpts, sender common.Address, key PoolKey, params SwapParams, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "beforeSwap", sender, key, params, hookData)
+}
+
+// BeforeSwap is a paid mutator transaction binding the contract method 0x575e24b4.
+//
+// Solidity: function beforeSwap(address sender, (address,address,uint24,int24,address) key, (bool,int256,uint160) params, bytes hookData) returns(bytes4, int256, uint24)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) BeforeSwap(sender common.Address, key PoolKey, params SwapParams, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.BeforeSwap(&_LSTrebalanceHook.TransactOpts, sender, key, params, hookData)
+}
+
+// BeforeSwap is a paid mutator transaction binding the contract method 0x575e24b4.
+//
+// Solidity: function beforeSwap(address sender, (address,address,uint24,int24,address) key, (bool,int256,uint160) params, bytes hookData) returns(bytes4, int256, uint24)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) BeforeSwap(sender common.Address, key PoolKey, params SwapParams, hookData []byte) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.BeforeSwap(&_LSTrebalanceHook.TransactOpts, sender, key, params, hookData)
+}
+
+// ExecuteRebalance is a paid mutator transaction binding the contract method 0xb7e3f864.
+//
+// Solidity: function executeRebalance((address,address,uint24,int24,address) key, int24 tickShift, uint32 taskId) returns(uint256 positionsRebalanced)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) ExecuteRebalance(opts *bind.TransactOpts, key PoolKey, tickShift *big.Int, taskId uint32) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "executeRebalance", key, tickShift, taskId)
+}
+
+// ExecuteRebalance is a paid mutator transaction binding the contract method 0xb7e3f864.
+//
+// Solidity: function executeRebalance((address,address,uint24,int24,address) key, int24 tickShift, uint32 taskId) returns(uint256 positionsRebalanced)
+func (_LSTrebalanceHook *LSTrebalanceHookSession) ExecuteRebalance(key PoolKey, tickShift *big.Int, taskId uint32) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.ExecuteRebalance(&_LSTrebalanceHook.TransactOpts, key, tickShift, taskId)
+}
+
+// ExecuteRebalance is a paid mutator transaction binding the contract method 0xb7e3f864.
+//
+// Solidity: function executeRebalance((address,address,uint24,int24,address) key, int24 tickShift, uint32 taskId) returns(uint256 positionsRebalanced)
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) ExecuteRebalance(key PoolKey, tickShift *big.Int, taskId uint32) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.ExecuteRebalance(&_LSTrebalanceHook.TransactOpts, key, tickShift, taskId)
+}
+
+// ManualRebalance is a paid mutator transaction binding the contract method 0x6675dfc0.
+//
+// Solidity: function manualRebalance((address,address,uint24,int24,address) key) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) ManualRebalance(opts *bind.TransactOpts, key PoolKey) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "manualRebalance", key)
+}
+
+// ManualRebalance is a paid mutator transaction binding the contract method 0x6675dfc0.
+//
+// Solidity: function manualRebalance((address,address,uint24,int24,address) key) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookSession) ManualRebalance(key PoolKey) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.ManualRebalance(&_LSTrebalanceHook.TransactOpts, key)
+}
+
+// ManualRebalance is a paid mutator transaction binding the contract method 0x6675dfc0.
+//
+// Solidity: function manualRebalance((address,address,uint24,int24,address) key) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) ManualRebalance(key PoolKey) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.ManualRebalance(&_LSTrebalanceHook.TransactOpts, key)
+}
+
+// SetAvsServiceManager is a paid mutator transaction binding the contract method 0x6f2563e5.
+//
+// Solidity: function setAvsServiceManager(address _serviceManager) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) SetAvsServiceManager(opts *bind.TransactOpts, _serviceManager common.Address) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "setAvsServiceManager", _serviceManager)
+}
+
+// SetAvsServiceManager is a paid mutator transaction binding the contract method 0x6f2563e5.
+//
+// Solidity: function setAvsServiceManager(address _serviceManager) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookSession) SetAvsServiceManager(_serviceManager common.Address) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.SetAvsServiceManager(&_LSTrebalanceHook.TransactOpts, _serviceManager)
+}
+
+// SetAvsServiceManager is a paid mutator transaction binding the contract method 0x6f2563e5.
+//
+// Solidity: function setAvsServiceManager(address _serviceManager) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) SetAvsServiceManager(_serviceManager common.Address) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.SetAvsServiceManager(&_LSTrebalanceHook.TransactOpts, _serviceManager)
+}
+
+// SetDemoMode is a paid mutator transaction binding the contract method 0x7edadd54.
+//
+// Solidity: function setDemoMode(bool _enabled) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) SetDemoMode(opts *bind.TransactOpts, _enabled bool) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "setDemoMode", _enabled)
+}
+
+// SetDemoMode is a paid mutator transaction binding the contract method 0x7edadd54.
+//
+// Solidity: function setDemoMode(bool _enabled) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookSession) SetDemoMode(_enabled bool) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.SetDemoMode(&_LSTrebalanceHook.TransactOpts, _enabled)
+}
+
+// SetDemoMode is a paid mutator transaction binding the contract method 0x7edadd54.
+//
+// Solidity: function setDemoMode(bool _enabled) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) SetDemoMode(_enabled bool) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.SetDemoMode(&_LSTrebalanceHook.TransactOpts, _enabled)
+}
+
+// SetInitialBalance is a paid mutator transaction binding the contract method 0x923604bb.
+//
+// Solidity: function setInitialBalance((address,address,uint24,int24,address) key, uint256 balance) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) SetInitialBalance(opts *bind.TransactOpts, key PoolKey, balance *big.Int) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "setInitialBalance", key, balance)
+}
+
+// SetInitialBalance is a paid mutator transaction binding the contract method 0x923604bb.
+//
+// Solidity: function setInitialBalance((address,address,uint24,int24,address) key, uint256 balance) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookSession) SetInitialBalance(key PoolKey, balance *big.Int) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.SetInitialBalance(&_LSTrebalanceHook.TransactOpts, key, balance)
+}
+
+// SetInitialBalance is a paid mutator transaction binding the contract method 0x923604bb.
+//
+// Solidity: function setInitialBalance((address,address,uint24,int24,address) key, uint256 balance) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) SetInitialBalance(key PoolKey, balance *big.Int) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.SetInitialBalance(&_LSTrebalanceHook.TransactOpts, key, balance)
+}
+
+// SimulateYieldAccumulation is a paid mutator transaction binding the contract method 0xcb2d9db2.
+//
+// Solidity: function simulateYieldAccumulation((address,address,uint24,int24,address) key, uint256 additionalYieldBps) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookTransactor) SimulateYieldAccumulation(opts *bind.TransactOpts, key PoolKey, additionalYieldBps *big.Int) (*types.Transaction, error) {
+	return _LSTrebalanceHook.contract.Transact(opts, "simulateYieldAccumulation", key, additionalYieldBps)
+}
+
+// SimulateYieldAccumulation is a paid mutator transaction binding the contract method 0xcb2d9db2.
+//
+// Solidity: function simulateYieldAccumulation((address,address,uint24,int24,address) key, uint256 additionalYieldBps) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookSession) SimulateYieldAccumulation(key PoolKey, additionalYieldBps *big.Int) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.SimulateYieldAccumulation(&_LSTrebalanceHook.TransactOpts, key, additionalYieldBps)
+}
+
+// SimulateYieldAccumulation is a paid mutator transaction binding the contract method 0xcb2d9db2.
+//
+// Solidity: function simulateYieldAccumulation((address,address,uint24,int24,address) key, uint256 additionalYieldBps) returns()
+func (_LSTrebalanceHook *LSTrebalanceHookTransactorSession) SimulateYieldAccumulation(key PoolKey, additionalYieldBps *big.Int) (*types.Transaction, error) {
+	return _LSTrebalanceHook.Contract.SimulateYieldAccumulation(&_LSTrebalanceHook.TransactOpts, key, additionalYieldBps)
+}
+
+// LSTrebalanceHookDebugAfterInitIterator is returned from FilterDebugAfterInit and is used to iterate over the raw logs and unpacked data for DebugAfterInit events raised by the LSTrebalanceHook contract.
+type LSTrebalanceHookDebugAfterInitIterator struct {
+	Event *LSTrebalanceHookDebugAfterInit // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LSTrebalanceHookDebugAfterInitIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LSTrebalanceHookDebugAfterInit)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LSTrebalanceHookDebugAfterInit)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LSTrebalanceHookDebugAfterInitIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LSTrebalanceHookDebugAfterInitIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LSTrebalanceHookDebugAfterInit represents a DebugAfterInit event raised by the LSTrebalanceHook contract.
+type LSTrebalanceHookDebugAfterInit struct {
+	Message string
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterDebugAfterInit is a free log retrieval operation binding the contract event 0x286147d0d50ca66454210d4eefe29ee136d9ef03b74ac08eb3d6e6acf92f4675.
+//
+// Solidity: event DebugAfterInit(string message)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) FilterDebugAfterInit(opts *bind.FilterOpts) (*LSTrebalanceHookDebugAfterInitIterator, error) {
+
+	logs, sub, err := _LSTrebalanceHook.contract.FilterLogs(opts, "DebugAfterInit")
+	if err != nil {
+		return nil, err
+	}
+	return &LSTrebalanceHookDebugAfterInitIterator{contract: _LSTrebalanceHook.contract, event: "DebugAfterInit", logs: logs, sub: sub}, nil
+}
+
+// WatchDebugAfterInit is a free log subscription operation binding the contract event 0x286147d0d50ca66454210d4eefe29ee136d9ef03b74ac08eb3d6e6acf92f4675.
+//
+// Solidity: event DebugAfterInit(string message)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) WatchDebugAfterInit(opts *bind.WatchOpts, sink chan<- *LSTrebalanceHookDebugAfterInit) (event.Subscription, error) {
+
+	logs, sub, err := _LSTrebalanceHook.contract.WatchLogs(opts, "DebugAfterInit")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LSTrebalanceHookDebugAfterInit)
+				if err := _LSTrebalanceHook.contract.UnpackLog(event, "DebugAfterInit", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseDebugAfterInit is a log parse operation binding the contract event 0x286147d0d50ca66454210d4eefe29ee136d9ef03b74ac08eb3d6e6acf92f4675.
+//
+// Solidity: event DebugAfterInit(string message)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) ParseDebugAfterInit(log types.Log) (*LSTrebalanceHookDebugAfterInit, error) {
+	event := new(LSTrebalanceHookDebugAfterInit)
+	if err := _LSTrebalanceHook.contract.UnpackLog(event, "DebugAfterInit", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LSTrebalanceHookPositionRegisteredIterator is returned from FilterPositionRegistered and is used to iterate over the raw logs and unpacked data for PositionRegistered events raised by the LSTrebalanceHook contract.
+type LSTrebalanceHookPositionRegisteredIterator struct {
+	Event *LSTrebalanceHookPositionRegistered // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LSTrebalanceHookPositionRegisteredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LSTrebalanceHookPositionRegistered)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LSTrebalanceHookPositionRegistered)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LSTrebalanceHookPositionRegisteredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LSTrebalanceHookPositionRegisteredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LSTrebalanceHookPositionRegistered represents a PositionRegistered event raised by the LSTrebalanceHook contract.
+type LSTrebalanceHookPositionRegistered struct {
+	PoolId    [32]byte
+	Owner     common.Address
+	TickLower *big.Int
+	TickUpper *big.Int
+	Liquidity *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterPositionRegistered is a free log retrieval operation binding the contract event 0x5d2cce3f646d2894bba59ba3298dbe488355ea24d6aa7ad21104c73b89e65cf6.
+//
+// Solidity: event PositionRegistered(bytes32 indexed poolId, address indexed owner, int24 tickLower, int24 tickUpper, uint128 liquidity)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) FilterPositionRegistered(opts *bind.FilterOpts, poolId [][32]byte, owner []common.Address) (*LSTrebalanceHookPositionRegisteredIterator, error) {
+
+	var poolIdRule []interface{}
+	for _, poolIdItem := range poolId {
+		poolIdRule = append(poolIdRule, poolIdItem)
+	}
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+
+	logs, sub, err := _LSTrebalanceHook.contract.FilterLogs(opts, "PositionRegistered", poolIdRule, ownerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LSTrebalanceHookPositionRegisteredIterator{contract: _LSTrebalanceHook.contract, event: "PositionRegistered", logs: logs, sub: sub}, nil
+}
+
+// WatchPositionRegistered is a free log subscription operation binding the contract event 0x5d2cce3f646d2894bba59ba3298dbe488355ea24d6aa7ad21104c73b89e65cf6.
+//
+// Solidity: event PositionRegistered(bytes32 indexed poolId, address indexed owner, int24 tickLower, int24 tickUpper, uint128 liquidity)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) WatchPositionRegistered(opts *bind.WatchOpts, sink chan<- *LSTrebalanceHookPositionRegistered, poolId [][32]byte, owner []common.Address) (event.Subscription, error) {
+
+	var poolIdRule []interface{}
+	for _, poolIdItem := range poolId {
+		poolIdRule = append(poolIdRule, poolIdItem)
+	}
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+
+	logs, sub, err := _LSTrebalanceHook.contract.WatchLogs(opts, "PositionRegistered", poolIdRule, ownerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LSTrebalanceHookPositionRegistered)
+				if err := _LSTrebalanceHook.contract.UnpackLog(event, "PositionRegistered", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParsePositionRegistered is a log parse operation binding the contract event 0x5d2cce3f646d2894bba59ba3298dbe488355ea24d6aa7ad21104c73b89e65cf6.
+//
+// Solidity: event PositionRegistered(bytes32 indexed poolId, address indexed owner, int24 tickLower, int24 tickUpper, uint128 liquidity)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) ParsePositionRegistered(log types.Log) (*LSTrebalanceHookPositionRegistered, error) {
+	event := new(LSTrebalanceHookPositionRegistered)
+	if err := _LSTrebalanceHook.contract.UnpackLog(event, "PositionRegistered", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LSTrebalanceHookRebalanceExecutedIterator is returned from FilterRebalanceExecuted and is used to iterate over the raw logs and unpacked data for RebalanceExecuted events raised by the LSTrebalanceHook contract.
+type LSTrebalanceHookRebalanceExecutedIterator struct {
+	Event *LSTrebalanceHookRebalanceExecuted // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LSTrebalanceHookRebalanceExecutedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LSTrebalanceHookRebalanceExecuted)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LSTrebalanceHookRebalanceExecuted)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LSTrebalanceHookRebalanceExecutedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LSTrebalanceHookRebalanceExecutedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LSTrebalanceHookRebalanceExecuted represents a RebalanceExecuted event raised by the LSTrebalanceHook contract.
+type LSTrebalanceHookRebalanceExecuted struct {
+	PoolId              [32]byte
+	PositionsRebalanced *big.Int
+	TickShift           *big.Int
+	Raw                 types.Log // Blockchain specific contextual infos
+}
+
+// FilterRebalanceExecuted is a free log retrieval operation binding the contract event 0x72e31c3208554200d948822003db4ac142d3b177ad0cbc95e8d8e94bf3f178c8.
+//
+// Solidity: event RebalanceExecuted(bytes32 indexed poolId, uint256 positionsRebalanced, int24 tickShift)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) FilterRebalanceExecuted(opts *bind.FilterOpts, poolId [][32]byte) (*LSTrebalanceHookRebalanceExecutedIterator, error) {
+
+	var poolIdRule []interface{}
+	for _, poolIdItem := range poolId {
+		poolIdRule = append(poolIdRule, poolIdItem)
+	}
+
+	logs, sub, err := _LSTrebalanceHook.contract.FilterLogs(opts, "RebalanceExecuted", poolIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LSTrebalanceHookRebalanceExecutedIterator{contract: _LSTrebalanceHook.contract, event: "RebalanceExecuted", logs: logs, sub: sub}, nil
+}
+
+// WatchRebalanceExecuted is a free log subscription operation binding the contract event 0x72e31c3208554200d948822003db4ac142d3b177ad0cbc95e8d8e94bf3f178c8.
+//
+// Solidity: event RebalanceExecuted(bytes32 indexed poolId, uint256 positionsRebalanced, int24 tickShift)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) WatchRebalanceExecuted(opts *bind.WatchOpts, sink chan<- *LSTrebalanceHookRebalanceExecuted, poolId [][32]byte) (event.Subscription, error) {
+
+	var poolIdRule []interface{}
+	for _, poolIdItem := range poolId {
+		poolIdRule = append(poolIdRule, poolIdItem)
+	}
+
+	logs, sub, err := _LSTrebalanceHook.contract.WatchLogs(opts, "RebalanceExecuted", poolIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LSTrebalanceHookRebalanceExecuted)
+				if err := _LSTrebalanceHook.contract.UnpackLog(event, "RebalanceExecuted", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseRebalanceExecuted is a log parse operation binding the contract event 0x72e31c3208554200d948822003db4ac142d3b177ad0cbc95e8d8e94bf3f178c8.
+//
+// Solidity: event RebalanceExecuted(bytes32 indexed poolId, uint256 positionsRebalanced, int24 tickShift)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) ParseRebalanceExecuted(log types.Log) (*LSTrebalanceHookRebalanceExecuted, error) {
+	event := new(LSTrebalanceHookRebalanceExecuted)
+	if err := _LSTrebalanceHook.contract.UnpackLog(event, "RebalanceExecuted", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LSTrebalanceHookRebalanceRequestedIterator is returned from FilterRebalanceRequested and is used to iterate over the raw logs and unpacked data for RebalanceRequested events raised by the LSTrebalanceHook contract.
+type LSTrebalanceHookRebalanceRequestedIterator struct {
+	Event *LSTrebalanceHookRebalanceRequested // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LSTrebalanceHookRebalanceRequestedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LSTrebalanceHookRebalanceRequested)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LSTrebalanceHookRebalanceRequested)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LSTrebalanceHookRebalanceRequestedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LSTrebalanceHookRebalanceRequestedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LSTrebalanceHookRebalanceRequested represents a RebalanceRequested event raised by the LSTrebalanceHook contract.
+type LSTrebalanceHookRebalanceRequested struct {
+	PoolId               [32]byte
+	YieldAmount          *big.Int
+	YieldBps             *big.Int
+	CumulativeYieldBps   *big.Int
+	PositionsToRebalance *big.Int
+	CurrentStETHBalance  *big.Int
+	Timestamp            *big.Int
+	Raw                  types.Log // Blockchain specific contextual infos
+}
+
+// FilterRebalanceRequested is a free log retrieval operation binding the contract event 0xfada0cf8ae9210cd607e6f6ded3b7e4efa2caa276c41876c0da4815dbedf3d36.
+//
+// Solidity: event RebalanceRequested(bytes32 indexed poolId, uint256 yieldAmount, uint256 yieldBps, uint256 cumulativeYieldBps, uint256 positionsToRebalance, uint256 currentStETHBalance, uint256 timestamp)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) FilterRebalanceRequested(opts *bind.FilterOpts, poolId [][32]byte) (*LSTrebalanceHookRebalanceRequestedIterator, error) {
+
+	var poolIdRule []interface{}
+	for _, poolIdItem := range poolId {
+		poolIdRule = append(poolIdRule, poolIdItem)
+	}
+
+	logs, sub, err := _LSTrebalanceHook.contract.FilterLogs(opts, "RebalanceRequested", poolIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LSTrebalanceHookRebalanceRequestedIterator{contract: _LSTrebalanceHook.contract, event: "RebalanceRequested", logs: logs, sub: sub}, nil
+}
+
+// WatchRebalanceRequested is a free log subscription operation binding the contract event 0xfada0cf8ae9210cd607e6f6ded3b7e4efa2caa276c41876c0da4815dbedf3d36.
+//
+// Solidity: event RebalanceRequested(bytes32 indexed poolId, uint256 yieldAmount, uint256 yieldBps, uint256 cumulativeYieldBps, uint256 positionsToRebalance, uint256 currentStETHBalance, uint256 timestamp)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) WatchRebalanceRequested(opts *bind.WatchOpts, sink chan<- *LSTrebalanceHookRebalanceRequested, poolId [][32]byte) (event.Subscription, error) {
+
+	var poolIdRule []interface{}
+	for _, poolIdItem := range poolId {
+		poolIdRule = append(poolIdRule, poolIdItem)
+	}
+
+	logs, sub, err := _LSTrebalanceHook.contract.WatchLogs(opts, "RebalanceRequested", poolIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LSTrebalanceHookRebalanceRequested)
+				if err := _LSTrebalanceHook.contract.UnpackLog(event, "RebalanceRequested", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseRebalanceRequested is a log parse operation binding the contract event 0xfada0cf8ae9210cd607e6f6ded3b7e4efa2caa276c41876c0da4815dbedf3d36.
+//
+// Solidity: event RebalanceRequested(bytes32 indexed poolId, uint256 yieldAmount, uint256 yieldBps, uint256 cumulativeYieldBps, uint256 positionsToRebalance, uint256 currentStETHBalance, uint256 timestamp)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) ParseRebalanceRequested(log types.Log) (*LSTrebalanceHookRebalanceRequested, error) {
+	event := new(LSTrebalanceHookRebalanceRequested)
+	if err := _LSTrebalanceHook.contract.UnpackLog(event, "RebalanceRequested", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// LSTrebalanceHookYieldDetectedIterator is returned from FilterYieldDetected and is used to iterate over the raw logs and unpacked data for YieldDetected events raised by the LSTrebalanceHook contract.
+type LSTrebalanceHookYieldDetectedIterator struct {
+	Event *LSTrebalanceHookYieldDetected // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LSTrebalanceHookYieldDetectedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LSTrebalanceHookYieldDetected)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LSTrebalanceHookYieldDetected)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LSTrebalanceHookYieldDetectedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LSTrebalanceHookYieldDetectedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LSTrebalanceHookYieldDetected represents a YieldDetected event raised by the LSTrebalanceHook contract.
+type LSTrebalanceHookYieldDetected struct {
+	PoolId             [32]byte
+	YieldAmount        *big.Int
+	YieldBps           *big.Int
+	CumulativeYieldBps *big.Int
+	Raw                types.Log // Blockchain specific contextual infos
+}
+
+// FilterYieldDetected is a free log retrieval operation binding the contract event 0x2d0de87c0392a7d555a403e7e1fd3f2ea308e44dd81c7be466e31a69337bc439.
+//
+// Solidity: event YieldDetected(bytes32 indexed poolId, uint256 yieldAmount, uint256 yieldBps, uint256 cumulativeYieldBps)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) FilterYieldDetected(opts *bind.FilterOpts, poolId [][32]byte) (*LSTrebalanceHookYieldDetectedIterator, error) {
+
+	var poolIdRule []interface{}
+	for _, poolIdItem := range poolId {
+		poolIdRule = append(poolIdRule, poolIdItem)
+	}
+
+	logs, sub, err := _LSTrebalanceHook.contract.FilterLogs(opts, "YieldDetected", poolIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LSTrebalanceHookYieldDetectedIterator{contract: _LSTrebalanceHook.contract, event: "YieldDetected", logs: logs, sub: sub}, nil
+}
+
+// WatchYieldDetected is a free log subscription operation binding the contract event 0x2d0de87c0392a7d555a403e7e1fd3f2ea308e44dd81c7be466e31a69337bc439.
+//
+// Solidity: event YieldDetected(bytes32 indexed poolId, uint256 yieldAmount, uint256 yieldBps, uint256 cumulativeYieldBps)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) WatchYieldDetected(opts *bind.WatchOpts, sink chan<- *LSTrebalanceHookYieldDetected, poolId [][32]byte) (event.Subscription, error) {
+
+	var poolIdRule []interface{}
+	for _, poolIdItem := range poolId {
+		poolIdRule = append(poolIdRule, poolIdItem)
+	}
+
+	logs, sub, err := _LSTrebalanceHook.contract.WatchLogs(opts, "YieldDetected", poolIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LSTrebalanceHookYieldDetected)
+				if err := _LSTrebalanceHook.contract.UnpackLog(event, "YieldDetected", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseYieldDetected is a log parse operation binding the contract event 0x2d0de87c0392a7d555a403e7e1fd3f2ea308e44dd81c7be466e31a69337bc439.
+//
+// Solidity: event YieldDetected(bytes32 indexed poolId, uint256 yieldAmount, uint256 yieldBps, uint256 cumulativeYieldBps)
+func (_LSTrebalanceHook *LSTrebalanceHookFilterer) ParseYieldDetected(log types.Log) (*LSTrebalanceHookYieldDetected, error) {
+	event := new(LSTrebalanceHookYieldDetected)
+	if err := _LSTrebalanceHook.contract.UnpackLog(event, "YieldDetected", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
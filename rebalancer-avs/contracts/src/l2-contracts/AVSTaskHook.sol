@@ -5,6 +5,38 @@ import {IAVSTaskHook} from "@eigenlayer-contracts/src/contracts/interfaces/IAVST
 import {ITaskMailboxTypes} from "@eigenlayer-contracts/src/contracts/interfaces/ITaskMailbox.sol";
 
 contract AVSTaskHook is IAVSTaskHook {
+    /// @notice Layout version of the performer's ABI-encoded task result.
+    uint8 public constant RESULT_VERSION = 2;
+
+    /// @notice The planned target range of one hook position. `skip` is why
+    ///         the plan leaves the position in place, empty when it moves.
+    struct PositionPlan {
+        address owner;
+        uint128 liquidity;
+        int24 tickLower;
+        int24 tickUpper;
+        int24 targetTickLower;
+        int24 targetTickUpper;
+        string skip;
+    }
+
+    /// @notice The performer's task result, see rebalancer-avs/pkg/taskresult.
+    struct RebalanceResult {
+        uint8 version;
+        bytes32 poolId;
+        bytes32 taskId;
+        int24 tickShift;
+        uint256 referenceBlockNumber;
+        bytes32 referenceBlockHash;
+        uint256 positionsRebalanced;
+        PositionPlan[] positions;
+    }
+
+    error MalformedResult(uint256 length);
+    error UnsupportedResultVersion(uint256 version);
+    error ResultTaskMismatch(bytes32 taskHash, bytes32 resultTaskId);
+    error ReferenceBlockMismatch(uint256 blockNumber, bytes32 expected, bytes32 actual);
+
     function validatePreTaskCreation(
         address, /*caller*/
         ITaskMailboxTypes.TaskParams memory /*taskParams*/
@@ -20,11 +52,21 @@ contract AVSTaskHook is IAVSTaskHook {
 
     function validatePreTaskResultSubmission(
         address, /*caller*/
-        bytes32, /*taskHash*/
+        bytes32 taskHash,
         bytes memory, /*cert*/
-        bytes memory /*result*/
+        bytes memory result
     ) external view {
-        //TODO: Implement
+        RebalanceResult memory decoded = decodeResult(result);
+        if (decoded.taskId != taskHash) {
+            revert ResultTaskMismatch(taskHash, decoded.taskId);
+        }
+
+        // blockhash is only available for the last 256 blocks; older references
+        // cannot be checked on-chain and are left to off-chain verifiers.
+        bytes32 actual = blockhash(decoded.referenceBlockNumber);
+        if (actual != bytes32(0) && actual != decoded.referenceBlockHash) {
+            revert ReferenceBlockMismatch(decoded.referenceBlockNumber, decoded.referenceBlockHash, actual);
+        }
     }
 
     function handlePostTaskResultSubmission(
@@ -39,4 +81,27 @@ contract AVSTaskHook is IAVSTaskHook {
     ) external view returns (uint96) {
         //TODO: Implement
     }
+
+    /// @notice Decodes a versioned task result, reverting on unknown versions
+    ///         or a length too short for the version's layout. The fields
+    ///         are encoded one after the other, not as a single tuple, so
+    ///         the version is always the first word.
+    function decodeResult(
+        bytes memory result
+    ) public pure returns (RebalanceResult memory decoded) {
+        if (result.length < 32) {
+            revert MalformedResult(result.length);
+        }
+        uint256 version = abi.decode(result, (uint256));
+        if (version != RESULT_VERSION) {
+            revert UnsupportedResultVersion(version);
+        }
+        // Eight head words and the length of the positions array.
+        if (result.length < 9 * 32) {
+            revert MalformedResult(result.length);
+        }
+        // abi.encode(RebalanceResult) is the fields' encoding behind the
+        // offset of the dynamic tuple, so prepend that offset to decode.
+        decoded = abi.decode(bytes.concat(bytes32(uint256(32)), result), (RebalanceResult));
+    }
 }
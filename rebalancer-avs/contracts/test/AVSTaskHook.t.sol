@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: BUSL-1.1
+pragma solidity ^0.8.27;
+
+import {Test} from "forge-std/Test.sol";
+
+import {AVSTaskHook} from "@project/l2-contracts/AVSTaskHook.sol";
+
+contract AVSTaskHookTest is Test {
+    AVSTaskHook public hook;
+
+    bytes32 constant POOL_ID = bytes32(uint256(0xaabb));
+    bytes32 constant TASK_HASH = keccak256("task");
+
+    function setUp() public {
+        hook = new AVSTaskHook();
+        vm.roll(100);
+        vm.setBlockhash(99, keccak256("block 99"));
+    }
+
+    function encodeResult(uint8 version, bytes32 taskId, int24 tickShift, uint256 blockNumber, bytes32 blockHash)
+        internal
+        pure
+        returns (bytes memory)
+    {
+        AVSTaskHook.PositionPlan[] memory positions = new AVSTaskHook.PositionPlan[](2);
+        positions[0] = AVSTaskHook.PositionPlan(address(0xA11CE), 1e18, -120, 120, -180, 60, "");
+        positions[1] = AVSTaskHook.PositionPlan(address(0xB0B), 0, -60, 60, -60, 60, "empty");
+        return abi.encode(version, POOL_ID, taskId, tickShift, blockNumber, blockHash, uint256(1), positions);
+    }
+
+    function testDecodeResult() view public {
+        AVSTaskHook.RebalanceResult memory decoded =
+            hook.decodeResult(encodeResult(2, TASK_HASH, -60, 42, bytes32(uint256(1))));
+
+        assertEq(decoded.version, 2);
+        assertEq(decoded.poolId, POOL_ID);
+        assertEq(decoded.taskId, TASK_HASH);
+        assertEq(decoded.tickShift, -60);
+        assertEq(decoded.referenceBlockNumber, 42);
+        assertEq(decoded.positionsRebalanced, 1);
+        assertEq(decoded.positions.length, 2);
+        assertEq(decoded.positions[0].owner, address(0xA11CE));
+        assertEq(decoded.positions[0].targetTickLower, -180);
+        assertEq(decoded.positions[1].skip, "empty");
+    }
+
+    function testValidatesResult() view public {
+        bytes memory result = encodeResult(2, TASK_HASH, 60, 99, blockhash(99));
+        hook.validatePreTaskResultSubmission(address(this), TASK_HASH, "", result);
+    }
+
+    function testRejectsUnknownVersion() public {
+        vm.expectRevert(abi.encodeWithSelector(AVSTaskHook.UnsupportedResultVersion.selector, 1));
+        hook.decodeResult(encodeResult(1, TASK_HASH, 60, 99, bytes32(0)));
+    }
+
+    function testRejectsDecimalResult() public {
+        vm.expectRevert(abi.encodeWithSelector(AVSTaskHook.MalformedResult.selector, 2));
+        hook.decodeResult(bytes("60"));
+    }
+
+    function testRejectsOtherTask() public {
+        bytes memory result = encodeResult(2, keccak256("other"), 60, 99, blockhash(99));
+        vm.expectRevert(
+            abi.encodeWithSelector(AVSTaskHook.ResultTaskMismatch.selector, TASK_HASH, keccak256("other"))
+        );
+        hook.validatePreTaskResultSubmission(address(this), TASK_HASH, "", result);
+    }
+
+    function testRejectsWrongReferenceBlock() public {
+        bytes memory result = encodeResult(2, TASK_HASH, 60, 99, bytes32(uint256(1)));
+        vm.expectRevert(
+            abi.encodeWithSelector(AVSTaskHook.ReferenceBlockMismatch.selector, 99, bytes32(uint256(1)), blockhash(99))
+        );
+        hook.validatePreTaskResultSubmission(address(this), TASK_HASH, "", result);
+    }
+}
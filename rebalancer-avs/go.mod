@@ -6,7 +6,12 @@ require (
 	github.com/Layr-Labs/hourglass-monorepo/ponos v0.0.0-20251016020310-11f155493c33
 	github.com/Layr-Labs/protocol-apis v1.17.0
 	github.com/ethereum/go-ethereum v1.15.11
+	github.com/google/uuid v1.6.0
+	github.com/miekg/pkcs11 v1.1.2
+	go.etcd.io/bbolt v1.4.3
 	go.uber.org/zap v1.27.0
+	golang.org/x/term v0.32.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -18,7 +23,6 @@ require (
 	github.com/go-ole/go-ole v1.3.0 // indirect
 	github.com/go-stack/stack v1.8.1 // indirect
 	github.com/golang/protobuf v1.5.4 // indirect
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/gorilla/websocket v1.5.0 // indirect
 	github.com/grpc-ecosystem/go-grpc-middleware v1.4.0 // indirect
 	github.com/holiman/uint256 v1.3.2 // indirect
@@ -0,0 +1,79 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// oidSecp256k1 is the CKA_EC_PARAMS named curve of secp256k1 keys.
+	oidSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// parseECDSASignature returns r and s of an ECDSA signature, either a DER
+// SEQUENCE { r INTEGER, s INTEGER } or the 64-byte r || s that PKCS#11's
+// CKM_ECDSA produces.
+func parseECDSASignature(sig []byte) (r, s *big.Int, err error) {
+	var der struct{ R, S *big.Int }
+	if rest, err := asn1.Unmarshal(sig, &der); err == nil && len(rest) == 0 {
+		r, s = der.R, der.S
+	} else if len(sig) == 64 {
+		r, s = new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+	} else {
+		return nil, nil, fmt.Errorf("signature is neither DER nor 64-byte r || s (%d bytes)", len(sig))
+	}
+	if r.Sign() <= 0 || r.Cmp(secp256k1N) >= 0 || s.Sign() <= 0 || s.Cmp(secp256k1N) >= 0 {
+		return nil, nil, errors.New("signature values out of range")
+	}
+	return r, s, nil
+}
+
+// ethereumSignature turns an ECDSA signature of hash by pub into Ethereum's
+// 65-byte [R || S || V]. S is normalized to the lower half of the curve order,
+// which is all Ethereum accepts (EIP-2), and V is the recovery id that
+// recovers pub.
+func ethereumSignature(hash common.Hash, r, s *big.Int, pub *ecdsa.PublicKey) ([]byte, error) {
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s = new(big.Int).Sub(secp256k1N, s)
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+
+	want := crypto.PubkeyToAddress(*pub)
+	for v := byte(0); v < 2; v++ {
+		sig[crypto.RecoveryIDOffset] = v
+		recovered, err := crypto.SigToPub(hash[:], sig)
+		if err == nil && crypto.PubkeyToAddress(*recovered) == want {
+			return sig, nil
+		}
+	}
+	return nil, ErrWrongSigner
+}
+
+// parseECPoint decodes a CKA_EC_POINT: an uncompressed secp256k1 point,
+// usually wrapped in a DER OCTET STRING.
+func parseECPoint(point []byte) (*ecdsa.PublicKey, error) {
+	// A bare point starts with 0x04 as well, the OCTET STRING tag, so only
+	// unwrap what is not already a point.
+	if len(point) != 65 {
+		var inner []byte
+		if rest, err := asn1.Unmarshal(point, &inner); err == nil && len(rest) == 0 {
+			point = inner
+		}
+	}
+	pub, err := crypto.UnmarshalPubkey(point)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EC point: %w", err)
+	}
+	return pub, nil
+}
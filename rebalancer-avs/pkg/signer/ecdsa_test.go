@@ -0,0 +1,110 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func Test_EthereumSignatureFromDER(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+
+	for i := 0; i < 16; i++ {
+		hash := crypto.Keccak256Hash([]byte{byte(i)})
+		want, err := crypto.Sign(hash[:], key)
+		if err != nil {
+			t.Fatalf("Sign failed: %v", err)
+		}
+		r := new(big.Int).SetBytes(want[:32])
+		s := new(big.Int).SetBytes(want[32:64])
+		highS := new(big.Int).Sub(secp256k1N, s)
+
+		// An HSM may return either S; both must become the low-S signature
+		// with the right recovery id.
+		for _, tc := range []struct {
+			name string
+			s    *big.Int
+		}{{"low s", s}, {"high s", highS}} {
+			der, err := asn1.Marshal(struct{ R, S *big.Int }{r, tc.s})
+			if err != nil {
+				t.Fatalf("asn1.Marshal failed: %v", err)
+			}
+			gotR, gotS, err := parseECDSASignature(der)
+			if err != nil {
+				t.Fatalf("%s: parseECDSASignature failed: %v", tc.name, err)
+			}
+			got, err := ethereumSignature(hash, gotR, gotS, &key.PublicKey)
+			if err != nil {
+				t.Fatalf("%s: ethereumSignature failed: %v", tc.name, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s: signature = %x, want %x", tc.name, got, want)
+			}
+		}
+
+		// PKCS#11 CKM_ECDSA returns raw r || s.
+		rawR, rawS, err := parseECDSASignature(append(want[:32:32], highS.FillBytes(make([]byte, 32))...))
+		if err != nil {
+			t.Fatalf("raw: parseECDSASignature failed: %v", err)
+		}
+		if got, err := ethereumSignature(hash, rawR, rawS, &key.PublicKey); err != nil || !bytes.Equal(got, want) {
+			t.Errorf("raw: signature = %x, %v, want %x", got, err, want)
+		}
+	}
+}
+
+func Test_EthereumSignatureWrongKey(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	hash := crypto.Keccak256Hash([]byte("rebalance"))
+	sig, _ := crypto.Sign(hash[:], key)
+
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+	if _, err := ethereumSignature(hash, r, s, &other.PublicKey); !errors.Is(err, ErrWrongSigner) {
+		t.Errorf("expected ErrWrongSigner, got %v", err)
+	}
+}
+
+func Test_ParseECDSASignatureInvalid(t *testing.T) {
+	zero, _ := asn1.Marshal(struct{ R, S *big.Int }{big.NewInt(0), big.NewInt(1)})
+	tooBig, _ := asn1.Marshal(struct{ R, S *big.Int }{big.NewInt(1), secp256k1N})
+	for name, sig := range map[string][]byte{
+		"empty":    nil,
+		"short":    make([]byte, 63),
+		"zero r":   zero,
+		"s over n": tooBig,
+	} {
+		if _, _, err := parseECDSASignature(sig); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func Test_ParseECPoint(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	point := crypto.FromECDSAPub(&key.PublicKey)
+	wrapped, err := asn1.Marshal(point)
+	if err != nil {
+		t.Fatalf("asn1.Marshal failed: %v", err)
+	}
+
+	for name, in := range map[string][]byte{"bare": point, "octet string": wrapped} {
+		pub, err := parseECPoint(in)
+		if err != nil {
+			t.Fatalf("%s: parseECPoint failed: %v", name, err)
+		}
+		if crypto.PubkeyToAddress(*pub) != crypto.PubkeyToAddress(key.PublicKey) {
+			t.Errorf("%s: wrong key", name)
+		}
+	}
+	if _, err := parseECPoint(point[:33]); err == nil {
+		t.Error("expected an error for a compressed point")
+	}
+}
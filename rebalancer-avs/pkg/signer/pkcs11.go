@@ -0,0 +1,190 @@
+//go:build cgo
+
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/miekg/pkcs11"
+)
+
+// PKCS11 signs with a secp256k1 key that never leaves a PKCS#11 token, such
+// as an HSM. CKM_ECDSA signs the hash itself; the signature is converted to
+// Ethereum's [R || S || V] with a low S.
+type PKCS11 struct {
+	// mu serializes use of the session, which PKCS#11 does not allow
+	// concurrently.
+	mu      sync.Mutex
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	key     pkcs11.ObjectHandle
+	pub     *ecdsa.PublicKey
+	address common.Address
+}
+
+// NewPKCS11 loads cfg.Module, logs in to the token labelled cfg.TokenLabel
+// and finds the key pair labelled cfg.KeyLabel. Close releases the token.
+func NewPKCS11(cfg PKCS11Config) (*PKCS11, error) {
+	if cfg.Module == "" || cfg.TokenLabel == "" || cfg.KeyLabel == "" {
+		return nil, errors.New("pkcs11: module, token label and key label are required")
+	}
+	p11 := pkcs11.New(cfg.Module)
+	if p11 == nil {
+		return nil, fmt.Errorf("pkcs11: failed to load module %s", cfg.Module)
+	}
+	if err := p11.Initialize(); err != nil {
+		p11.Destroy()
+		return nil, fmt.Errorf("pkcs11: initialize: %w", err)
+	}
+
+	p := &PKCS11{ctx: p11}
+	if err := p.open(cfg); err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *PKCS11) open(cfg PKCS11Config) error {
+	slot, err := p.findSlot(cfg.TokenLabel)
+	if err != nil {
+		return err
+	}
+	if p.session, err = p.ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION); err != nil {
+		return fmt.Errorf("pkcs11: open session: %w", err)
+	}
+	if err := p.ctx.Login(p.session, pkcs11.CKU_USER, cfg.PIN); err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+		// The PIN is not part of the error.
+		return fmt.Errorf("pkcs11: login to token %q: %w", cfg.TokenLabel, err)
+	}
+
+	if p.key, err = p.findObject(pkcs11.CKO_PRIVATE_KEY, cfg.KeyLabel); err != nil {
+		return err
+	}
+	pubKey, err := p.findObject(pkcs11.CKO_PUBLIC_KEY, cfg.KeyLabel)
+	if err != nil {
+		return err
+	}
+	attrs, err := p.ctx.GetAttributeValue(p.session, pubKey, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return fmt.Errorf("pkcs11: read public key %q: %w", cfg.KeyLabel, err)
+	}
+	var curve asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(attrs[0].Value, &curve); err != nil || !curve.Equal(oidSecp256k1) {
+		return fmt.Errorf("pkcs11: key %q is not a secp256k1 key", cfg.KeyLabel)
+	}
+	if p.pub, err = parseECPoint(attrs[1].Value); err != nil {
+		return fmt.Errorf("pkcs11: key %q: %w", cfg.KeyLabel, err)
+	}
+	p.address = crypto.PubkeyToAddress(*p.pub)
+	return nil
+}
+
+func (p *PKCS11) findSlot(label string) (uint, error) {
+	slots, err := p.ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("pkcs11: list slots: %w", err)
+	}
+	for _, slot := range slots {
+		info, err := p.ctx.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+		// Token labels are blank-padded to 32 bytes.
+		if strings.TrimRight(info.Label, " \x00") == label {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("pkcs11: no token labelled %q", label)
+}
+
+func (p *PKCS11) findObject(class uint, label string) (pkcs11.ObjectHandle, error) {
+	if err := p.ctx.FindObjectsInit(p.session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}); err != nil {
+		return 0, fmt.Errorf("pkcs11: find %q: %w", label, err)
+	}
+	objects, _, err := p.ctx.FindObjects(p.session, 2)
+	if finalErr := p.ctx.FindObjectsFinal(p.session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("pkcs11: find %q: %w", label, err)
+	}
+	kind := "private"
+	if class == pkcs11.CKO_PUBLIC_KEY {
+		kind = "public"
+	}
+	switch len(objects) {
+	case 0:
+		return 0, fmt.Errorf("pkcs11: no EC %s key labelled %q", kind, label)
+	case 1:
+		return objects[0], nil
+	default:
+		return 0, fmt.Errorf("pkcs11: several EC %s keys labelled %q", kind, label)
+	}
+}
+
+func (p *PKCS11) Address() common.Address {
+	return p.address
+}
+
+func (p *PKCS11) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	s := types.LatestSignerForChainID(chainID)
+	sig, err := p.SignHash(ctx, s.Hash(tx))
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(s, sig)
+}
+
+func (p *PKCS11) SignHash(_ context.Context, hash common.Hash) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.ctx.SignInit(p.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, p.key); err != nil {
+		return nil, fmt.Errorf("pkcs11: sign init: %w", err)
+	}
+	raw, err := p.ctx.Sign(p.session, hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: sign: %w", err)
+	}
+	r, s, err := parseECDSASignature(raw)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: %w", err)
+	}
+	return ethereumSignature(hash, r, s, p.pub)
+}
+
+// Close logs out and unloads the module.
+func (p *PKCS11) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ctx == nil {
+		return nil
+	}
+	if p.session != 0 {
+		_ = p.ctx.Logout(p.session)
+		_ = p.ctx.CloseSession(p.session)
+	}
+	err := p.ctx.Finalize()
+	p.ctx.Destroy()
+	p.ctx = nil
+	return err
+}
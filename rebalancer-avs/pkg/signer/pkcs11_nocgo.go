@@ -0,0 +1,35 @@
+//go:build !cgo
+
+package signer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrPKCS11Unavailable is returned by NewPKCS11 in binaries built without
+// cgo, which the PKCS#11 bindings need.
+var ErrPKCS11Unavailable = errors.New("PKCS#11 support needs a cgo build (CGO_ENABLED=1)")
+
+// PKCS11 is unavailable without cgo.
+type PKCS11 struct{}
+
+func NewPKCS11(PKCS11Config) (*PKCS11, error) {
+	return nil, ErrPKCS11Unavailable
+}
+
+func (*PKCS11) Address() common.Address { return common.Address{} }
+
+func (*PKCS11) SignTx(context.Context, *types.Transaction, *big.Int) (*types.Transaction, error) {
+	return nil, ErrPKCS11Unavailable
+}
+
+func (*PKCS11) SignHash(context.Context, common.Hash) ([]byte, error) {
+	return nil, ErrPKCS11Unavailable
+}
+
+func (*PKCS11) Close() error { return nil }
@@ -0,0 +1,199 @@
+//go:build softhsm && cgo
+
+// Integration tests against SoftHSM v2. They need softhsm2-util on PATH and
+// the module, found in the usual places or at SOFTHSM2_MODULE:
+//
+//	go test -tags softhsm ./pkg/signer
+package signer
+
+import (
+	"context"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/miekg/pkcs11"
+)
+
+const (
+	softhsmToken = "performer-test"
+	softhsmPIN   = "1234"
+	softhsmKey   = "operator"
+)
+
+var softhsmModules = []string{
+	"/usr/lib/softhsm/libsofthsm2.so",
+	"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+	"/usr/lib/aarch64-linux-gnu/softhsm/libsofthsm2.so",
+	"/usr/local/lib/softhsm/libsofthsm2.so",
+	"/opt/homebrew/lib/softhsm/libsofthsm2.so",
+}
+
+// newSoftHSM initializes a fresh token in a temporary directory and returns
+// the module path.
+func newSoftHSM(t *testing.T) string {
+	t.Helper()
+
+	module := os.Getenv("SOFTHSM2_MODULE")
+	for _, m := range softhsmModules {
+		if module != "" {
+			break
+		}
+		if _, err := os.Stat(m); err == nil {
+			module = m
+		}
+	}
+	if module == "" {
+		t.Fatal("libsofthsm2.so not found; install SoftHSM v2 or set SOFTHSM2_MODULE")
+	}
+
+	dir := t.TempDir()
+	tokens := filepath.Join(dir, "tokens")
+	if err := os.Mkdir(tokens, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	conf := filepath.Join(dir, "softhsm2.conf")
+	if err := os.WriteFile(conf, []byte(fmt.Sprintf("directories.tokendir = %s\nobjectstore.backend = file\n", tokens)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SOFTHSM2_CONF", conf)
+
+	out, err := exec.Command("softhsm2-util", "--init-token", "--free",
+		"--label", softhsmToken, "--pin", softhsmPIN, "--so-pin", "5678").CombinedOutput()
+	if err != nil {
+		t.Fatalf("softhsm2-util --init-token failed: %v\n%s", err, out)
+	}
+	return module
+}
+
+// generateKeyPair creates a non-extractable secp256k1 key pair labelled
+// label on the token.
+func generateKeyPair(t *testing.T, module, label string) {
+	t.Helper()
+
+	p11 := pkcs11.New(module)
+	if err := p11.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer func() {
+		_ = p11.Finalize()
+		p11.Destroy()
+	}()
+
+	p := &PKCS11{ctx: p11}
+	slot, err := p.findSlot(softhsmToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := p11.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		t.Fatalf("OpenSession failed: %v", err)
+	}
+	defer func() { _ = p11.CloseSession(session) }()
+	if err := p11.Login(session, pkcs11.CKU_USER, softhsmPIN); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	params, err := asn1.Marshal(oidSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = p11.GenerateKeyPair(session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, params),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		},
+	)
+	if err != nil {
+		t.Fatalf("GenerateKeyPair failed: %v", err)
+	}
+}
+
+func Test_PKCS11SoftHSM(t *testing.T) {
+	module := newSoftHSM(t)
+	generateKeyPair(t, module, softhsmKey)
+
+	s, err := NewPKCS11(PKCS11Config{Module: module, TokenLabel: softhsmToken, KeyLabel: softhsmKey, PIN: softhsmPIN})
+	if err != nil {
+		t.Fatalf("NewPKCS11 failed: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	ctx := context.Background()
+
+	// Roughly half of raw ECDSA signatures have a high S and either recovery
+	// id, so sign enough hashes to see both.
+	for i := 0; i < 32; i++ {
+		hash := crypto.Keccak256Hash([]byte{byte(i)})
+		sig, err := s.SignHash(ctx, hash)
+		if err != nil {
+			t.Fatalf("SignHash failed: %v", err)
+		}
+		if !crypto.ValidateSignatureValues(sig[64], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]), true) {
+			t.Fatalf("signature %x is not a valid low-S signature", sig)
+		}
+		pub, err := crypto.SigToPub(hash[:], sig)
+		if err != nil || crypto.PubkeyToAddress(*pub) != s.Address() {
+			t.Fatalf("signature %x does not recover to %s", sig, s.Address().Hex())
+		}
+	}
+
+	tx := testTx(5)
+	signed, err := s.SignTx(ctx, tx, testChainID)
+	if err != nil {
+		t.Fatalf("SignTx failed: %v", err)
+	}
+	if err := checkSignedTx(tx, signed, testChainID, s.Address()); err != nil {
+		t.Errorf("checkSignedTx: %v", err)
+	}
+	auth := NewTransactor(ctx, s, testChainID)
+	if signed, err := auth.Signer(auth.From, testTx(6)); err != nil {
+		t.Errorf("transactor Signer failed: %v", err)
+	} else if from, _ := types.Sender(types.LatestSignerForChainID(testChainID), signed); from != s.Address() {
+		t.Errorf("sender = %s, want %s", from.Hex(), s.Address().Hex())
+	}
+}
+
+func Test_PKCS11SoftHSMErrors(t *testing.T) {
+	module := newSoftHSM(t)
+	generateKeyPair(t, module, softhsmKey)
+
+	tests := []struct {
+		name string
+		cfg  PKCS11Config
+		want error
+	}{
+		{"wrong pin", PKCS11Config{Module: module, TokenLabel: softhsmToken, KeyLabel: softhsmKey, PIN: "0000"}, pkcs11.Error(pkcs11.CKR_PIN_INCORRECT)},
+		{"unknown token", PKCS11Config{Module: module, TokenLabel: "missing", KeyLabel: softhsmKey, PIN: softhsmPIN}, nil},
+		{"unknown key", PKCS11Config{Module: module, TokenLabel: softhsmToken, KeyLabel: "missing", PIN: softhsmPIN}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewPKCS11(tt.cfg)
+			if err == nil {
+				_ = s.Close()
+				t.Fatal("expected an error")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package signer
+
+// PKCS11Config locates a secp256k1 key pair on a PKCS#11 token.
+type PKCS11Config struct {
+	// Module is the path of the PKCS#11 library, e.g. libsofthsm2.so.
+	Module string
+	// TokenLabel selects the slot whose token has this label.
+	TokenLabel string
+	// KeyLabel is the CKA_LABEL of the private and public key objects.
+	KeyLabel string
+	// PIN logs in as the token's user.
+	PIN string
+}
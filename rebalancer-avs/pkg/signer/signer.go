@@ -0,0 +1,124 @@
+// Package signer signs the performer's transactions.
+//
+// A Signer either holds the operator key in process (Local), asks a remote
+// signing service for every signature (Web3Signer) or has an HSM sign through
+// PKCS#11 (PKCS11), so that the key does not have to be inside the performer
+// at all.
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ErrWrongSigner is returned when a signature does not recover to the
+	// signer's address.
+	ErrWrongSigner = errors.New("signature does not recover to the signer address")
+	// ErrTxModified is returned when a signed transaction is not the one that
+	// was sent for signing.
+	ErrTxModified = errors.New("signed transaction differs from the request")
+)
+
+// Signer signs for one account.
+type Signer interface {
+	// Address is the account the signer signs for.
+	Address() common.Address
+	// SignTx returns tx signed for chainID.
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// HashSigner is a Signer that can also sign a prehashed digest. Local and
+// PKCS11 implement it; Web3Signer only signs transactions.
+type HashSigner interface {
+	Signer
+	// SignHash returns a 65-byte [R || S || V] signature of hash, V being 0
+	// or 1, as crypto.Sign does.
+	SignHash(ctx context.Context, hash common.Hash) ([]byte, error)
+}
+
+// NewTransactor returns TransactOpts that sign with s. ctx is used for every
+// signature made through the returned Signer, replacements included.
+func NewTransactor(ctx context.Context, s Signer, chainID *big.Int) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From:    s.Address(),
+		Signer:  SignerFn(ctx, s, chainID),
+		Context: ctx,
+	}
+}
+
+// SignerFn adapts s to the bind.SignerFn the contract bindings call.
+func SignerFn(ctx context.Context, s Signer, chainID *big.Int) bind.SignerFn {
+	return func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if from != s.Address() {
+			return nil, bind.ErrNotAuthorized
+		}
+		return s.SignTx(ctx, tx, chainID)
+	}
+}
+
+// checkSignedTx verifies that signed is tx, signed by want for chainID.
+func checkSignedTx(tx, signed *types.Transaction, chainID *big.Int, want common.Address) error {
+	s := types.LatestSignerForChainID(chainID)
+	if s.Hash(signed) != s.Hash(tx) {
+		return ErrTxModified
+	}
+	from, err := types.Sender(s, signed)
+	if err != nil {
+		return fmt.Errorf("invalid transaction signature: %w", err)
+	}
+	if from != want {
+		return fmt.Errorf("%w: signed by %s, want %s", ErrWrongSigner, from.Hex(), want.Hex())
+	}
+	return nil
+}
+
+// checkHashSignature normalizes a 27/28 V to 0/1 in place and verifies that
+// sig is a signature of hash by want.
+func checkHashSignature(hash common.Hash, sig []byte, want common.Address) error {
+	if len(sig) != crypto.SignatureLength {
+		return fmt.Errorf("signature is %d bytes, want %d", len(sig), crypto.SignatureLength)
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(hash[:], sig)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	if from := crypto.PubkeyToAddress(*pub); from != want {
+		return fmt.Errorf("%w: signed by %s, want %s", ErrWrongSigner, from.Hex(), want.Hex())
+	}
+	return nil
+}
+
+// Local signs with a private key held in memory.
+type Local struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewLocal returns a Signer for key.
+func NewLocal(key *ecdsa.PrivateKey) *Local {
+	return &Local{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+func (l *Local) Address() common.Address {
+	return l.address
+}
+
+func (l *Local) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), l.key)
+}
+
+func (l *Local) SignHash(_ context.Context, hash common.Hash) ([]byte, error) {
+	return crypto.Sign(hash[:], l.key)
+}
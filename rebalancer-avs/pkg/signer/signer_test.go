@@ -0,0 +1,103 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var testChainID = big.NewInt(31338)
+
+func testKey(t *testing.T) *Local {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	return NewLocal(key)
+}
+
+func testTx(nonce uint64) *types.Transaction {
+	to := common.HexToAddress("0x4444444444444444444444444444444444441540")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   testChainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(50e9),
+		Gas:       300_000,
+		To:        &to,
+		Data:      []byte{0xde, 0xad, 0xbe, 0xef},
+	})
+}
+
+func Test_LocalSigner(t *testing.T) {
+	s := testKey(t)
+	ctx := context.Background()
+
+	tx := testTx(7)
+	signed, err := s.SignTx(ctx, tx, testChainID)
+	if err != nil {
+		t.Fatalf("SignTx failed: %v", err)
+	}
+	if err := checkSignedTx(tx, signed, testChainID, s.Address()); err != nil {
+		t.Errorf("checkSignedTx: %v", err)
+	}
+
+	hash := crypto.Keccak256Hash([]byte("rebalance"))
+	sig, err := s.SignHash(ctx, hash)
+	if err != nil {
+		t.Fatalf("SignHash failed: %v", err)
+	}
+	if err := checkHashSignature(hash, sig, s.Address()); err != nil {
+		t.Errorf("checkHashSignature: %v", err)
+	}
+}
+
+func Test_NewTransactor(t *testing.T) {
+	s := testKey(t)
+	auth := NewTransactor(context.Background(), s, testChainID)
+	if auth.From != s.Address() {
+		t.Fatalf("From = %s, want %s", auth.From.Hex(), s.Address().Hex())
+	}
+
+	signed, err := auth.Signer(auth.From, testTx(1))
+	if err != nil {
+		t.Fatalf("Signer failed: %v", err)
+	}
+	if from, err := types.Sender(types.LatestSignerForChainID(testChainID), signed); err != nil || from != s.Address() {
+		t.Errorf("sender = %s, %v", from.Hex(), err)
+	}
+
+	if _, err := auth.Signer(common.Address{0x01}, testTx(1)); !errors.Is(err, bind.ErrNotAuthorized) {
+		t.Errorf("expected bind.ErrNotAuthorized for another address, got %v", err)
+	}
+}
+
+func Test_CheckSignedTx(t *testing.T) {
+	s, other := testKey(t), testKey(t)
+	ctx := context.Background()
+	tx := testTx(3)
+
+	byOther, err := other.SignTx(ctx, tx, testChainID)
+	if err != nil {
+		t.Fatalf("SignTx failed: %v", err)
+	}
+	if err := checkSignedTx(tx, byOther, testChainID, s.Address()); !errors.Is(err, ErrWrongSigner) {
+		t.Errorf("expected ErrWrongSigner, got %v", err)
+	}
+
+	modified, err := s.SignTx(ctx, testTx(4), testChainID)
+	if err != nil {
+		t.Fatalf("SignTx failed: %v", err)
+	}
+	if err := checkSignedTx(tx, modified, testChainID, s.Address()); !errors.Is(err, ErrTxModified) {
+		t.Errorf("expected ErrTxModified, got %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// defaultWeb3SignerTimeout bounds each request when Web3SignerConfig.Timeout is zero.
+const defaultWeb3SignerTimeout = 10 * time.Second
+
+// maxResponseSize caps how much of a Web3Signer response is read.
+const maxResponseSize = 1 << 20
+
+// RemoteError is an error reported by the signing service.
+type RemoteError struct {
+	// Code is the JSON-RPC error code, or the HTTP status.
+	Code    int
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf("web3signer error %d: %s", e.Code, e.Message)
+}
+
+// Web3SignerConfig configures a Web3Signer.
+type Web3SignerConfig struct {
+	// URL is the Web3Signer endpoint, e.g. https://web3signer:9000.
+	URL string
+	// Address is the account Web3Signer holds the key of.
+	Address common.Address
+	// CACert verifies the server; ClientCert and ClientKey enable mutual TLS.
+	// All are PEM and optional.
+	CACert     []byte
+	ClientCert []byte
+	ClientKey  []byte
+	// Timeout bounds each request.
+	Timeout time.Duration
+}
+
+// Web3Signer signs transactions through a Web3Signer-compatible service with
+// the eth_signTransaction JSON-RPC method. Every signature is checked against
+// Address before it is returned, so a misconfigured or compromised service
+// cannot make the performer send something it did not ask for.
+type Web3Signer struct {
+	url       string
+	address   common.Address
+	client    *http.Client
+	requestID atomic.Int64
+}
+
+// NewWeb3Signer returns a Signer backed by the service at cfg.URL.
+func NewWeb3Signer(cfg Web3SignerConfig) (*Web3Signer, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("web3signer URL not configured")
+	}
+	if cfg.Address == (common.Address{}) {
+		return nil, errors.New("web3signer address not configured")
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultWeb3SignerTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(cfg.CACert) > 0 || len(cfg.ClientCert) > 0 || len(cfg.ClientKey) > 0 {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if len(cfg.CACert) > 0 {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(cfg.CACert) {
+				return nil, errors.New("web3signer CA certificate is not valid PEM")
+			}
+			tlsConfig.RootCAs = pool
+		}
+		if len(cfg.ClientCert) > 0 || len(cfg.ClientKey) > 0 {
+			cert, err := tls.X509KeyPair(cfg.ClientCert, cfg.ClientKey)
+			if err != nil {
+				return nil, fmt.Errorf("invalid web3signer client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &Web3Signer{
+		url:     strings.TrimSuffix(cfg.URL, "/"),
+		address: cfg.Address,
+		client:  &http.Client{Timeout: timeout, Transport: transport},
+	}, nil
+}
+
+func (w *Web3Signer) Address() common.Address {
+	return w.address
+}
+
+// Accounts lists the accounts the service holds keys for (eth_accounts).
+func (w *Web3Signer) Accounts(ctx context.Context) ([]common.Address, error) {
+	var accounts []common.Address
+	if err := w.call(ctx, "eth_accounts", []interface{}{}, &accounts); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (w *Web3Signer) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := map[string]interface{}{
+		"from":    w.address,
+		"gas":     hexutil.Uint64(tx.Gas()),
+		"nonce":   hexutil.Uint64(tx.Nonce()),
+		"value":   (*hexutil.Big)(tx.Value()),
+		"data":    hexutil.Bytes(tx.Data()),
+		"chainId": (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		args["to"] = tx.To()
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args["gasPrice"] = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		if len(tx.AccessList()) > 0 {
+			return nil, errors.New("web3signer: access lists are not supported")
+		}
+		args["maxFeePerGas"] = (*hexutil.Big)(tx.GasFeeCap())
+		args["maxPriorityFeePerGas"] = (*hexutil.Big)(tx.GasTipCap())
+	default:
+		return nil, fmt.Errorf("web3signer: unsupported transaction type %d", tx.Type())
+	}
+
+	var raw hexutil.Bytes
+	if err := w.call(ctx, "eth_signTransaction", []interface{}{args}, &raw); err != nil {
+		return nil, err
+	}
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("web3signer returned an invalid transaction: %w", err)
+	}
+	if err := checkSignedTx(tx, signed, chainID, w.address); err != nil {
+		return nil, fmt.Errorf("web3signer: %w", err)
+	}
+	return signed, nil
+}
+
+type jsonrpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int64       `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type jsonrpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (w *Web3Signer) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(jsonrpcRequest{JSONRPC: "2.0", ID: w.requestID.Add(1), Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	data, err := w.post(ctx, w.url, body)
+	if err != nil {
+		return err
+	}
+	var resp jsonrpcResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("web3signer %s: invalid response: %w", method, err)
+	}
+	if resp.Error != nil {
+		return &RemoteError{Code: resp.Error.Code, Message: resp.Error.Message}
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("web3signer %s: invalid result: %w", method, err)
+	}
+	return nil
+}
+
+func (w *Web3Signer) post(ctx context.Context, url string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("web3signer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read web3signer response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, &RemoteError{Code: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+	return data, nil
+}
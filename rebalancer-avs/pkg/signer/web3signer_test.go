@@ -0,0 +1,214 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// stubWeb3Signer is an in-process Web3Signer that signs with key.
+type stubWeb3Signer struct {
+	t   *testing.T
+	key *Local
+	// tamper, if set, rewrites each transaction before it is signed.
+	tamper func(*types.DynamicFeeTx)
+	// rpcError, if set, is returned for every JSON-RPC call.
+	rpcError string
+
+	requests []string
+}
+
+type stubTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Value                *hexutil.Big    `json:"value"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+}
+
+func (s *stubWeb3Signer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	ctx := context.Background()
+
+	var req struct {
+		ID     int64             `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.requests = append(s.requests, req.Method)
+	reply := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	switch {
+	case s.rpcError != "":
+		reply["error"] = map[string]interface{}{"code": -32000, "message": s.rpcError}
+	case req.Method == "eth_accounts":
+		reply["result"] = []common.Address{s.key.Address()}
+	case req.Method == "eth_signTransaction":
+		var args stubTxArgs
+		if err := json.Unmarshal(req.Params[0], &args); err != nil {
+			s.t.Errorf("bad eth_signTransaction params: %v", err)
+			return
+		}
+		if args.From != s.key.Address() {
+			reply["error"] = map[string]interface{}{"code": -32000, "message": "Signing not supported for the given from"}
+			break
+		}
+		inner := &types.DynamicFeeTx{
+			ChainID:   (*big.Int)(args.ChainID),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: (*big.Int)(args.MaxPriorityFeePerGas),
+			GasFeeCap: (*big.Int)(args.MaxFeePerGas),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     (*big.Int)(args.Value),
+			Data:      args.Data,
+		}
+		if s.tamper != nil {
+			s.tamper(inner)
+		}
+		signed, err := s.key.SignTx(ctx, types.NewTx(inner), inner.ChainID)
+		if err != nil {
+			s.t.Errorf("stub SignTx failed: %v", err)
+			return
+		}
+		raw, _ := signed.MarshalBinary()
+		reply["result"] = hexutil.Bytes(raw)
+	default:
+		reply["error"] = map[string]interface{}{"code": -32601, "message": "Method not found"}
+	}
+	_ = json.NewEncoder(w).Encode(reply)
+}
+
+func newStubWeb3Signer(t *testing.T) (*stubWeb3Signer, *httptest.Server) {
+	t.Helper()
+
+	stub := &stubWeb3Signer{t: t, key: testKey(t)}
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+	return stub, srv
+}
+
+func newTestWeb3Signer(t *testing.T, stub *stubWeb3Signer, url string) *Web3Signer {
+	t.Helper()
+
+	w, err := NewWeb3Signer(Web3SignerConfig{
+		URL:     url,
+		Address: stub.key.Address(),
+	})
+	if err != nil {
+		t.Fatalf("NewWeb3Signer failed: %v", err)
+	}
+	return w
+}
+
+func Test_Web3Signer(t *testing.T) {
+	stub, srv := newStubWeb3Signer(t)
+	w := newTestWeb3Signer(t, stub, srv.URL)
+	ctx := context.Background()
+
+	accounts, err := w.Accounts(ctx)
+	if err != nil || len(accounts) != 1 || accounts[0] != stub.key.Address() {
+		t.Fatalf("Accounts = %v, %v", accounts, err)
+	}
+
+	tx := testTx(9)
+	signed, err := w.SignTx(ctx, tx, testChainID)
+	if err != nil {
+		t.Fatalf("SignTx failed: %v", err)
+	}
+	if from, err := types.Sender(types.LatestSignerForChainID(testChainID), signed); err != nil || from != stub.key.Address() {
+		t.Errorf("sender = %s, %v", from.Hex(), err)
+	}
+	if signed.Nonce() != 9 || signed.GasFeeCap().Cmp(tx.GasFeeCap()) != 0 || signed.To() == nil || *signed.To() != *tx.To() {
+		t.Errorf("signed tx does not match request: %+v", signed)
+	}
+
+	// Transactors built on a remote signer sign through it too.
+	auth := NewTransactor(ctx, w, testChainID)
+	if _, err := auth.Signer(auth.From, testTx(10)); err != nil {
+		t.Errorf("transactor Signer failed: %v", err)
+	}
+
+	// Web3Signer cannot sign a prehashed digest.
+	if _, ok := Signer(w).(HashSigner); ok {
+		t.Error("Web3Signer implements HashSigner")
+	}
+	if got := strings.Join(stub.requests, ","); got != "eth_accounts,eth_signTransaction,eth_signTransaction" {
+		t.Errorf("requests = %s", got)
+	}
+}
+
+func Test_Web3SignerRejectsBadSignatures(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("modified transaction", func(t *testing.T) {
+		stub, srv := newStubWeb3Signer(t)
+		stub.tamper = func(tx *types.DynamicFeeTx) { tx.To = &common.Address{0x01} }
+		if _, err := newTestWeb3Signer(t, stub, srv.URL).SignTx(ctx, testTx(1), testChainID); !errors.Is(err, ErrTxModified) {
+			t.Errorf("expected ErrTxModified, got %v", err)
+		}
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		stub, srv := newStubWeb3Signer(t)
+		w := newTestWeb3Signer(t, stub, srv.URL)
+		stub.key = testKey(t)
+		// The stub now refuses the configured address, like Web3Signer would.
+		var remote *RemoteError
+		if _, err := w.SignTx(ctx, testTx(1), testChainID); !errors.As(err, &remote) {
+			t.Errorf("expected *RemoteError, got %v", err)
+		}
+	})
+
+	t.Run("rpc error", func(t *testing.T) {
+		stub, srv := newStubWeb3Signer(t)
+		stub.rpcError = "locked"
+		_, err := newTestWeb3Signer(t, stub, srv.URL).SignTx(ctx, testTx(1), testChainID)
+		var remote *RemoteError
+		if !errors.As(err, &remote) || remote.Message != "locked" {
+			t.Errorf("expected RemoteError locked, got %v", err)
+		}
+	})
+}
+
+func Test_Web3SignerTLS(t *testing.T) {
+	stub := &stubWeb3Signer{t: t, key: testKey(t)}
+	srv := httptest.NewTLSServer(stub)
+	t.Cleanup(srv.Close)
+
+	// Without the server's CA the handshake fails.
+	if _, err := newTestWeb3Signer(t, stub, srv.URL).Accounts(context.Background()); err == nil {
+		t.Fatal("expected a certificate error without the CA")
+	}
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	w, err := NewWeb3Signer(Web3SignerConfig{URL: srv.URL, Address: stub.key.Address(), CACert: ca})
+	if err != nil {
+		t.Fatalf("NewWeb3Signer failed: %v", err)
+	}
+	if _, err := w.Accounts(context.Background()); err != nil {
+		t.Errorf("Accounts over TLS failed: %v", err)
+	}
+
+	if _, err := NewWeb3Signer(Web3SignerConfig{URL: srv.URL, Address: stub.key.Address(), CACert: []byte("not pem")}); err == nil {
+		t.Error("expected an error for an invalid CA")
+	}
+}
@@ -0,0 +1,331 @@
+// Package taskresult encodes and decodes the performer's TaskResponse.Result.
+//
+// The result is ABI-encoded so that AVSTaskHook.validatePreTaskResultSubmission
+// can abi.decode it on-chain, and it starts with a version word so the layout
+// can evolve without breaking verifiers.
+package taskresult
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// VersionV1 is the first result layout:
+//
+//	abi.encode(
+//	    uint8   version,
+//	    bytes32 poolId,
+//	    bytes32 taskId,
+//	    int24   tickShift,
+//	    uint256 referenceBlockNumber,
+//	    bytes32 referenceBlockHash,
+//	    uint256 positionsRebalanced
+//	)
+//
+// Results of this version are still decoded, but no longer encoded.
+const VersionV1 uint8 = 1
+
+// VersionV2 appends the per-position plan to the V1 layout:
+//
+//	abi.encode(
+//	    uint8   version,
+//	    bytes32 poolId,
+//	    bytes32 taskId,
+//	    int24   tickShift,
+//	    uint256 referenceBlockNumber,
+//	    bytes32 referenceBlockHash,
+//	    uint256 positionsRebalanced,
+//	    (address owner, uint128 liquidity, int24 tickLower, int24 tickUpper,
+//	     int24 targetTickLower, int24 targetTickUpper, string skip)[] positions
+//	)
+//
+// It matches AVSTaskHook.RebalanceResult and is the layout Encode writes.
+const VersionV2 uint8 = 2
+
+// Bounds of an int24, the type of tickShift and of every tick.
+const (
+	minInt24 = -1 << 23
+	maxInt24 = 1<<23 - 1
+)
+
+var (
+	// ErrUnsupportedVersion is returned when the result version word is unknown.
+	ErrUnsupportedVersion = errors.New("unsupported result version")
+	// ErrMalformed is returned when the result does not match the declared layout.
+	ErrMalformed = errors.New("malformed result")
+)
+
+// Error reports a result that could not be encoded or decoded.
+type Error struct {
+	Version uint8
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid task result (version %d): %v", e.Version, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+var (
+	uint8Type, _   = abi.NewType("uint8", "", nil)
+	int24Type, _   = abi.NewType("int24", "", nil)
+	uint256Type, _ = abi.NewType("uint256", "", nil)
+	bytes32Type, _ = abi.NewType("bytes32", "", nil)
+
+	positionsType, _ = abi.NewType("tuple[]", "", []abi.ArgumentMarshaling{
+		{Name: "owner", Type: "address"},
+		{Name: "liquidity", Type: "uint128"},
+		{Name: "tickLower", Type: "int24"},
+		{Name: "tickUpper", Type: "int24"},
+		{Name: "targetTickLower", Type: "int24"},
+		{Name: "targetTickUpper", Type: "int24"},
+		{Name: "skip", Type: "string"},
+	})
+
+	resultV1Args = abi.Arguments{
+		{Name: "version", Type: uint8Type},
+		{Name: "poolId", Type: bytes32Type},
+		{Name: "taskId", Type: bytes32Type},
+		{Name: "tickShift", Type: int24Type},
+		{Name: "referenceBlockNumber", Type: uint256Type},
+		{Name: "referenceBlockHash", Type: bytes32Type},
+		{Name: "positionsRebalanced", Type: uint256Type},
+	}
+
+	resultV2Args = append(resultV1Args[:len(resultV1Args):len(resultV1Args)],
+		abi.Argument{Name: "positions", Type: positionsType})
+)
+
+// positionTuple is the ABI form of a Position.
+type positionTuple struct {
+	Owner           common.Address
+	Liquidity       *big.Int
+	TickLower       *big.Int
+	TickUpper       *big.Int
+	TargetTickLower *big.Int
+	TargetTickUpper *big.Int
+	Skip            string
+}
+
+// Position is the planned target range of one hook position, in the order
+// getPositions returns them.
+type Position struct {
+	Owner           common.Address
+	Liquidity       *big.Int
+	TickLower       int32
+	TickUpper       int32
+	TargetTickLower int32
+	TargetTickUpper int32
+	// Skip is why the plan leaves the position in place, empty when it moves.
+	Skip string
+}
+
+// Result is the outcome of one rebalance task.
+type Result struct {
+	Version uint8
+	PoolId  common.Hash
+	// TaskId is the TaskMailbox task hash the result answers.
+	TaskId    common.Hash
+	TickShift int32
+	// ReferenceBlockNumber and ReferenceBlockHash identify the L2 block of
+	// the task's RebalanceRequested event, where the pool state and positions
+	// were read.
+	ReferenceBlockNumber uint64
+	ReferenceBlockHash   common.Hash
+	// PositionsRebalanced is the number of positions the plan expects
+	// executeRebalance to move.
+	PositionsRebalanced uint64
+	// Positions is the plan for every position of the pool. V1 results have
+	// none.
+	Positions []Position
+}
+
+// Encode ABI-encodes r using the V2 layout. r.Version is ignored.
+func Encode(r *Result) ([]byte, error) {
+	if r.TickShift < minInt24 || r.TickShift > maxInt24 {
+		return nil, &Error{
+			Version: VersionV2,
+			Err:     fmt.Errorf("%w: tickShift %d overflows int24", ErrMalformed, r.TickShift),
+		}
+	}
+
+	positions := make([]positionTuple, len(r.Positions))
+	for i, p := range r.Positions {
+		if p.Liquidity == nil || p.Liquidity.Sign() < 0 || p.Liquidity.BitLen() > 128 {
+			return nil, &Error{
+				Version: VersionV2,
+				Err:     fmt.Errorf("%w: position %d liquidity %v is not a uint128", ErrMalformed, i, p.Liquidity),
+			}
+		}
+		for _, tick := range []int32{p.TickLower, p.TickUpper, p.TargetTickLower, p.TargetTickUpper} {
+			if tick < minInt24 || tick > maxInt24 {
+				return nil, &Error{
+					Version: VersionV2,
+					Err:     fmt.Errorf("%w: position %d tick %d overflows int24", ErrMalformed, i, tick),
+				}
+			}
+		}
+		positions[i] = positionTuple{
+			Owner:           p.Owner,
+			Liquidity:       p.Liquidity,
+			TickLower:       big.NewInt(int64(p.TickLower)),
+			TickUpper:       big.NewInt(int64(p.TickUpper)),
+			TargetTickLower: big.NewInt(int64(p.TargetTickLower)),
+			TargetTickUpper: big.NewInt(int64(p.TargetTickUpper)),
+			Skip:            p.Skip,
+		}
+	}
+
+	return resultV2Args.Pack(
+		VersionV2,
+		[32]byte(r.PoolId),
+		[32]byte(r.TaskId),
+		big.NewInt(int64(r.TickShift)),
+		new(big.Int).SetUint64(r.ReferenceBlockNumber),
+		[32]byte(r.ReferenceBlockHash),
+		new(big.Int).SetUint64(r.PositionsRebalanced),
+		positions,
+	)
+}
+
+// Decode decodes an encoded result. Any failure is returned as an *Error.
+func Decode(data []byte) (*Result, error) {
+	if len(data) < 32 {
+		return nil, &Error{Err: fmt.Errorf("%w: %d bytes is too short for a version word", ErrMalformed, len(data))}
+	}
+
+	version := new(big.Int).SetBytes(data[:32])
+	if !version.IsUint64() || version.Uint64() > math.MaxUint8 {
+		return nil, &Error{Err: fmt.Errorf("%w: version word %s", ErrMalformed, version)}
+	}
+
+	switch v := uint8(version.Uint64()); v {
+	case VersionV1:
+		return decodeV1(data)
+	case VersionV2:
+		return decodeV2(data)
+	default:
+		return nil, &Error{Version: v, Err: ErrUnsupportedVersion}
+	}
+}
+
+func decodeV1(data []byte) (*Result, error) {
+	if want := len(resultV1Args) * 32; len(data) != want {
+		return nil, &Error{
+			Version: VersionV1,
+			Err:     fmt.Errorf("%w: expected %d bytes, got %d", ErrMalformed, want, len(data)),
+		}
+	}
+
+	values, err := resultV1Args.Unpack(data)
+	if err != nil {
+		return nil, &Error{Version: VersionV1, Err: fmt.Errorf("%w: %v", ErrMalformed, err)}
+	}
+	return decodeHeader(VersionV1, values)
+}
+
+func decodeV2(data []byte) (*Result, error) {
+	values, err := resultV2Args.Unpack(data)
+	if err != nil {
+		return nil, &Error{Version: VersionV2, Err: fmt.Errorf("%w: %v", ErrMalformed, err)}
+	}
+	r, err := decodeHeader(VersionV2, values)
+	if err != nil {
+		return nil, err
+	}
+
+	tuples := *abi.ConvertType(values[7], new([]positionTuple)).(*[]positionTuple)
+	r.Positions = make([]Position, len(tuples))
+	for i, t := range tuples {
+		ticks := make([]int32, 4)
+		for j, tick := range []*big.Int{t.TickLower, t.TickUpper, t.TargetTickLower, t.TargetTickUpper} {
+			if ticks[j], err = decodeInt24(VersionV2, fmt.Sprintf("position %d tick", i), tick); err != nil {
+				return nil, err
+			}
+		}
+		if t.Liquidity.BitLen() > 128 {
+			return nil, &Error{
+				Version: VersionV2,
+				Err:     fmt.Errorf("%w: position %d liquidity %s overflows uint128", ErrMalformed, i, t.Liquidity),
+			}
+		}
+		r.Positions[i] = Position{
+			Owner:           t.Owner,
+			Liquidity:       t.Liquidity,
+			TickLower:       ticks[0],
+			TickUpper:       ticks[1],
+			TargetTickLower: ticks[2],
+			TargetTickUpper: ticks[3],
+			Skip:            t.Skip,
+		}
+	}
+
+	// The dynamic layout admits padding and out-of-order offsets the encoder
+	// never writes; accept only the canonical encoding so that every
+	// operator's result for a task is byte-identical.
+	canonical, err := Encode(r)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(canonical, data) {
+		return nil, &Error{Version: VersionV2, Err: fmt.Errorf("%w: non-canonical encoding", ErrMalformed)}
+	}
+	return r, nil
+}
+
+// decodeHeader decodes the fields V1 and V2 share.
+func decodeHeader(version uint8, values []interface{}) (*Result, error) {
+	// abi.decode in Solidity rejects words that are not sign-extended int24s;
+	// go-ethereum does not, so check the range here.
+	tickShift, err := decodeInt24(version, "tickShift", values[3].(*big.Int))
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Result{
+		Version:            version,
+		PoolId:             values[1].([32]byte),
+		TaskId:             values[2].([32]byte),
+		TickShift:          tickShift,
+		ReferenceBlockHash: values[5].([32]byte),
+	}
+
+	uint64Fields := []struct {
+		name  string
+		value *big.Int
+		dst   *uint64
+	}{
+		{"referenceBlockNumber", values[4].(*big.Int), &r.ReferenceBlockNumber},
+		{"positionsRebalanced", values[6].(*big.Int), &r.PositionsRebalanced},
+	}
+	for _, f := range uint64Fields {
+		if !f.value.IsUint64() {
+			return nil, &Error{
+				Version: version,
+				Err:     fmt.Errorf("%w: %s %s overflows uint64", ErrMalformed, f.name, f.value),
+			}
+		}
+		*f.dst = f.value.Uint64()
+	}
+
+	return r, nil
+}
+
+func decodeInt24(version uint8, name string, value *big.Int) (int32, error) {
+	if !value.IsInt64() || value.Int64() < minInt24 || value.Int64() > maxInt24 {
+		return 0, &Error{
+			Version: version,
+			Err:     fmt.Errorf("%w: %s %s overflows int24", ErrMalformed, name, value),
+		}
+	}
+	return int32(value.Int64()), nil
+}
@@ -0,0 +1,205 @@
+package taskresult
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_ResultRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		tickShift int32
+	}{
+		{"positive shift", 60},
+		{"negative shift", -960},
+		{"zero shift", 0},
+		{"int24 min", minInt24},
+		{"int24 max", maxInt24},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := &Result{
+				Version:              VersionV2,
+				PoolId:               common.Hash{0xaa, 0xbb},
+				TaskId:               common.Hash{0x01, 0x02},
+				TickShift:            tt.tickShift,
+				ReferenceBlockNumber: 1_234_567,
+				ReferenceBlockHash:   common.Hash{0xcc},
+				PositionsRebalanced:  1,
+				Positions: []Position{
+					{Owner: common.Address{0x01}, Liquidity: big.NewInt(1e18), TickLower: -120, TickUpper: 120, TargetTickLower: -60, TargetTickUpper: 180},
+					{Owner: common.Address{0x02}, Liquidity: new(big.Int), TickLower: minInt24, TickUpper: maxInt24, TargetTickLower: minInt24, TargetTickUpper: maxInt24, Skip: "empty"},
+				},
+			}
+
+			encoded, err := Encode(want)
+			if err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+
+			got, err := Decode(encoded)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			if !sameResult(got, want) {
+				t.Errorf("decoded %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+// sameResult compares results, liquidity by value.
+func sameResult(a, b *Result) bool {
+	if len(a.Positions) != len(b.Positions) {
+		return false
+	}
+	for i := range a.Positions {
+		if a.Positions[i].Liquidity.Cmp(b.Positions[i].Liquidity) != 0 {
+			return false
+		}
+	}
+	strip := func(r *Result) Result {
+		c := *r
+		c.Positions = make([]Position, len(r.Positions))
+		for i, p := range r.Positions {
+			p.Liquidity = nil
+			c.Positions[i] = p
+		}
+		return c
+	}
+	return reflect.DeepEqual(strip(a), strip(b))
+}
+
+func Test_EncodeLayout(t *testing.T) {
+	encoded, err := Encode(&Result{PoolId: common.Hash{0xaa}, TickShift: -1})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	if encoded[31] != VersionV2 {
+		t.Errorf("version word = %x, want %d", encoded[:32], VersionV2)
+	}
+	if encoded[32] != 0xaa {
+		t.Errorf("poolId word = %x", encoded[32:64])
+	}
+	// int24 -1 is sign-extended to a full word, as abi.encode does.
+	if !bytes.Equal(encoded[3*32:4*32], bytes.Repeat([]byte{0xff}, 32)) {
+		t.Errorf("tickShift word = %x, want all ones", encoded[3*32:4*32])
+	}
+	// The positions offset points past the eight head words at an empty array.
+	if offset := new(big.Int).SetBytes(encoded[7*32 : 8*32]); len(encoded) != 9*32 || offset.Uint64() != 8*32 {
+		t.Errorf("positions = %x, want offset 256 to an empty array", encoded[7*32:])
+	}
+}
+
+func Test_DecodeV1(t *testing.T) {
+	encoded, err := resultV1Args.Pack(
+		VersionV1,
+		[32]byte{0xaa},
+		[32]byte{0x01},
+		big.NewInt(-60),
+		big.NewInt(42),
+		[32]byte{0xcc},
+		big.NewInt(2),
+	)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	got, err := Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	want := &Result{
+		Version:              VersionV1,
+		PoolId:               common.Hash{0xaa},
+		TaskId:               common.Hash{0x01},
+		TickShift:            -60,
+		ReferenceBlockNumber: 42,
+		ReferenceBlockHash:   common.Hash{0xcc},
+		PositionsRebalanced:  2,
+	}
+	if !sameResult(got, want) {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+}
+
+func Test_EncodeErrors(t *testing.T) {
+	for _, shift := range []int32{maxInt24 + 1, minInt24 - 1} {
+		_, err := Encode(&Result{TickShift: shift})
+		if !errors.Is(err, ErrMalformed) {
+			t.Errorf("Encode(tickShift %d): expected ErrMalformed, got %v", shift, err)
+		}
+	}
+
+	positions := []Position{
+		{Liquidity: big.NewInt(1), TargetTickUpper: maxInt24 + 1},
+		{Liquidity: nil},
+		{Liquidity: new(big.Int).Lsh(big.NewInt(1), 128)},
+	}
+	for _, p := range positions {
+		_, err := Encode(&Result{Positions: []Position{p}})
+		if !errors.Is(err, ErrMalformed) {
+			t.Errorf("Encode(position %+v): expected ErrMalformed, got %v", p, err)
+		}
+	}
+}
+
+func Test_DecodeErrors(t *testing.T) {
+	valid, err := Encode(&Result{
+		TickShift:           60,
+		PositionsRebalanced: 1,
+		Positions:           []Position{{Liquidity: big.NewInt(1), TargetTickLower: 60, TargetTickUpper: 120}},
+	})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	unknownVersion := append([]byte(nil), valid...)
+	unknownVersion[31] = 3
+
+	overflow := append([]byte(nil), valid...)
+	overflow[4*32] = 0x01 // referenceBlockNumber high byte
+
+	badTick := append([]byte(nil), valid...)
+	badTick[3*32] = 0x01 // not a sign-extended int24
+
+	// The eight head words are followed by the array length and the offset
+	// of its one tuple, which starts at word 10; tickLower is its third word.
+	badPositionTick := append([]byte(nil), valid...)
+	badPositionTick[12*32] = 0x01
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"empty", nil, ErrMalformed},
+		{"decimal string", []byte("60"), ErrMalformed},
+		{"truncated", valid[:len(valid)-1], ErrMalformed},
+		{"trailing bytes", append(append([]byte(nil), valid...), 0x00), ErrMalformed},
+		{"unknown version", unknownVersion, ErrUnsupportedVersion},
+		{"uint64 overflow", overflow, ErrMalformed},
+		{"int24 overflow", badTick, ErrMalformed},
+		{"position int24 overflow", badPositionTick, ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.data)
+			var resultErr *Error
+			if !errors.As(err, &resultErr) {
+				t.Fatalf("expected *Error, got %v", err)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}